@@ -0,0 +1,134 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// combinedTimeLayout is the timestamp layout of the combined log format.
+const combinedTimeLayout = "02/Jan/2006:15:04:05 -0700"
+
+// AccessLogConfig controls what AccessLogHandler writes.
+type AccessLogConfig struct {
+	// Combined, if set, additionally receives each request as an Apache/NCSA
+	// combined log format line, for tools that only parse that format.
+	Combined io.Writer
+	// Clock supplies the request start times; defaults to the system clock.
+	Clock Clock
+}
+
+// AccessLogHandler returns an http.Handler that logs each request served by
+// next with its method, path, status, size, and duration, at Error for 5xx
+// responses and Info otherwise. It uses the logger of the request context when
+// an outer middleware such as RequestIDHandler has set one, and l otherwise.
+func AccessLogHandler(l Logger, config AccessLogConfig, next http.Handler) http.Handler {
+	var mu sync.Mutex
+	now := time.Now
+	if config.Clock != nil {
+		now = config.Clock.Now
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := now()
+		recorder := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(recorder, r)
+		duration := now().Sub(start)
+
+		fields := Fields{
+			"method":      r.Method,
+			"path":        r.URL.Path,
+			"status":      recorder.status,
+			"bytes":       recorder.bytes,
+			"duration_ms": float64(duration) / float64(time.Millisecond),
+			"remote_addr": r.RemoteAddr,
+			"user_agent":  r.UserAgent(),
+		}
+		if recorder.status >= http.StatusInternalServerError {
+			contextLogger(r.Context(), l).Error("http request", fields)
+		} else {
+			contextLogger(r.Context(), l).Info("http request", fields)
+		}
+
+		if config.Combined != nil {
+			line := combinedLine(r, start, recorder.status, recorder.bytes)
+			mu.Lock()
+			io.WriteString(config.Combined, line)
+			mu.Unlock()
+		}
+	})
+}
+
+// combinedLine formats a request in the combined log format:
+//
+//	host ident user [time] "request" status bytes "referer" "user-agent"
+func combinedLine(r *http.Request, start time.Time, status int, bytes int64) string {
+	host := r.RemoteAddr
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	user := "-"
+	if u, _, ok := r.BasicAuth(); ok && u != "" {
+		user = u
+	}
+	size := "-"
+	if bytes > 0 {
+		size = fmt.Sprint(bytes)
+	}
+	request := fmt.Sprintf("%s %s %s", r.Method, r.URL.RequestURI(), r.Proto)
+	return fmt.Sprintf("%s - %s [%s] \"%s\" %d %s \"%s\" \"%s\"\n",
+		orDash(host), combinedEscaper.Replace(user), start.Format(combinedTimeLayout),
+		combinedEscaper.Replace(request), status, size,
+		orDash(combinedEscaper.Replace(r.Referer())), orDash(combinedEscaper.Replace(r.UserAgent())))
+}
+
+// combinedEscaper escapes the quoted fields of a combined log line.
+var combinedEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`)
+
+// orDash returns s, or "-" if it is empty.
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// responseRecorder records the status and size of a response.
+type responseRecorder struct {
+	http.ResponseWriter
+	status      int
+	bytes       int64
+	wroteHeader bool
+}
+
+// WriteHeader records the status code.
+func (r *responseRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status = status
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Write records the number of bytes written.
+func (r *responseRecorder) Write(p []byte) (int, error) {
+	r.wroteHeader = true
+	n, err := r.ResponseWriter.Write(p)
+	r.bytes += int64(n)
+	return n, err
+}
+
+// Flush flushes the response if the underlying writer supports it.
+func (r *responseRecorder) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap returns the underlying writer, for http.ResponseController.
+func (r *responseRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
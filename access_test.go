@@ -0,0 +1,48 @@
+package logger
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestAccessLogHandler tests that requests are logged as entries and combined log lines.
+func TestAccessLogHandler(t *testing.T) {
+	observed, logs := Observe(Config{Level: InfoLevel})
+	combined := new(bytes.Buffer)
+	start := time.Date(2000, 10, 10, 13, 55, 36, 0, time.FixedZone("", -7*3600))
+	handler := AccessLogHandler(observed, AccessLogConfig{Combined: combined, Clock: fixedClock(start)}, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte("hello"))
+	}))
+
+	req := httptest.NewRequest("GET", "/apache_pb.gif?x=1", nil)
+	req.RemoteAddr = "127.0.0.1:5000"
+	req.SetBasicAuth("frank", "secret")
+	req.Header.Set("Referer", "http://www.example.com/start.html")
+	req.Header.Set("User-Agent", `Mozilla/4.08 "quoted"`)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/fail", nil))
+
+	expected := `127.0.0.1 - frank [10/Oct/2000:13:55:36 -0700] "GET /apache_pb.gif?x=1 HTTP/1.1" 200 5 "http://www.example.com/start.html" "Mozilla/4.08 \"quoted\""` + "\n" +
+		`192.0.2.1 - - [10/Oct/2000:13:55:36 -0700] "POST /fail HTTP/1.1" 500 - "-" "-"` + "\n"
+	if combined.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, combined.String())
+	}
+
+	entries := logs.All()
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(entries))
+	}
+	if entries[0].Level != InfoLevel || entries[0].Fields["status"] != 200 || entries[0].Fields["bytes"] != int64(5) {
+		t.Errorf("Expected an info entry for the 200 response, got %+v", entries[0])
+	}
+	if entries[1].Level != ErrorLevel || entries[1].Fields["status"] != 500 {
+		t.Errorf("Expected an error entry for the 500 response, got %+v", entries[1])
+	}
+}
@@ -0,0 +1,170 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// AlertConfig holds the configuration for an alerting hook.
+type AlertConfig struct {
+	// Level is the lowest level counted towards the threshold; nil counts
+	// ErrorLevel and above.
+	Level *Level
+	// Threshold is the number of counted entries within Window that fires an alert.
+	Threshold int
+	// Window is the sliding window over which entries are counted; defaults to one minute.
+	Window time.Duration
+	// Cooldown is the minimum time between alerts; defaults to Window.
+	Cooldown time.Duration
+	// Notify is called with each alert.
+	Notify func(Alert)
+	// WebhookURL, if set, receives each alert as a JSON POST request.
+	WebhookURL string
+	// Timeout bounds a webhook request; defaults to 10 seconds.
+	Timeout time.Duration
+	// ErrorOutput receives webhook failures; defaults to os.Stderr.
+	ErrorOutput io.Writer
+	// Context, if set, bounds the delivery of alerts: webhook requests in
+	// flight are canceled and no alert is delivered once it is canceled.
+	Context context.Context
+}
+
+// Alert describes a crossed error-rate threshold.
+type Alert struct {
+	// Count is the number of counted entries within the window that crossed
+	// the threshold, i.e. Threshold.
+	Count int `json:"count"`
+	// Window is the sliding window the entries were counted over.
+	Window time.Duration `json:"window"`
+	// Time is the time of the entry that crossed the threshold.
+	Time time.Time `json:"time"`
+	// Message is the message of the entry that crossed the threshold.
+	Message string `json:"message"`
+}
+
+// alerter counts entries over a sliding window.
+type alerter struct {
+	config AlertConfig
+	level  Level
+	client *http.Client
+	mu     sync.Mutex
+	// times is a ring of the times of the last Threshold counted entries,
+	// next the index of the oldest once it is full.
+	times []time.Time
+	next  int
+	last  time.Time
+}
+
+// NewAlertHook returns a Hook that fires an alert through Notify and the
+// webhook when at least Threshold entries at or above Level are logged within
+// Window, then stays quiet for Cooldown. Alerts are delivered on a separate
+// goroutine so that logging never waits on them.
+func NewAlertHook(config AlertConfig) Hook {
+	level := ErrorLevel
+	if config.Level != nil {
+		level = *config.Level
+	}
+	if config.Threshold <= 0 {
+		config.Threshold = 1
+	}
+	if config.Window <= 0 {
+		config.Window = time.Minute
+	}
+	if config.Cooldown <= 0 {
+		config.Cooldown = config.Window
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 10 * time.Second
+	}
+	if config.ErrorOutput == nil {
+		config.ErrorOutput = os.Stderr
+	}
+	if config.Context == nil {
+		config.Context = context.Background()
+	}
+	a := &alerter{config: config, level: level, client: &http.Client{}, times: make([]time.Time, 0, config.Threshold)}
+	return a.hook
+}
+
+// hook counts entry and fires an alert when the threshold is crossed.
+func (a *alerter) hook(entry *Entry) error {
+	if entry.Level < a.level {
+		return nil
+	}
+	alert, fire := a.record(entry)
+	if fire && a.config.Context.Err() == nil {
+		go a.notify(alert)
+	}
+	return nil
+}
+
+// record adds entry to the window and reports whether an alert should fire.
+func (a *alerter) record(entry *Entry) (Alert, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	now := entry.Time
+	if len(a.times) < a.config.Threshold {
+		a.times = append(a.times, now)
+	} else {
+		a.times[a.next] = now
+		a.next = (a.next + 1) % a.config.Threshold
+	}
+
+	// The threshold is crossed when the oldest of the last Threshold entries
+	// is still within the window.
+	if len(a.times) < a.config.Threshold || !a.times[a.next].After(now.Add(-a.config.Window)) {
+		return Alert{}, false
+	}
+	if !a.last.IsZero() && now.Sub(a.last) < a.config.Cooldown {
+		return Alert{}, false
+	}
+	a.last = now
+	return Alert{Count: a.config.Threshold, Window: a.config.Window, Time: now, Message: entry.Message}, true
+}
+
+// notify delivers alert to the callback and the webhook.
+func (a *alerter) notify(alert Alert) {
+	if a.config.Context.Err() != nil {
+		return
+	}
+	if a.config.Notify != nil {
+		a.config.Notify(alert)
+	}
+	if a.config.WebhookURL != "" {
+		if err := a.post(alert); err != nil {
+			fmt.Fprintf(a.config.ErrorOutput, "logger: alert webhook failed: %v\n", err)
+		}
+	}
+}
+
+// post sends alert to the webhook as JSON.
+func (a *alerter) post(alert Alert) error {
+	body, err := json.Marshal(alert)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(a.config.Context, a.config.Timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.config.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
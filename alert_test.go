@@ -0,0 +1,139 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestAlertHook tests that an alert fires once the threshold is crossed and respects the cooldown.
+func TestAlertHook(t *testing.T) {
+	alerts := make(chan Alert, 10)
+	hook := NewAlertHook(AlertConfig{
+		Threshold: 3,
+		Window:    time.Minute,
+		Cooldown:  5 * time.Minute,
+		Notify:    func(a Alert) { alerts <- a },
+	})
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	send := func(level Level, offset time.Duration) {
+		if err := hook(&Entry{Level: level, Time: start.Add(offset), Message: "failed"}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	send(ErrorLevel, 0)
+	send(WarnLevel, time.Second)
+	send(ErrorLevel, 2*time.Minute)
+	send(ErrorLevel, 2*time.Minute+time.Second)
+	select {
+	case a := <-alerts:
+		t.Fatalf("Expected no alert outside the window, got %+v", a)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	send(ErrorLevel, 2*time.Minute+2*time.Second)
+	select {
+	case a := <-alerts:
+		if a.Count != 3 || a.Message != "failed" {
+			t.Errorf("Expected an alert for 3 entries, got %+v", a)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Expected an alert")
+	}
+
+	send(ErrorLevel, 3*time.Minute)
+	select {
+	case a := <-alerts:
+		t.Errorf("Expected no alert during the cooldown, got %+v", a)
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+// TestAlertHook_DebugLevel tests that debug entries can be counted when configured.
+func TestAlertHook_DebugLevel(t *testing.T) {
+	alerts := make(chan Alert, 10)
+	level := DebugLevel
+	hook := NewAlertHook(AlertConfig{Level: &level, Threshold: 2, Notify: func(a Alert) { alerts <- a }})
+
+	now := time.Now()
+	hook(&Entry{Level: DebugLevel, Time: now, Message: "noisy"})
+	hook(&Entry{Level: DebugLevel, Time: now.Add(time.Second), Message: "noisy"})
+	select {
+	case a := <-alerts:
+		if a.Count != 2 {
+			t.Errorf("Expected an alert for 2 entries, got %+v", a)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Expected an alert for debug entries")
+	}
+
+	defaults := NewAlertHook(AlertConfig{Threshold: 1, Notify: func(a Alert) { alerts <- a }})
+	defaults(&Entry{Level: WarnLevel, Time: now, Message: "warn"})
+	select {
+	case a := <-alerts:
+		t.Errorf("Expected warnings to be ignored by default, got %+v", a)
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+// TestAlertHook_Bounded tests that the hook keeps at most Threshold entry times.
+func TestAlertHook_Bounded(t *testing.T) {
+	a := &alerter{config: AlertConfig{Threshold: 3, Window: time.Minute, Cooldown: time.Minute}}
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	fired := 0
+	for i := 0; i < 100; i++ {
+		if _, fire := a.record(&Entry{Time: start.Add(time.Duration(i) * time.Hour)}); fire {
+			fired++
+		}
+	}
+	if len(a.times) != 3 || fired != 0 {
+		t.Errorf("Expected 3 kept times and no alert, got %d and %d alerts", len(a.times), fired)
+	}
+}
+
+// TestAlertHook_Webhook tests that alerts are posted to the webhook as JSON.
+func TestAlertHook_Webhook(t *testing.T) {
+	received := make(chan Alert, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var a Alert
+		if err := json.NewDecoder(r.Body).Decode(&a); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+		received <- a
+	}))
+	defer server.Close()
+
+	zapLogger := NewZap(Config{Level: InfoLevel, Output: new(bytes.Buffer)})
+	zapLogger.AddHook(NewAlertHook(AlertConfig{Threshold: 1, WebhookURL: server.URL}))
+	zapLogger.Error("Error message", nil)
+
+	select {
+	case a := <-received:
+		if a.Count != 1 || a.Message != "Error message" {
+			t.Errorf("Expected the alert to describe the entry, got %+v", a)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Expected a webhook request")
+	}
+}
+
+// TestAlertHook_Context tests that no alert is delivered once the context is canceled.
+func TestAlertHook_Context(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	alerts := make(chan Alert, 10)
+	hook := NewAlertHook(AlertConfig{Notify: func(a Alert) { alerts <- a }, Context: ctx})
+	cancel()
+
+	hook(&Entry{Level: ErrorLevel, Time: time.Now(), Message: "failed"})
+	select {
+	case a := <-alerts:
+		t.Errorf("Expected no alert after the context is canceled, got %+v", a)
+	case <-time.After(20 * time.Millisecond):
+	}
+}
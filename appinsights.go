@@ -0,0 +1,337 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// appInsightsDefaultEndpoint is the public ingestion endpoint used when the
+// connection string does not name one.
+const appInsightsDefaultEndpoint = "https://dc.services.visualstudio.com/"
+
+// AppInsightsTransport delivers envelopes to Application Insights. The built-in
+// transport posts them to the ingestion endpoint's v2/track API.
+type AppInsightsTransport interface {
+	Track(ctx context.Context, envelopes []AppInsightsEnvelope) error
+}
+
+// AppInsightsConfig holds the configuration for the Application Insights exporter.
+type AppInsightsConfig struct {
+	// ConnectionString is the resource's connection string, e.g.
+	// InstrumentationKey=...;IngestionEndpoint=https://...
+	ConnectionString string
+	// InstrumentationKey is used when ConnectionString is empty.
+	InstrumentationKey string
+	// OperationIDKey is the field used as the operation ID that correlates
+	// telemetry across services; defaults to correlation_id.
+	OperationIDKey string
+	// BatchSize is the number of envelopes buffered before an export; defaults to 1.
+	BatchSize int
+	// MaxBuffered caps the envelopes kept for the next export after failed
+	// exports; the oldest are dropped beyond it. Defaults to 100 times BatchSize.
+	MaxBuffered int
+	// Timeout bounds a single export; defaults to 10 seconds.
+	Timeout time.Duration
+	// Transport overrides the default HTTP transport.
+	Transport AppInsightsTransport
+	// Severities overrides the severity levels reported for levels.
+	Severities map[Level]int
+	// KeyNames are the keys of the core entry fields; set it to the
+	// Config.KeyNames of the logger writing to the exporter.
+	KeyNames KeyNames
+	// OnError is called when an export triggered by Write fails. The
+	// envelopes stay buffered for the next export, and Health reports the failure.
+	OnError func(error)
+}
+
+// AppInsightsExporter is an output that sends entries to Application Insights
+// as traces, or as exceptions for error entries with an error field. It
+// expects the JSON entries written by Zap.
+type AppInsightsExporter struct {
+	config  AppInsightsConfig
+	iKey    string
+	mu      sync.Mutex
+	batch   []AppInsightsEnvelope
+	lastErr error
+}
+
+// NewAppInsightsExporterE returns a new *AppInsightsExporter, or an error if no
+// instrumentation key is configured.
+func NewAppInsightsExporterE(config AppInsightsConfig) (*AppInsightsExporter, error) {
+	if iKey, _ := parseConnectionString(config); iKey == "" {
+		return nil, errors.New("appinsights: instrumentation key is not configured")
+	}
+	return NewAppInsightsExporter(config), nil
+}
+
+// MustAppInsightsExporter is like NewAppInsightsExporterE but panics on error.
+func MustAppInsightsExporter(config AppInsightsConfig) *AppInsightsExporter {
+	e, err := NewAppInsightsExporterE(config)
+	if err != nil {
+		panic(err)
+	}
+	return e
+}
+
+// NewAppInsightsExporter returns a new *AppInsightsExporter. A missing
+// instrumentation key is only detected on the first export; use
+// NewAppInsightsExporterE to check it up front.
+func NewAppInsightsExporter(config AppInsightsConfig) *AppInsightsExporter {
+	if config.BatchSize <= 0 {
+		config.BatchSize = 1
+	}
+	if config.MaxBuffered <= 0 {
+		config.MaxBuffered = 100 * config.BatchSize
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 10 * time.Second
+	}
+	if config.OperationIDKey == "" {
+		config.OperationIDKey = "correlation_id"
+	}
+	iKey, endpoint := parseConnectionString(config)
+	if config.Transport == nil {
+		config.Transport = &appInsightsHTTPTransport{
+			endpoint: strings.TrimSuffix(endpoint, "/") + "/v2/track",
+			client:   &http.Client{},
+		}
+	}
+	return &AppInsightsExporter{config: config, iKey: iKey}
+}
+
+// parseConnectionString returns the instrumentation key and ingestion endpoint
+// of the configuration.
+func parseConnectionString(config AppInsightsConfig) (iKey, endpoint string) {
+	iKey, endpoint = config.InstrumentationKey, appInsightsDefaultEndpoint
+	for _, part := range strings.Split(config.ConnectionString, ";") {
+		key, value, _ := strings.Cut(part, "=")
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "instrumentationkey":
+			iKey = strings.TrimSpace(value)
+		case "ingestionendpoint":
+			endpoint = strings.TrimSpace(value)
+		}
+	}
+	return iKey, endpoint
+}
+
+// Write decodes a single JSON entry and queues it for export. Once queued the
+// entry is accepted, even if the export it triggers fails, so that wrappers
+// such as RetryWriter do not queue it twice.
+func (e *AppInsightsExporter) Write(p []byte) (int, error) {
+	if e.iKey == "" {
+		return 0, errors.New("appinsights: instrumentation key is not configured")
+	}
+	envelope, err := e.envelopeFromJSON(p)
+	if err != nil {
+		return 0, err
+	}
+
+	e.mu.Lock()
+	e.batch = append(e.batch, envelope)
+	full := len(e.batch) >= e.config.BatchSize
+	e.mu.Unlock()
+
+	if full {
+		if err := e.Sync(); err != nil && e.config.OnError != nil {
+			e.config.OnError(err)
+		}
+	}
+	return len(p), nil
+}
+
+// Sync exports any buffered envelopes. If the export fails, the envelopes are
+// kept for the next export, up to MaxBuffered.
+func (e *AppInsightsExporter) Sync() error {
+	if e.iKey == "" {
+		return errors.New("appinsights: instrumentation key is not configured")
+	}
+
+	e.mu.Lock()
+	batch := e.batch
+	e.batch = nil
+	e.mu.Unlock()
+
+	if len(batch) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), e.config.Timeout)
+	defer cancel()
+	err := e.config.Transport.Track(ctx, batch)
+	e.mu.Lock()
+	e.lastErr = err
+	if err != nil {
+		e.batch = append(batch, e.batch...)
+		if over := len(e.batch) - e.config.MaxBuffered; over > 0 {
+			e.batch = e.batch[over:]
+		}
+	}
+	e.mu.Unlock()
+	return err
+}
+
+// Health returns an error if no instrumentation key is configured or the most
+// recent export failed.
+func (e *AppInsightsExporter) Health(ctx context.Context) error {
+	if e.iKey == "" {
+		return errors.New("appinsights: instrumentation key is not configured")
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.lastErr
+}
+
+// Ping verifies the configuration and ingestion endpoint without tracking
+// anything: a custom transport is pinged if it implements Pinger, and the
+// HTTP endpoint is resolved and connected to.
+func (e *AppInsightsExporter) Ping(ctx context.Context) error {
+	if e.iKey == "" {
+		return errors.New("appinsights: instrumentation key is not configured")
+	}
+	switch t := e.config.Transport.(type) {
+	case Pinger:
+		return t.Ping(ctx)
+	case *appInsightsHTTPTransport:
+		if err := pingURL(ctx, t.endpoint); err != nil {
+			return fmt.Errorf("appinsights: ping: %w", err)
+		}
+	}
+	return nil
+}
+
+// AppInsightsEnvelope is the JSON form of an Application Insights telemetry item.
+type AppInsightsEnvelope struct {
+	Name string            `json:"name"`
+	Time string            `json:"time"`
+	IKey string            `json:"iKey"`
+	Tags map[string]string `json:"tags,omitempty"`
+	Data AppInsightsData   `json:"data"`
+}
+
+// AppInsightsData wraps the telemetry payload with its type.
+type AppInsightsData struct {
+	BaseType string              `json:"baseType"`
+	BaseData AppInsightsBaseData `json:"baseData"`
+}
+
+// AppInsightsBaseData is the payload of a MessageData or ExceptionData item.
+type AppInsightsBaseData struct {
+	Ver           int                    `json:"ver"`
+	Message       string                 `json:"message,omitempty"`
+	Exceptions    []AppInsightsException `json:"exceptions,omitempty"`
+	SeverityLevel int                    `json:"severityLevel"`
+	Properties    map[string]string      `json:"properties,omitempty"`
+}
+
+// AppInsightsException describes an exception in an ExceptionData item.
+type AppInsightsException struct {
+	TypeName     string `json:"typeName"`
+	Message      string `json:"message"`
+	HasFullStack bool   `json:"hasFullStack"`
+}
+
+// appInsightsSeverity maps the encoded level to an Application Insights severity level.
+var appInsightsSeverity = map[string]int{
+	"debug":  0,
+	"info":   1,
+	"warn":   2,
+	"error":  3,
+	"dpanic": 4,
+	"panic":  4,
+	"fatal":  4,
+}
+
+// envelopeFromJSON converts a JSON entry into a trace or exception envelope.
+func (e *AppInsightsExporter) envelopeFromJSON(p []byte) (AppInsightsEnvelope, error) {
+	entry, err := decodeEntry(p)
+	if err != nil {
+		return AppInsightsEnvelope{}, fmt.Errorf("appinsights: decode entry: %w", err)
+	}
+
+	core := takeEntryCore(entry, e.config.KeyNames)
+	level, msg := core.level, core.message
+	severity := mapSeverity(level, e.config.Severities, appInsightsSeverity)
+	isError := appInsightsSeverity[strings.ToLower(level)] >= appInsightsSeverity["error"]
+	t := core.time
+	if !core.hasTime {
+		t = time.Now()
+	}
+
+	envelope := AppInsightsEnvelope{
+		Time: t.UTC().Format(time.RFC3339Nano),
+		IKey: e.iKey,
+	}
+	if id, ok := entry[e.config.OperationIDKey].(string); ok && id != "" {
+		envelope.Tags = map[string]string{"ai.operation.id": id}
+	}
+
+	properties := make(map[string]string, len(entry))
+	for k, v := range entry {
+		if s, ok := v.(string); ok {
+			properties[k] = s
+			continue
+		}
+		raw, _ := json.Marshal(v)
+		properties[k] = string(raw)
+	}
+
+	itemPrefix := "Microsoft.ApplicationInsights." + strings.ReplaceAll(e.iKey, "-", "") + "."
+	if errMsg, ok := entry["error"].(string); ok && isError {
+		envelope.Name = itemPrefix + "Exception"
+		envelope.Data = AppInsightsData{BaseType: "ExceptionData", BaseData: AppInsightsBaseData{
+			Ver:           2,
+			Exceptions:    []AppInsightsException{{TypeName: "error", Message: msg + ": " + errMsg}},
+			SeverityLevel: severity,
+			Properties:    properties,
+		}}
+		return envelope, nil
+	}
+
+	envelope.Name = itemPrefix + "Message"
+	envelope.Data = AppInsightsData{BaseType: "MessageData", BaseData: AppInsightsBaseData{
+		Ver:           2,
+		Message:       msg,
+		SeverityLevel: severity,
+		Properties:    properties,
+	}}
+	return envelope, nil
+}
+
+// appInsightsHTTPTransport posts envelopes to the ingestion endpoint.
+type appInsightsHTTPTransport struct {
+	endpoint string
+	client   *http.Client
+}
+
+// Track posts the envelopes as a JSON array.
+func (t *appInsightsHTTPTransport) Track(ctx context.Context, envelopes []AppInsightsEnvelope) error {
+	body, err := json.Marshal(envelopes)
+	if err != nil {
+		return fmt.Errorf("appinsights: encode envelopes: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("appinsights: build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("appinsights: track: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("appinsights: track: unexpected status %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package logger
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestAppInsightsExporter tests that entries are tracked as messages and exceptions.
+func TestAppInsightsExporter(t *testing.T) {
+	var received []AppInsightsEnvelope
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/track" {
+			t.Errorf("Expected path /v2/track, got %s", r.URL.Path)
+		}
+		var envelopes []AppInsightsEnvelope
+		if err := json.NewDecoder(r.Body).Decode(&envelopes); err != nil {
+			t.Errorf("Failed to decode request: %v", err)
+		}
+		received = append(received, envelopes...)
+	}))
+	defer server.Close()
+
+	exporter := MustAppInsightsExporter(AppInsightsConfig{
+		ConnectionString: "InstrumentationKey=1234-5678;IngestionEndpoint=" + server.URL + "/",
+	})
+	zapLogger := NewZap(Config{Level: InfoLevel, Output: exporter})
+
+	zapLogger.Warn("Warn message", Fields{"key": "value", "correlation_id": "op-1"})
+	zapLogger.Error("Error message", Fields{"error": errors.New("boom")})
+
+	if len(received) != 2 {
+		t.Fatalf("Expected 2 envelopes, got %d", len(received))
+	}
+	trace := received[0]
+	if trace.Name != "Microsoft.ApplicationInsights.12345678.Message" || trace.IKey != "1234-5678" {
+		t.Errorf("Unexpected envelope name or key: %s %s", trace.Name, trace.IKey)
+	}
+	if trace.Data.BaseData.Message != "Warn message" || trace.Data.BaseData.SeverityLevel != 2 {
+		t.Errorf("Unexpected trace data: %+v", trace.Data.BaseData)
+	}
+	if trace.Data.BaseData.Properties["key"] != "value" {
+		t.Errorf("Expected property key=value, got %v", trace.Data.BaseData.Properties)
+	}
+	if trace.Tags["ai.operation.id"] != "op-1" {
+		t.Errorf("Expected operation ID op-1, got %v", trace.Tags)
+	}
+
+	exception := received[1]
+	if exception.Data.BaseType != "ExceptionData" || len(exception.Data.BaseData.Exceptions) != 1 {
+		t.Fatalf("Expected an exception, got %+v", exception.Data)
+	}
+	if msg := exception.Data.BaseData.Exceptions[0].Message; msg != "Error message: boom" {
+		t.Errorf("Expected exception message, got %q", msg)
+	}
+}
+
+// TestNewAppInsightsExporterE tests that a missing instrumentation key is rejected.
+func TestNewAppInsightsExporterE(t *testing.T) {
+	if _, err := NewAppInsightsExporterE(AppInsightsConfig{}); err == nil {
+		t.Errorf("Expected an error without an instrumentation key")
+	}
+	if _, err := NewAppInsightsExporterE(AppInsightsConfig{InstrumentationKey: "key"}); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	exporter := NewAppInsightsExporter(AppInsightsConfig{Transport: &appInsightsRecorder{}})
+	if _, err := exporter.Write([]byte(`{"level":"info","msg":"m"}`)); err == nil {
+		t.Errorf("Expected an error exporting without an instrumentation key")
+	}
+}
+
+// TestAppInsightsExporter_Retry tests that envelopes of a failed export are
+// kept for the next one, up to MaxBuffered.
+func TestAppInsightsExporter_Retry(t *testing.T) {
+	transport := &appInsightsRecorder{err: errors.New("ingestion down")}
+	var reported []error
+	exporter := NewAppInsightsExporter(AppInsightsConfig{
+		InstrumentationKey: "key",
+		BatchSize:          2,
+		MaxBuffered:        3,
+		Transport:          transport,
+		OnError:            func(err error) { reported = append(reported, err) },
+	})
+
+	for i := 0; i < 4; i++ {
+		if _, err := exporter.Write([]byte(fmt.Sprintf(`{"level":"info","msg":"m%d"}`, i))); err != nil {
+			t.Errorf("Expected the buffered envelope to be accepted, got %v", err)
+		}
+	}
+	if len(reported) != 3 || exporter.Health(context.Background()) == nil {
+		t.Errorf("Expected the failed exports to be reported, got %v", reported)
+	}
+
+	transport.err = nil
+	if err := exporter.Sync(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var messages []string
+	for _, envelope := range transport.envelopes {
+		messages = append(messages, envelope.Data.BaseData.Message)
+	}
+	if fmt.Sprint(messages) != "[m1 m2 m3]" {
+		t.Errorf("Expected the 3 most recent envelopes to be exported, got %v", messages)
+	}
+	if err := exporter.Health(context.Background()); err != nil {
+		t.Errorf("Expected Health to recover after the export, got %v", err)
+	}
+}
+
+type appInsightsRecorder struct {
+	envelopes []AppInsightsEnvelope
+	// err, if set, fails the exports.
+	err error
+}
+
+func (r *appInsightsRecorder) Track(_ context.Context, envelopes []AppInsightsEnvelope) error {
+	if r.err != nil {
+		return r.err
+	}
+	r.envelopes = append(r.envelopes, envelopes...)
+	return nil
+}
@@ -0,0 +1,186 @@
+package logger
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ObjectUploader stores an object in S3-compatible or other object storage,
+// e.g. by wrapping an S3, MinIO, or GCS client.
+type ObjectUploader interface {
+	Upload(ctx context.Context, key string, body []byte) error
+}
+
+// ArchiveConfig holds the configuration for the archive writer.
+type ArchiveConfig struct {
+	// Uploader stores the finished objects.
+	Uploader ObjectUploader
+	// Prefix is prepended to every object key, e.g. "logs/orders/".
+	Prefix string
+	// Bucket is the time span covered by one object; defaults to one hour.
+	Bucket time.Duration
+	// Clock assigns entries to buckets; defaults to the system clock.
+	Clock Clock
+	// Timeout bounds a single upload; defaults to one minute.
+	Timeout time.Duration
+	// MaxPending caps the finished objects kept while uploads fail; defaults
+	// to 24. The oldest object is dropped when the cap is exceeded.
+	MaxPending int
+	// OnError is called when an upload triggered by Write fails or a pending
+	// object is dropped. Sync returns upload errors instead.
+	OnError func(error)
+}
+
+// ArchiveWriter is an output that accumulates entries into gzip-compressed
+// NDJSON objects, one per time bucket, and uploads each object once its bucket
+// has passed. Objects are keyed Prefix + bucket start + a unique suffix, e.g.
+// logs/2024/05/01/13-00-00-01HX....ndjson.gz. Objects whose upload fails are
+// kept and retried with the next finished bucket or Sync.
+//
+// The object of a bucket is only finished by the first Write of a later
+// bucket, so an idle writer keeps its last object in memory until Sync. Call
+// Sync periodically and before exiting.
+type ArchiveWriter struct {
+	config  ArchiveConfig
+	mu      sync.Mutex
+	start   time.Time
+	buf     bytes.Buffer
+	gz      *gzip.Writer
+	pending []archiveObject
+	lastErr error
+}
+
+// archiveObject is a finished object awaiting upload.
+type archiveObject struct {
+	key  string
+	body []byte
+}
+
+// NewArchiveWriterE returns a new *ArchiveWriter, or an error if no uploader
+// is configured.
+func NewArchiveWriterE(config ArchiveConfig) (*ArchiveWriter, error) {
+	if config.Uploader == nil {
+		return nil, errors.New("archive: uploader is not configured")
+	}
+	if config.Bucket <= 0 {
+		config.Bucket = time.Hour
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = time.Minute
+	}
+	if config.MaxPending <= 0 {
+		config.MaxPending = 24
+	}
+	return &ArchiveWriter{config: config}, nil
+}
+
+// MustArchiveWriter is like NewArchiveWriterE but panics on error.
+func MustArchiveWriter(config ArchiveConfig) *ArchiveWriter {
+	w, err := NewArchiveWriterE(config)
+	if err != nil {
+		panic(err)
+	}
+	return w
+}
+
+// Write appends a single entry to the object of the current bucket. The
+// first entry of a new bucket finishes the previous object and uploads it;
+// upload failures are passed to OnError and never cause the entry to be lost.
+func (w *ArchiveWriter) Write(p []byte) (int, error) {
+	now := time.Now()
+	if w.config.Clock != nil {
+		now = w.config.Clock.Now()
+	}
+	start := now.Truncate(w.config.Bucket)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.gz != nil && !start.Equal(w.start) {
+		if err := w.finish(); err != nil {
+			w.report(err)
+		} else if err := w.upload(); err != nil {
+			w.report(err)
+		}
+	}
+	if w.gz == nil {
+		w.start = start
+		w.gz = gzip.NewWriter(&w.buf)
+	}
+	if _, err := w.gz.Write(p); err != nil {
+		return 0, fmt.Errorf("archive: compress: %w", err)
+	}
+	return len(p), nil
+}
+
+// Sync finishes the current object, even if its bucket has not passed yet,
+// and uploads it together with any objects left by earlier failures.
+func (w *ArchiveWriter) Sync() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.gz != nil {
+		if err := w.finish(); err != nil {
+			return err
+		}
+	}
+	return w.upload()
+}
+
+// finish closes the current object and queues it for upload, dropping the
+// oldest pending object beyond MaxPending. The caller must hold w.mu.
+func (w *ArchiveWriter) finish() error {
+	if err := w.gz.Close(); err != nil {
+		return fmt.Errorf("archive: compress: %w", err)
+	}
+	key := w.config.Prefix + w.start.UTC().Format("2006/01/02/15-04-05") + "-" + newULID(time.Now()) + ".ndjson.gz"
+	w.pending = append(w.pending, archiveObject{key: key, body: append([]byte(nil), w.buf.Bytes()...)})
+	w.buf.Reset()
+	w.gz = nil
+
+	if n := len(w.pending) - w.config.MaxPending; n > 0 {
+		for _, object := range w.pending[:n] {
+			w.report(fmt.Errorf("archive: dropped %s after failed uploads", object.key))
+		}
+		w.pending = append(w.pending[:0], w.pending[n:]...)
+	}
+	return nil
+}
+
+// upload uploads the pending objects in order, stopping at the first failure.
+// An object is discarded only once it has been stored, so a failed upload is
+// retried with the same key. The caller must hold w.mu.
+func (w *ArchiveWriter) upload() error {
+	for len(w.pending) > 0 {
+		object := w.pending[0]
+		ctx, cancel := context.WithTimeout(context.Background(), w.config.Timeout)
+		err := w.config.Uploader.Upload(ctx, object.key, object.body)
+		cancel()
+		if err != nil {
+			w.lastErr = fmt.Errorf("archive: upload %s: %w", object.key, err)
+			return w.lastErr
+		}
+		w.pending[0] = archiveObject{}
+		w.pending = w.pending[1:]
+	}
+	w.lastErr = nil
+	return nil
+}
+
+// report passes err to OnError.
+func (w *ArchiveWriter) report(err error) {
+	if w.config.OnError != nil {
+		w.config.OnError(err)
+	}
+}
+
+// Health returns the error of the most recent upload, or nil if it succeeded.
+func (w *ArchiveWriter) Health(ctx context.Context) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.lastErr
+}
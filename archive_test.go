@@ -0,0 +1,166 @@
+package logger
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestArchiveWriter tests that entries are uploaded as one gzip NDJSON object per bucket.
+func TestArchiveWriter(t *testing.T) {
+	uploader := &memoryUploader{}
+	clock := &manualClock{now: time.Date(2024, 5, 1, 13, 10, 0, 0, time.UTC)}
+	writer := MustArchiveWriter(ArchiveConfig{Uploader: uploader, Prefix: "logs/", Clock: clock})
+
+	writer.Write([]byte("{\"msg\":\"a\"}\n"))
+	writer.Write([]byte("{\"msg\":\"b\"}\n"))
+	if len(uploader.keys) != 0 {
+		t.Errorf("Expected no upload within the bucket, got %v", uploader.keys)
+	}
+
+	clock.now = clock.now.Add(time.Hour)
+	writer.Write([]byte("{\"msg\":\"c\"}\n"))
+	if len(uploader.keys) != 1 {
+		t.Fatalf("Expected 1 upload after the bucket passed, got %d", len(uploader.keys))
+	}
+	if !strings.HasPrefix(uploader.keys[0], "logs/2024/05/01/13-00-00-") || !strings.HasSuffix(uploader.keys[0], ".ndjson.gz") {
+		t.Errorf("Unexpected object key %s", uploader.keys[0])
+	}
+	if got := gunzip(t, uploader.bodies[0]); got != "{\"msg\":\"a\"}\n{\"msg\":\"b\"}\n" {
+		t.Errorf("Unexpected object content %q", got)
+	}
+
+	if err := writer.Sync(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(uploader.keys) != 2 || !strings.HasPrefix(uploader.keys[1], "logs/2024/05/01/14-00-00-") {
+		t.Errorf("Expected Sync to upload the partial object, got %v", uploader.keys)
+	}
+}
+
+// TestArchiveWriter_RetryUpload tests that a failed upload keeps the object for the next attempt.
+func TestArchiveWriter_RetryUpload(t *testing.T) {
+	uploader := &memoryUploader{err: errors.New("unavailable")}
+	writer := MustArchiveWriter(ArchiveConfig{Uploader: uploader})
+
+	writer.Write([]byte("{\"msg\":\"a\"}\n"))
+	if err := writer.Sync(); err == nil {
+		t.Errorf("Expected the upload error to be returned")
+	}
+	writer.Write([]byte("{\"msg\":\"b\"}\n"))
+
+	uploader.err = nil
+	if err := writer.Sync(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(uploader.bodies) != 2 {
+		t.Fatalf("Expected 2 uploads after the retry, got %d", len(uploader.bodies))
+	}
+	if got := gunzip(t, uploader.bodies[0]) + gunzip(t, uploader.bodies[1]); got != "{\"msg\":\"a\"}\n{\"msg\":\"b\"}\n" {
+		t.Errorf("Expected both entries after the retry, got %q", got)
+	}
+
+	if _, err := NewArchiveWriterE(ArchiveConfig{}); err == nil {
+		t.Errorf("Expected an error without an uploader")
+	}
+}
+
+// TestArchiveWriter_FailedBucketUpload tests that a failed upload at a bucket
+// boundary keeps both the finished object and the new entries.
+func TestArchiveWriter_FailedBucketUpload(t *testing.T) {
+	uploader := &memoryUploader{err: errors.New("unavailable")}
+	clock := &manualClock{now: time.Date(2024, 5, 1, 13, 10, 0, 0, time.UTC)}
+	var reported []error
+	writer := MustArchiveWriter(ArchiveConfig{
+		Uploader: uploader,
+		Clock:    clock,
+		OnError:  func(err error) { reported = append(reported, err) },
+	})
+
+	writer.Write([]byte("{\"msg\":\"a\"}\n"))
+	clock.now = clock.now.Add(time.Hour)
+	if n, err := writer.Write([]byte("{\"msg\":\"b\"}\n")); n != 12 || err != nil {
+		t.Errorf("Expected the entry to be accepted, got %d, %v", n, err)
+	}
+	writer.Write([]byte("{\"msg\":\"c\"}\n"))
+	if len(reported) != 1 {
+		t.Errorf("Expected the failed upload to be reported once, got %v", reported)
+	}
+	if writer.Health(context.Background()) == nil {
+		t.Errorf("Expected Health to report the failed upload")
+	}
+
+	uploader.err = nil
+	if err := writer.Sync(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(uploader.keys) != 2 || !strings.Contains(uploader.keys[0], "/13-00-00-") || !strings.Contains(uploader.keys[1], "/14-00-00-") {
+		t.Fatalf("Expected both buckets to be uploaded in order, got %v", uploader.keys)
+	}
+	if got := gunzip(t, uploader.bodies[1]); got != "{\"msg\":\"b\"}\n{\"msg\":\"c\"}\n" {
+		t.Errorf("Unexpected object content %q", got)
+	}
+	if writer.Health(context.Background()) != nil {
+		t.Errorf("Expected Health to recover after the upload")
+	}
+}
+
+// TestArchiveWriter_MaxPending tests that the oldest pending object is dropped beyond MaxPending.
+func TestArchiveWriter_MaxPending(t *testing.T) {
+	uploader := &memoryUploader{err: errors.New("unavailable")}
+	var reported []error
+	writer := MustArchiveWriter(ArchiveConfig{
+		Uploader:   uploader,
+		MaxPending: 1,
+		OnError:    func(err error) { reported = append(reported, err) },
+	})
+
+	writer.Write([]byte("{\"msg\":\"a\"}\n"))
+	writer.Sync()
+	writer.Write([]byte("{\"msg\":\"b\"}\n"))
+	writer.Sync()
+	if len(reported) != 1 || !strings.Contains(reported[0].Error(), "dropped") {
+		t.Errorf("Expected the dropped object to be reported, got %v", reported)
+	}
+
+	uploader.err = nil
+	if err := writer.Sync(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(uploader.bodies) != 1 || gunzip(t, uploader.bodies[0]) != "{\"msg\":\"b\"}\n" {
+		t.Errorf("Expected only the newest object to remain")
+	}
+}
+
+type memoryUploader struct {
+	keys   []string
+	bodies [][]byte
+	err    error
+}
+
+func (u *memoryUploader) Upload(_ context.Context, key string, body []byte) error {
+	if u.err != nil {
+		return u.err
+	}
+	u.keys = append(u.keys, key)
+	u.bodies = append(u.bodies, append([]byte(nil), body...))
+	return nil
+}
+
+func gunzip(t *testing.T, body []byte) string {
+	t.Helper()
+	r, err := gzip.NewReader(bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return string(data)
+}
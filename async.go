@@ -0,0 +1,243 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"sync/atomic"
+)
+
+// ErrAsyncClosed is returned by writes to a closed AsyncWriter.
+var ErrAsyncClosed = errors.New("logger: async writer closed")
+
+// Backpressure selects what an AsyncWriter does when its queue is full.
+type Backpressure int
+
+const (
+	// BackpressureBlock blocks the caller until there is room, losing nothing;
+	// suited to batch jobs.
+	BackpressureBlock Backpressure = iota
+	// BackpressureDropNewest discards the entry being written, keeping callers
+	// fast; suited to latency-sensitive servers.
+	BackpressureDropNewest
+	// BackpressureDropOldest discards the oldest queued entry to make room,
+	// keeping the most recent context.
+	BackpressureDropOldest
+)
+
+// AsyncConfig holds the configuration for an asynchronous writer.
+type AsyncConfig struct {
+	// Output receives the entries from a background goroutine.
+	Output io.Writer
+	// QueueSize is the number of entries that can be queued; defaults to 1024.
+	QueueSize int
+	// Backpressure selects the policy when the queue is full; defaults to
+	// BackpressureBlock.
+	Backpressure Backpressure
+	// Context, if set, closes the writer when it is canceled: the queued
+	// entries are written and the goroutine stops, as with Close.
+	Context context.Context
+}
+
+// AsyncStats counts the entries affected by backpressure.
+type AsyncStats struct {
+	// Blocked is the number of writes that waited for room in the queue.
+	Blocked uint64
+	// DroppedNewest is the number of entries discarded on arrival.
+	DroppedNewest uint64
+	// DroppedOldest is the number of queued entries discarded to make room.
+	DroppedOldest uint64
+}
+
+// AsyncWriter is a writer that queues entries and writes them to its output
+// from a background goroutine, so slow outputs do not block logging calls.
+// Sync waits until the entries queued before it are written; Close drains the
+// queue and stops the goroutine.
+type AsyncWriter struct {
+	config  AsyncConfig
+	queue   chan asyncItem
+	mu      sync.RWMutex
+	closed  bool
+	stopped chan struct{}
+	abandon uint32
+	stats   AsyncStats
+	lastErr atomic.Value
+}
+
+// asyncItem is a queued entry, or a sync marker when done is set.
+type asyncItem struct {
+	data []byte
+	done chan error
+}
+
+// NewAsyncWriter returns a new *AsyncWriter and starts its goroutine.
+func NewAsyncWriter(config AsyncConfig) *AsyncWriter {
+	if config.QueueSize <= 0 {
+		config.QueueSize = 1024
+	}
+	w := &AsyncWriter{
+		config:  config,
+		queue:   make(chan asyncItem, config.QueueSize),
+		stopped: make(chan struct{}),
+	}
+	go w.run()
+	if ctx := config.Context; ctx != nil && ctx.Done() != nil {
+		go w.closeOnDone(ctx)
+	}
+	return w
+}
+
+// closeOnDone closes w once ctx is canceled, unless w stops first.
+func (w *AsyncWriter) closeOnDone(ctx context.Context) {
+	select {
+	case <-ctx.Done():
+		w.Close()
+	case <-w.stopped:
+	}
+}
+
+// Write queues a copy of p according to the backpressure policy.
+func (w *AsyncWriter) Write(p []byte) (int, error) {
+	item := asyncItem{data: append([]byte(nil), p...)}
+
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if w.closed {
+		return 0, ErrAsyncClosed
+	}
+
+	select {
+	case w.queue <- item:
+		return len(p), nil
+	default:
+	}
+
+	switch w.config.Backpressure {
+	case BackpressureDropNewest:
+		atomic.AddUint64(&w.stats.DroppedNewest, 1)
+	case BackpressureDropOldest:
+		for {
+			select {
+			case w.queue <- item:
+				return len(p), nil
+			default:
+			}
+			select {
+			case old := <-w.queue:
+				if old.done != nil {
+					old.done <- nil
+					continue
+				}
+				atomic.AddUint64(&w.stats.DroppedOldest, 1)
+			default:
+			}
+		}
+	default:
+		atomic.AddUint64(&w.stats.Blocked, 1)
+		w.queue <- item
+	}
+	return len(p), nil
+}
+
+// Sync waits until the entries queued before it have been written, then
+// flushes the output if it supports it.
+func (w *AsyncWriter) Sync() error {
+	done := make(chan error, 1)
+
+	w.mu.RLock()
+	if w.closed {
+		w.mu.RUnlock()
+		return nil
+	}
+	w.queue <- asyncItem{done: done}
+	w.mu.RUnlock()
+
+	return <-done
+}
+
+// Close stops accepting entries, writes the queued ones, and flushes the output.
+func (w *AsyncWriter) Close() error {
+	return w.Shutdown(context.Background())
+}
+
+// Shutdown stops accepting entries, writes the queued ones, and flushes the
+// output. If ctx expires first, the entries still queued are discarded and
+// an *AbandonedError reports how many.
+func (w *AsyncWriter) Shutdown(ctx context.Context) error {
+	go func() {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		if !w.closed {
+			w.closed = true
+			close(w.queue)
+		}
+	}()
+
+	select {
+	case <-w.stopped:
+		return w.flush()
+	case <-ctx.Done():
+		atomic.StoreUint32(&w.abandon, 1)
+		return &AbandonedError{Abandoned: len(w.queue), Err: ctx.Err()}
+	}
+}
+
+// Stats returns the backpressure counters.
+func (w *AsyncWriter) Stats() AsyncStats {
+	return AsyncStats{
+		Blocked:       atomic.LoadUint64(&w.stats.Blocked),
+		DroppedNewest: atomic.LoadUint64(&w.stats.DroppedNewest),
+		DroppedOldest: atomic.LoadUint64(&w.stats.DroppedOldest),
+	}
+}
+
+// Health returns the error of the most recent write to the output, or the
+// output's own health if it can report it.
+func (w *AsyncWriter) Health(ctx context.Context) error {
+	if err, _ := w.lastErr.Load().(asyncError); err.err != nil {
+		return err.err
+	}
+	if checker, ok := w.config.Output.(HealthChecker); ok {
+		return checker.Health(ctx)
+	}
+	return nil
+}
+
+// Ping pings the output if it can be pinged or health-checked.
+func (w *AsyncWriter) Ping(ctx context.Context) error {
+	return pingOutput(ctx, w.config.Output)
+}
+
+// asyncError wraps errors stored in an atomic.Value, which requires a
+// consistent concrete type.
+type asyncError struct {
+	err error
+}
+
+// run writes queued entries until the queue is closed.
+func (w *AsyncWriter) run() {
+	defer close(w.stopped)
+	for item := range w.queue {
+		if atomic.LoadUint32(&w.abandon) != 0 {
+			if item.done != nil {
+				item.done <- nil
+			}
+			continue
+		}
+		if item.done != nil {
+			item.done <- w.flush()
+			continue
+		}
+		_, err := w.config.Output.Write(item.data)
+		w.lastErr.Store(asyncError{err})
+	}
+}
+
+// flush syncs the output if it supports it.
+func (w *AsyncWriter) flush() error {
+	if s, ok := w.config.Output.(syncer); ok {
+		return s.Sync()
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestAsyncWriter tests that entries are written in the background and Sync waits for them.
+func TestAsyncWriter(t *testing.T) {
+	output := &syncBuffer{}
+	writer := NewAsyncWriter(AsyncConfig{Output: output})
+	zapLogger := NewZap(Config{Level: InfoLevel, Output: writer})
+
+	for i := 0; i < 100; i++ {
+		zapLogger.Info("Info message", nil)
+	}
+	if err := zapLogger.Sync(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if n := strings.Count(output.String(), "\n"); n != 100 {
+		t.Errorf("Expected 100 entries after Sync, got %d", n)
+	}
+
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := writer.Write([]byte("late\n")); !errors.Is(err, ErrAsyncClosed) {
+		t.Errorf("Expected ErrAsyncClosed after Close, got %v", err)
+	}
+}
+
+// TestAsyncWriter_Backpressure tests each policy when the queue is full.
+func TestAsyncWriter_Backpressure(t *testing.T) {
+	tests := []struct {
+		policy   Backpressure
+		expected string
+		stats    AsyncStats
+	}{
+		{BackpressureDropNewest, "0\n1\n2\n", AsyncStats{DroppedNewest: 1}},
+		{BackpressureDropOldest, "0\n2\n3\n", AsyncStats{DroppedOldest: 1}},
+		{BackpressureBlock, "0\n1\n2\n3\n", AsyncStats{Blocked: 1}},
+	}
+	for _, test := range tests {
+		output := &gatedWriter{gate: make(chan struct{}), started: make(chan struct{}, 1)}
+		writer := NewAsyncWriter(AsyncConfig{Output: output, QueueSize: 2, Backpressure: test.policy})
+
+		writer.Write([]byte("0\n"))
+		<-output.started
+		writer.Write([]byte("1\n"))
+		writer.Write([]byte("2\n"))
+
+		if test.policy != BackpressureBlock {
+			writer.Write([]byte("3\n"))
+			close(output.gate)
+		} else {
+			written := make(chan struct{})
+			go func() {
+				writer.Write([]byte("3\n"))
+				close(written)
+			}()
+			select {
+			case <-written:
+				t.Errorf("Expected the write to block while the queue is full")
+			case <-time.After(20 * time.Millisecond):
+			}
+			close(output.gate)
+			<-written
+		}
+		writer.Close()
+
+		if got := output.buf.String(); got != test.expected {
+			t.Errorf("Expected %q with policy %d, got %q", test.expected, test.policy, got)
+		}
+		if stats := writer.Stats(); stats != test.stats {
+			t.Errorf("Expected stats %+v with policy %d, got %+v", test.stats, test.policy, stats)
+		}
+	}
+}
+
+// gatedWriter blocks its writes until gate is closed.
+type gatedWriter struct {
+	gate    chan struct{}
+	started chan struct{}
+	mu      sync.Mutex
+	buf     bytes.Buffer
+}
+
+func (w *gatedWriter) Write(p []byte) (int, error) {
+	select {
+	case w.started <- struct{}{}:
+	default:
+	}
+	<-w.gate
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.Write(p)
+}
+
+// TestAsyncWriter_Context tests that canceling the context closes the writer
+// after writing the queued entries.
+func TestAsyncWriter_Context(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	output := &syncBuffer{}
+	w := NewAsyncWriter(AsyncConfig{Output: output, Context: ctx})
+
+	w.Write([]byte("first\n"))
+	w.Write([]byte("second\n"))
+	cancel()
+
+	select {
+	case <-w.stopped:
+	case <-time.After(time.Second):
+		t.Fatalf("Expected the goroutine to stop once the context is canceled")
+	}
+	if output.String() != "first\nsecond\n" {
+		t.Errorf("Expected the queued entries to be written, got %q", output.String())
+	}
+	if _, err := w.Write([]byte("third\n")); err != ErrAsyncClosed {
+		t.Errorf("Expected ErrAsyncClosed after the context is canceled, got %v", err)
+	}
+}
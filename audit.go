@@ -0,0 +1,76 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// auditLevel is the level label written on audit entries.
+const auditLevel = "audit"
+
+// syncer is implemented by outputs that can flush to stable storage, such as *os.File.
+type syncer interface {
+	Sync() error
+}
+
+// AuditLogger writes compliance events to a dedicated output. Unlike the regular
+// logging methods, every entry is written synchronously, flushed to stable storage
+// when the output supports it, and write failures are returned to the caller.
+type AuditLogger struct {
+	mu      sync.Mutex
+	output  io.Writer
+	encoder zapcore.Encoder
+}
+
+// NewAuditLogger returns a new *AuditLogger writing to output.
+func NewAuditLogger(output io.Writer) *AuditLogger {
+	encoderConfig := newEncoderConfig(Config{})
+	encoderConfig.CallerKey = zapcore.OmitKey
+	encoderConfig.EncodeLevel = func(_ zapcore.Level, enc zapcore.PrimitiveArrayEncoder) {
+		enc.AppendString(auditLevel)
+	}
+
+	return &AuditLogger{
+		output:  output,
+		encoder: zapcore.NewJSONEncoder(encoderConfig),
+	}
+}
+
+// Audit writes an audit event with structured fields. It returns an error if the
+// entry could not be encoded, written, or flushed; callers must not ignore it.
+func (a *AuditLogger) Audit(msg string, fields Fields) error {
+	if a.output == nil {
+		return errors.New("audit: output is not configured")
+	}
+
+	buf, err := a.encoder.EncodeEntry(zapcore.Entry{
+		Time:    time.Now(),
+		Message: msg,
+	}, mapToZapFields(fields))
+	if err != nil {
+		return fmt.Errorf("audit: encode entry: %w", err)
+	}
+	defer buf.Free()
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	n, err := a.output.Write(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("audit: write entry: %w", err)
+	}
+	if n != buf.Len() {
+		return fmt.Errorf("audit: write entry: %w", io.ErrShortWrite)
+	}
+	if s, ok := a.output.(syncer); ok {
+		if err := s.Sync(); err != nil {
+			return fmt.Errorf("audit: sync output: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+// TestAuditLogger_Audit tests that audit entries are written and synced.
+func TestAuditLogger_Audit(t *testing.T) {
+	output := &syncBuffer{}
+	auditLogger := NewAuditLogger(output)
+
+	if err := auditLogger.Audit("user deleted", Fields{"user": "alice"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for _, expected := range []string{`"level":"audit"`, `"msg":"user deleted"`, `"user":"alice"`} {
+		if !bytes.Contains(output.Bytes(), []byte(expected)) {
+			t.Errorf("Expected %s to contain %s", output.String(), expected)
+		}
+	}
+	if output.syncs != 1 {
+		t.Errorf("Expected 1 sync, got %d", output.syncs)
+	}
+}
+
+// TestAuditLogger_WriteError tests that write failures are returned to the caller.
+func TestAuditLogger_WriteError(t *testing.T) {
+	auditLogger := NewAuditLogger(failingWriter{})
+
+	if err := auditLogger.Audit("user deleted", nil); err == nil {
+		t.Errorf("Expected an error, got nil")
+	}
+}
+
+type syncBuffer struct {
+	bytes.Buffer
+	syncs int
+}
+
+func (s *syncBuffer) Sync() error {
+	s.syncs++
+	return nil
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("disk full")
+}
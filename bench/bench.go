@@ -0,0 +1,260 @@
+// Package bench runs an identical logging workload through several logger
+// backends and reports the cost of each, so a backend can be chosen with data:
+//
+//	results := bench.Run(bench.Backends(), bench.Workloads())
+//	bench.WriteTable(os.Stdout, results)
+//
+// The package ships the backends of the logger package, zap writing
+// synchronously and zap behind an AsyncWriter, and, when built with Go 1.21 or
+// later, log/slog writing JSON. Libraries outside the standard library, such
+// as zerolog, are not bundled, which keeps this module free of their
+// dependencies; they take part by adding a Backend that adapts them to
+// logger.Logger. For go test, call Loop from a benchmark function.
+package bench
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ralonr/logger"
+)
+
+// Backend is a logger implementation under comparison.
+type Backend struct {
+	// Name identifies the backend in results, e.g. "zap-async".
+	Name string
+	// New returns an instance of the backend writing to w.
+	New func(w io.Writer) Instance
+}
+
+// Instance is a backend set up for one run.
+type Instance struct {
+	// Logger receives the workload.
+	Logger logger.Logger
+	// Dropped, if set, returns the number of entries the backend discarded,
+	// e.g. under backpressure.
+	Dropped func() uint64
+	// Close, if set, flushes the backend at the end of the run.
+	Close func() error
+}
+
+// Workload is the entry logged on every iteration of a run.
+type Workload struct {
+	// Name identifies the workload in results.
+	Name string
+	// Level is the level of the entry.
+	Level logger.Level
+	// Message is the entry's message.
+	Message string
+	// Fields are the entry's fields.
+	Fields logger.Fields
+	// Parallel logs from GOMAXPROCS goroutines at once.
+	Parallel bool
+}
+
+// Result is the cost of a workload on a backend.
+type Result struct {
+	Backend  string
+	Workload string
+	// N is the number of entries logged in the measured run.
+	N           int
+	NsPerOp     int64
+	AllocsPerOp int64
+	BytesPerOp  int64
+	// Dropped is the number of entries the backend discarded in the measured run.
+	Dropped uint64
+	// Err is the error closing the backend after the measured run, if any.
+	Err error
+}
+
+// extraBackends are the backends that depend on the Go version, such as slog.
+var extraBackends []Backend
+
+// Backends returns the bundled backends: "zap", writing synchronously,
+// "zap-async", writing through an AsyncWriter that drops the newest entries
+// when its queue is full, and "slog" when built with Go 1.21 or later.
+func Backends() []Backend {
+	return append([]Backend{
+		{Name: "zap", New: func(w io.Writer) Instance {
+			return Instance{Logger: newZap(w)}
+		}},
+		{Name: "zap-async", New: func(w io.Writer) Instance {
+			async := logger.NewAsyncWriter(logger.AsyncConfig{Output: w, Backpressure: logger.BackpressureDropNewest})
+			return Instance{
+				Logger: newZap(async),
+				Dropped: func() uint64 {
+					stats := async.Stats()
+					return stats.DroppedNewest + stats.DroppedOldest
+				},
+				Close: async.Close,
+			}
+		}},
+	}, extraBackends...)
+}
+
+// newZap returns a zap logger writing JSON entries to w at info level.
+func newZap(w io.Writer) *logger.Zap {
+	return logger.NewZap(logger.Config{Level: logger.InfoLevel, Output: w, OnFatal: logger.FatalNone})
+}
+
+// Workloads returns a standard set of workloads: a message without fields, a
+// handful of fields, a disabled debug entry, and the handful of fields from
+// parallel goroutines.
+func Workloads() []Workload {
+	fields := logger.Fields{
+		"user":     "alice",
+		"order_id": 42,
+		"amount":   19.99,
+		"paid":     true,
+		"tags":     []string{"a", "b"},
+	}
+	return []Workload{
+		{Name: "no-fields", Level: logger.InfoLevel, Message: "request served"},
+		{Name: "fields", Level: logger.InfoLevel, Message: "request served", Fields: fields},
+		{Name: "disabled", Level: logger.DebugLevel, Message: "cache lookup", Fields: fields},
+		{Name: "parallel", Level: logger.InfoLevel, Message: "request served", Fields: fields, Parallel: true},
+	}
+}
+
+// benchTime is the minimum duration of a measured run.
+var benchTime = time.Second
+
+// Run measures every workload on every backend, writing the entries to
+// io.Discard. Like go test -bench, each measurement grows the number of
+// entries until a run lasts at least a second.
+func Run(backends []Backend, workloads []Workload) []Result {
+	var results []Result
+	for _, backend := range backends {
+		for _, workload := range workloads {
+			results = append(results, measure(backend, workload))
+		}
+	}
+	return results
+}
+
+// measure runs workload on backend with a growing number of entries until a
+// run lasts benchTime, and returns the result of the last run.
+func measure(backend Backend, workload Workload) Result {
+	n := 1
+	for {
+		result, elapsed := runN(backend, workload, n)
+		if elapsed >= benchTime || n >= 1e9 {
+			return result
+		}
+		next := 100 * n
+		if perOp := elapsed.Nanoseconds() / int64(n); perOp > 0 {
+			// Aim 20% past benchTime, growing at least by one and at most 100x.
+			if predicted := int(benchTime.Nanoseconds() * 6 / 5 / perOp); predicted < next {
+				next = predicted
+			}
+		}
+		if next <= n {
+			next = n + 1
+		}
+		n = next
+	}
+}
+
+// runN logs workload n times on a new instance of backend.
+func runN(backend Backend, workload Workload, n int) (Result, time.Duration) {
+	instance := backend.New(io.Discard)
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	start := time.Now()
+	Loop(instance, workload, n)
+	elapsed := time.Since(start)
+	runtime.ReadMemStats(&after)
+	dropped, err := instance.Finish()
+
+	return Result{
+		Backend:     backend.Name,
+		Workload:    workload.Name,
+		N:           n,
+		NsPerOp:     elapsed.Nanoseconds() / int64(n),
+		AllocsPerOp: int64(after.Mallocs-before.Mallocs) / int64(n),
+		BytesPerOp:  int64(after.TotalAlloc-before.TotalAlloc) / int64(n),
+		Dropped:     dropped,
+		Err:         err,
+	}, elapsed
+}
+
+// Loop logs workload n times on instance, spreading the entries over
+// GOMAXPROCS goroutines for parallel workloads. It is the measured part of a
+// run, for use from benchmark functions:
+//
+//	instance := backend.New(io.Discard)
+//	b.ReportAllocs()
+//	b.ResetTimer()
+//	bench.Loop(instance, workload, b.N)
+//	b.StopTimer()
+//	dropped, err := instance.Finish()
+func Loop(instance Instance, workload Workload, n int) {
+	log := logFunc(instance.Logger, workload.Level)
+	if !workload.Parallel {
+		for i := 0; i < n; i++ {
+			log(workload.Message, workload.Fields)
+		}
+		return
+	}
+
+	procs := runtime.GOMAXPROCS(0)
+	var wg sync.WaitGroup
+	for p := 0; p < procs; p++ {
+		count := n / procs
+		if p < n%procs {
+			count++
+		}
+		wg.Add(1)
+		go func(count int) {
+			defer wg.Done()
+			for i := 0; i < count; i++ {
+				log(workload.Message, workload.Fields)
+			}
+		}(count)
+	}
+	wg.Wait()
+}
+
+// Finish closes the instance, if it can be closed, and returns the number of
+// entries it dropped.
+func (i Instance) Finish() (uint64, error) {
+	var err error
+	if i.Close != nil {
+		err = i.Close()
+	}
+	if i.Dropped != nil {
+		return i.Dropped(), err
+	}
+	return 0, err
+}
+
+// logFunc returns the method of l logging at level.
+func logFunc(l logger.Logger, level logger.Level) func(string, logger.Fields) {
+	switch level {
+	case logger.DebugLevel:
+		return l.Debug
+	case logger.InfoLevel:
+		return l.Info
+	case logger.WarnLevel:
+		return l.Warn
+	case logger.ErrorLevel:
+		return l.Error
+	default:
+		return l.Fatal
+	}
+}
+
+// WriteTable writes results as an aligned text table.
+func WriteTable(w io.Writer, results []Result) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "backend\tworkload\tns/op\tB/op\tallocs/op\tdropped\t")
+	for _, r := range results {
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%d\t%d\t\n", r.Backend, r.Workload, r.NsPerOp, r.BytesPerOp, r.AllocsPerOp, r.Dropped)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,78 @@
+package bench
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ralonr/logger"
+)
+
+// BenchmarkBackends compares the bundled backends, reporting dropped entries
+// as a "dropped" metric.
+func BenchmarkBackends(b *testing.B) {
+	for _, backend := range Backends() {
+		for _, workload := range Workloads() {
+			backend, workload := backend, workload
+			b.Run(backend.Name+"/"+workload.Name, func(b *testing.B) {
+				instance := backend.New(io.Discard)
+				b.ReportAllocs()
+				b.ResetTimer()
+				Loop(instance, workload, b.N)
+				b.StopTimer()
+				dropped, err := instance.Finish()
+				if err != nil {
+					b.Errorf("close %s: %v", backend.Name, err)
+				}
+				b.ReportMetric(float64(dropped), "dropped")
+			})
+		}
+	}
+}
+
+// countingLogger is a backend that counts entries and drops every other one.
+type countingLogger struct {
+	logged uint64
+}
+
+func (l *countingLogger) Debug(msg string, fields logger.Fields) {}
+func (l *countingLogger) Info(msg string, fields logger.Fields)  { atomic.AddUint64(&l.logged, 1) }
+func (l *countingLogger) Warn(msg string, fields logger.Fields)  {}
+func (l *countingLogger) Error(msg string, fields logger.Fields) {}
+func (l *countingLogger) Fatal(msg string, fields logger.Fields) {}
+
+// TestRun tests that every workload is run on every backend and dropped entries are reported.
+func TestRun(t *testing.T) {
+	counting := Backend{Name: "counting", New: func(w io.Writer) Instance {
+		l := &countingLogger{}
+		return Instance{Logger: l, Dropped: func() uint64 { return atomic.LoadUint64(&l.logged) / 2 }}
+	}}
+	workloads := []Workload{{Name: "info", Level: logger.InfoLevel, Message: "m"}, {Name: "debug", Level: logger.DebugLevel, Message: "m"}}
+
+	defer func(d time.Duration) { benchTime = d }(benchTime)
+	benchTime = 10 * time.Millisecond
+	results := Run([]Backend{counting}, workloads)
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 results, got %d", len(results))
+	}
+	if results[0].Backend != "counting" || results[0].Workload != "info" || results[0].N == 0 {
+		t.Errorf("Expected the info workload result, got %+v", results[0])
+	}
+	if results[0].Dropped != uint64(results[0].N/2) {
+		t.Errorf("Expected %d dropped entries, got %d", results[0].N/2, results[0].Dropped)
+	}
+	if results[1].Dropped != 0 {
+		t.Errorf("Expected no entries for the debug workload, got %d", results[1].Dropped)
+	}
+
+	buffer := new(bytes.Buffer)
+	if err := WriteTable(buffer, results); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if lines := strings.Split(strings.TrimSpace(buffer.String()), "\n"); len(lines) != 3 || !strings.Contains(lines[1], "counting") {
+		t.Errorf("Expected a header and 2 rows, got %s", buffer.String())
+	}
+}
@@ -0,0 +1,46 @@
+//go:build go1.21
+
+package bench
+
+import (
+	"context"
+	"io"
+	"log/slog"
+
+	"github.com/ralonr/logger"
+)
+
+func init() {
+	extraBackends = append(extraBackends, Backend{Name: "slog", New: func(w io.Writer) Instance {
+		return Instance{Logger: slogLogger{slog.New(slog.NewJSONHandler(w, nil))}}
+	}})
+}
+
+// slogFatal is the slog level fatal entries are logged at; slog defines none.
+const slogFatal = slog.LevelError + 4
+
+// slogLogger adapts a *slog.Logger to logger.Logger. Fatal entries are logged
+// without exiting.
+type slogLogger struct {
+	l *slog.Logger
+}
+
+func (s slogLogger) Debug(msg string, fields logger.Fields) { s.log(slog.LevelDebug, msg, fields) }
+func (s slogLogger) Info(msg string, fields logger.Fields)  { s.log(slog.LevelInfo, msg, fields) }
+func (s slogLogger) Warn(msg string, fields logger.Fields)  { s.log(slog.LevelWarn, msg, fields) }
+func (s slogLogger) Error(msg string, fields logger.Fields) { s.log(slog.LevelError, msg, fields) }
+func (s slogLogger) Fatal(msg string, fields logger.Fields) { s.log(slogFatal, msg, fields) }
+
+// log writes an entry with fields as attributes, skipping disabled levels
+// before converting the fields, as the zap backend does.
+func (s slogLogger) log(level slog.Level, msg string, fields logger.Fields) {
+	ctx := context.Background()
+	if !s.l.Enabled(ctx, level) {
+		return
+	}
+	attrs := make([]slog.Attr, 0, len(fields))
+	for k, v := range fields {
+		attrs = append(attrs, slog.Any(k, v))
+	}
+	s.l.LogAttrs(ctx, level, msg, attrs...)
+}
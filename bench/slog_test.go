@@ -0,0 +1,34 @@
+//go:build go1.21
+
+package bench
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/ralonr/logger"
+)
+
+// TestSlogBackend tests that the slog backend is bundled and writes JSON entries.
+func TestSlogBackend(t *testing.T) {
+	var slogBackend *Backend
+	for _, backend := range Backends() {
+		if backend.Name == "slog" {
+			backend := backend
+			slogBackend = &backend
+		}
+	}
+	if slogBackend == nil {
+		t.Fatalf("Expected a slog backend")
+	}
+
+	buffer := new(bytes.Buffer)
+	instance := slogBackend.New(buffer)
+	Loop(instance, Workload{Level: logger.InfoLevel, Message: "request served", Fields: logger.Fields{"user": "alice"}}, 2)
+	Loop(instance, Workload{Level: logger.DebugLevel, Message: "cache lookup"}, 1)
+
+	if n := strings.Count(buffer.String(), `"msg":"request served","user":"alice"`); n != 2 {
+		t.Errorf("Expected 2 info entries and no debug entry, got %s", buffer.String())
+	}
+}
@@ -0,0 +1,157 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned by a CircuitBreaker without a fallback while it
+// is not sending to its output.
+var ErrCircuitOpen = errors.New("logger: circuit breaker open")
+
+// CircuitBreakerConfig holds the configuration for a circuit breaker.
+type CircuitBreakerConfig struct {
+	// Output is the remote writer being protected.
+	Output io.Writer
+	// Fallback receives entries while the circuit is open, e.g. a local file.
+	// Without one, those entries are rejected with ErrCircuitOpen.
+	Fallback io.Writer
+	// Failures is the number of consecutive failed writes that opens the
+	// circuit; defaults to 5.
+	Failures int
+	// Cooldown is how long the circuit stays open before a single write is let
+	// through to probe for recovery; defaults to 30 seconds.
+	Cooldown time.Duration
+	// Clock times the cooldown; defaults to the system clock.
+	Clock Clock
+}
+
+// CircuitBreaker is a writer that stops sending to a failing output after
+// repeated failures, so a slow or unreachable log backend cannot add latency
+// to every entry. While open, entries go to the fallback; after the cooldown
+// one write probes the output and closes the circuit if it succeeds. A write
+// that fails while the circuit is closed is also sent to the fallback.
+type CircuitBreaker struct {
+	config   CircuitBreakerConfig
+	mu       sync.Mutex
+	failures int
+	openedAt time.Time
+	open     bool
+	probing  bool
+}
+
+// NewCircuitBreaker returns a new *CircuitBreaker.
+func NewCircuitBreaker(config CircuitBreakerConfig) *CircuitBreaker {
+	if config.Failures <= 0 {
+		config.Failures = 5
+	}
+	if config.Cooldown <= 0 {
+		config.Cooldown = 30 * time.Second
+	}
+	return &CircuitBreaker{config: config}
+}
+
+// Write sends p to the output if the circuit is closed or due for a probe, and
+// to the fallback otherwise or if the output fails.
+func (b *CircuitBreaker) Write(p []byte) (int, error) {
+	if !b.allow() {
+		return b.fallback(p)
+	}
+
+	_, err := b.config.Output.Write(p)
+	b.record(err)
+	if err != nil {
+		return b.fallback(p)
+	}
+	return len(p), nil
+}
+
+// allow reports whether a write may be sent to the output.
+func (b *CircuitBreaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.open {
+		return true
+	}
+	if b.probing || b.now().Sub(b.openedAt) < b.config.Cooldown {
+		return false
+	}
+	b.probing = true
+	return true
+}
+
+// record updates the circuit with the outcome of a write to the output.
+func (b *CircuitBreaker) record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+	if err == nil {
+		b.failures = 0
+		b.open = false
+		return
+	}
+	b.failures++
+	if b.open || b.failures >= b.config.Failures {
+		b.open = true
+		b.openedAt = b.now()
+	}
+}
+
+// fallback writes p to the fallback writer, if any.
+func (b *CircuitBreaker) fallback(p []byte) (int, error) {
+	if b.config.Fallback == nil {
+		return 0, ErrCircuitOpen
+	}
+	return b.config.Fallback.Write(p)
+}
+
+// Open reports whether the circuit is open.
+func (b *CircuitBreaker) Open() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.open
+}
+
+// Health returns ErrCircuitOpen while the circuit is open, and otherwise the
+// health of the output if it can report it.
+func (b *CircuitBreaker) Health(ctx context.Context) error {
+	if b.Open() {
+		return ErrCircuitOpen
+	}
+	if checker, ok := b.config.Output.(HealthChecker); ok {
+		return checker.Health(ctx)
+	}
+	return nil
+}
+
+// Ping pings the output if it can be pinged or health-checked, even while the
+// circuit is open.
+func (b *CircuitBreaker) Ping(ctx context.Context) error {
+	return pingOutput(ctx, b.config.Output)
+}
+
+// Sync flushes the output while the circuit is closed, and the fallback.
+func (b *CircuitBreaker) Sync() error {
+	var err error
+	if s, ok := b.config.Output.(syncer); ok && !b.Open() {
+		err = s.Sync()
+		b.record(err)
+	}
+	if s, ok := b.config.Fallback.(syncer); ok {
+		if fallbackErr := s.Sync(); err == nil {
+			err = fallbackErr
+		}
+	}
+	return err
+}
+
+// now returns the current time from the configured clock.
+func (b *CircuitBreaker) now() time.Time {
+	if b.config.Clock != nil {
+		return b.config.Clock.Now()
+	}
+	return time.Now()
+}
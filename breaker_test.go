@@ -0,0 +1,84 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestCircuitBreaker tests that the circuit opens after repeated failures and
+// closes after a successful probe.
+func TestCircuitBreaker(t *testing.T) {
+	remote := &flakyWriter{fail: true}
+	fallback := new(bytes.Buffer)
+	clock := &manualClock{now: time.Unix(0, 0)}
+	breaker := NewCircuitBreaker(CircuitBreakerConfig{
+		Output:   remote,
+		Fallback: fallback,
+		Failures: 2,
+		Cooldown: time.Minute,
+		Clock:    clock,
+	})
+
+	for i := 0; i < 3; i++ {
+		if _, err := breaker.Write([]byte("a\n")); err != nil {
+			t.Errorf("Expected the fallback to absorb the failure, got %v", err)
+		}
+	}
+	if !breaker.Open() {
+		t.Fatalf("Expected the circuit to open after 2 failures")
+	}
+	if remote.attempts != 2 {
+		t.Errorf("Expected no attempts while open, got %d", remote.attempts)
+	}
+	if fallback.String() != "a\na\na\n" {
+		t.Errorf("Expected every entry in the fallback, got %q", fallback.String())
+	}
+
+	clock.now = clock.now.Add(time.Minute)
+	breaker.Write([]byte("b\n"))
+	if remote.attempts != 3 || !breaker.Open() {
+		t.Errorf("Expected a failed probe to keep the circuit open")
+	}
+
+	remote.fail = false
+	breaker.Write([]byte("c\n"))
+	if remote.attempts != 3 {
+		t.Errorf("Expected no probe before the cooldown passes again")
+	}
+	clock.now = clock.now.Add(time.Minute)
+	breaker.Write([]byte("d\n"))
+	if breaker.Open() || remote.written.String() != "d\n" {
+		t.Errorf("Expected a successful probe to close the circuit, got %q", remote.written.String())
+	}
+}
+
+// TestCircuitBreaker_NoFallback tests that writes are rejected while open without a fallback.
+func TestCircuitBreaker_NoFallback(t *testing.T) {
+	breaker := NewCircuitBreaker(CircuitBreakerConfig{Output: failingWriter{}, Failures: 1})
+
+	breaker.Write([]byte("a"))
+	if _, err := breaker.Write([]byte("b")); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("Expected ErrCircuitOpen, got %v", err)
+	}
+	if err := breaker.Health(context.Background()); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("Expected an unhealthy breaker, got %v", err)
+	}
+}
+
+// flakyWriter fails while fail is set and counts write attempts.
+type flakyWriter struct {
+	fail     bool
+	attempts int
+	written  bytes.Buffer
+}
+
+func (w *flakyWriter) Write(p []byte) (int, error) {
+	w.attempts++
+	if w.fail {
+		return 0, errors.New("connection refused")
+	}
+	return w.written.Write(p)
+}
@@ -0,0 +1,37 @@
+package logger
+
+import (
+	"runtime/debug"
+	"sync"
+)
+
+var (
+	buildInfoOnce   sync.Once
+	buildInfoFields Fields
+)
+
+// BuildInfo returns fields identifying the running build, read once from the
+// build information embedded by the go command: "version", the main module
+// version, and "vcs.revision", "vcs.time", and "vcs.modified" from version
+// control. Values that are not available, such as the version of a
+// development build, are omitted. The returned map must not be modified.
+// Set Config.BuildInfo to add the fields to every entry.
+func BuildInfo() Fields {
+	buildInfoOnce.Do(func() {
+		buildInfoFields = Fields{}
+		info, ok := debug.ReadBuildInfo()
+		if !ok {
+			return
+		}
+		if v := info.Main.Version; v != "" && v != "(devel)" {
+			buildInfoFields["version"] = v
+		}
+		for _, setting := range info.Settings {
+			switch setting.Key {
+			case "vcs.revision", "vcs.time", "vcs.modified":
+				buildInfoFields[setting.Key] = setting.Value
+			}
+		}
+	})
+	return buildInfoFields
+}
@@ -0,0 +1,31 @@
+package logger
+
+import (
+	"bytes"
+	"testing"
+)
+
+// TestZap_BuildInfo tests that the build fields are added to every entry.
+func TestZap_BuildInfo(t *testing.T) {
+	BuildInfo()
+	saved := buildInfoFields
+	defer func() { buildInfoFields = saved }()
+	buildInfoFields = Fields{"version": "v1.4.0", "vcs.revision": "4f2c9e1", "vcs.time": "2024-03-01T12:00:00Z"}
+
+	buffer := new(bytes.Buffer)
+	zapLogger := NewZap(Config{Level: InfoLevel, Output: buffer, BuildInfo: true})
+	zapLogger.Info("started", nil)
+	zapLogger.With(Fields{"component": "db"}).Info("connected", nil)
+
+	for _, expected := range []string{`"version":"v1.4.0"`, `"vcs.revision":"4f2c9e1"`, `"vcs.time":"2024-03-01T12:00:00Z"`} {
+		if c := bytes.Count(buffer.Bytes(), []byte(expected)); c != 2 {
+			t.Errorf("Expected %s in both entries of %s", expected, buffer.String())
+		}
+	}
+
+	buffer.Reset()
+	NewZap(Config{Level: InfoLevel, Output: buffer}).Info("started", nil)
+	if bytes.Contains(buffer.Bytes(), []byte("vcs.revision")) {
+		t.Errorf("Expected no build fields by default, got %s", buffer.String())
+	}
+}
@@ -0,0 +1,102 @@
+package logger
+
+import (
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+// cefVersion is the CEF format version written in every header.
+const cefVersion = "0"
+
+// defaultCEFSignatureKey is the default CEFConfig.SignatureKey.
+const defaultCEFSignatureKey = "event"
+
+// CEFConfig holds the header settings and key mapping of FormatCEF output.
+type CEFConfig struct {
+	// Vendor, Product, and Version identify the device in the CEF header and
+	// are required for FormatCEF outputs.
+	Vendor  string
+	Product string
+	Version string
+	// SignatureKey names the field holding the event type, written as the
+	// Signature ID; defaults to "event". Entries without it use the message.
+	SignatureKey string
+	// Keys maps field keys to CEF extension keys, e.g. "user": "suser" or
+	// "ip": "src". Unmapped keys are written as they are.
+	Keys map[string]string
+}
+
+// cefSeverity maps levels to CEF severities, from 0 (lowest) to 10.
+var cefSeverity = map[zapcore.Level]int{
+	zapcore.DebugLevel:  1,
+	zapcore.InfoLevel:   3,
+	zapcore.WarnLevel:   5,
+	zapcore.ErrorLevel:  8,
+	zapcore.DPanicLevel: 9,
+	zapcore.PanicLevel:  9,
+	zapcore.FatalLevel:  10,
+}
+
+// Escapers for the CEF header and extension values.
+var (
+	cefHeaderEscaper = strings.NewReplacer(`\`, `\\`, `|`, `\|`, "\n", " ", "\r", " ")
+	cefValueEscaper  = strings.NewReplacer(`\`, `\\`, `=`, `\=`, "\n", `\n`, "\r", `\r`)
+)
+
+// newCEFCore returns a core writing Common Event Format entries, one per line:
+// the header holds the device, signature, message, and severity, and the
+// extension holds rt (the time in epoch milliseconds), the caller, and the
+// fields.
+func newCEFCore(config CEFConfig, encoderConfig zapcore.EncoderConfig, ws zapcore.WriteSyncer, enabler zapcore.LevelEnabler) zapcore.Core {
+	if config.SignatureKey == "" {
+		config.SignatureKey = defaultCEFSignatureKey
+	}
+	callerKey := encoderConfig.CallerKey
+	render := func(buf *buffer.Buffer, entry zapcore.Entry, fields map[string]interface{}) error {
+		signature := entry.Message
+		if v, ok := fields[config.SignatureKey]; ok {
+			signature = formatValue(v)
+			delete(fields, config.SignatureKey)
+		}
+
+		buf.AppendString("CEF:" + cefVersion)
+		for _, s := range []string{config.Vendor, config.Product, config.Version, signature, entry.Message} {
+			buf.AppendByte('|')
+			buf.AppendString(cefHeaderEscaper.Replace(s))
+		}
+		buf.AppendByte('|')
+		buf.AppendInt(int64(cefSeverity[entry.Level]))
+		buf.AppendByte('|')
+
+		buf.AppendString("rt=")
+		buf.AppendString(strconv.FormatInt(entry.Time.UnixNano()/1e6, 10))
+		if callerKey != "" && entry.Caller.Defined {
+			appendCEFExtension(buf, config.cefKey(callerKey), entry.Caller.TrimmedPath())
+		}
+		for _, k := range sortedKeys(fields) {
+			appendCEFExtension(buf, config.cefKey(k), formatValue(fields[k]))
+		}
+		buf.AppendByte('\n')
+		return nil
+	}
+	return newMapCore(render, ws, enabler)
+}
+
+// cefKey returns the extension key for a field key.
+func (c CEFConfig) cefKey(key string) string {
+	if mapped, ok := c.Keys[key]; ok {
+		return mapped
+	}
+	return key
+}
+
+// appendCEFExtension appends " key=value" to buf.
+func appendCEFExtension(buf *buffer.Buffer, key, value string) {
+	buf.AppendByte(' ')
+	buf.AppendString(key)
+	buf.AppendByte('=')
+	buf.AppendString(cefValueEscaper.Replace(value))
+}
@@ -0,0 +1,43 @@
+package logger
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestFormat_CEF tests that entries are written as CEF header and extension.
+func TestFormat_CEF(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	zapLogger := NewZap(Config{
+		Level:         InfoLevel,
+		Output:        buffer,
+		Format:        FormatCEF,
+		DisableCaller: true,
+		Clock:         fixedClock(time.Unix(1700000000, 0)),
+		CEF: CEFConfig{
+			Vendor:  "Acme",
+			Product: "Pay|ments",
+			Version: "1.2",
+			Keys:    map[string]string{"user": "suser"},
+		},
+	})
+
+	zapLogger.With(Fields{"ip": "10.0.0.1"}).Warn("login failed", Fields{"event": "auth_failure", "user": "alice", "query": "a=b\nc"})
+
+	expected := `CEF:0|Acme|Pay\|ments|1.2|auth_failure|login failed|5|rt=1700000000000 ip=10.0.0.1 query=a\=b\nc suser=alice` + "\n"
+	if buffer.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, buffer.String())
+	}
+
+	buffer.Reset()
+	zapLogger.Error("disk full", nil)
+	if !strings.HasPrefix(buffer.String(), "CEF:0|Acme|Pay\\|ments|1.2|disk full|disk full|8|") {
+		t.Errorf("Expected the message as signature, got %q", buffer.String())
+	}
+
+	if _, err := NewZapE(Config{Output: buffer, Format: FormatCEF}); err == nil {
+		t.Errorf("Expected an error without vendor and product")
+	}
+}
@@ -0,0 +1,49 @@
+package logger
+
+import (
+	"net/http"
+	"os"
+	"strings"
+)
+
+// CloudTraceHeader is the header Cloud Run and Cloud Functions set on incoming
+// requests, in the form TRACE_ID/SPAN_ID;o=OPTIONS.
+const CloudTraceHeader = "X-Cloud-Trace-Context"
+
+// CloudTraceFields returns the Cloud Logging trace fields for an
+// X-Cloud-Trace-Context header value, so entries nest under the request trace.
+// projectID defaults to the GOOGLE_CLOUD_PROJECT environment variable. It
+// returns nil if the header has no trace ID or no project is known.
+func CloudTraceFields(projectID, header string) Fields {
+	if projectID == "" {
+		projectID = os.Getenv("GOOGLE_CLOUD_PROJECT")
+	}
+	traceID, rest, _ := strings.Cut(header, "/")
+	if traceID == "" || projectID == "" {
+		return nil
+	}
+
+	fields := Fields{"logging.googleapis.com/trace": "projects/" + projectID + "/traces/" + traceID}
+	spanID, options, _ := strings.Cut(rest, ";")
+	if spanID != "" {
+		fields["logging.googleapis.com/spanId"] = spanID
+	}
+	if options != "" {
+		fields["logging.googleapis.com/trace_sampled"] = options == "o=1"
+	}
+	return fields
+}
+
+// CloudTraceHandler returns an http.Handler that serves each request with a
+// context carrying a child of l bound to the request's Cloud Logging trace
+// fields, retrievable with FromContext. Requests without a trace header are
+// served unchanged.
+func CloudTraceHandler(l Logger, projectID string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fields := CloudTraceFields(projectID, r.Header.Get(CloudTraceHeader)); fields != nil {
+			ctx := NewContext(r.Context(), With(contextLogger(r.Context(), l), fields))
+			r = r.WithContext(ctx)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,52 @@
+package logger
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+// TestCloudTraceFields tests parsing of X-Cloud-Trace-Context values.
+func TestCloudTraceFields(t *testing.T) {
+	tests := []struct {
+		header   string
+		expected Fields
+	}{
+		{"105445aa7843bc8bf206b12000100000/1;o=1", Fields{
+			"logging.googleapis.com/trace":         "projects/my-project/traces/105445aa7843bc8bf206b12000100000",
+			"logging.googleapis.com/spanId":        "1",
+			"logging.googleapis.com/trace_sampled": true,
+		}},
+		{"105445aa7843bc8bf206b12000100000", Fields{
+			"logging.googleapis.com/trace": "projects/my-project/traces/105445aa7843bc8bf206b12000100000",
+		}},
+		{"", nil},
+	}
+	for _, test := range tests {
+		if got := CloudTraceFields("my-project", test.header); !reflect.DeepEqual(got, test.expected) {
+			t.Errorf("Expected %v for %q, got %v", test.expected, test.header, got)
+		}
+	}
+
+	t.Setenv("GOOGLE_CLOUD_PROJECT", "")
+	if got := CloudTraceFields("", "abc/1"); got != nil {
+		t.Errorf("Expected no fields without a project, got %v", got)
+	}
+}
+
+// TestCloudTraceHandler tests that request logs carry the trace field.
+func TestCloudTraceHandler(t *testing.T) {
+	observed, logs := Observe(Config{Level: InfoLevel})
+	handler := CloudTraceHandler(observed, "my-project", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		FromContext(r.Context()).Info("handled", nil)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(CloudTraceHeader, "abc/1;o=0")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	if logs.FilterField("logging.googleapis.com/trace", "projects/my-project/traces/abc").Len() != 1 {
+		t.Errorf("Expected an entry with the trace field, got %v", logs.All())
+	}
+}
@@ -0,0 +1,199 @@
+// Command logpretty renders the JSON log stream written by the logger package
+// as colorized, human-readable lines.
+//
+//	kubectl logs deploy/api | logpretty -level warn -fields user,order_id
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// ANSI escape sequences used for colorized output.
+const (
+	colorReset  = "\x1b[0m"
+	colorGray   = "\x1b[90m"
+	colorRed    = "\x1b[31m"
+	colorYellow = "\x1b[33m"
+	colorBlue   = "\x1b[34m"
+	colorCyan   = "\x1b[36m"
+)
+
+// levels orders the level names written by the logger.
+var levels = map[string]int{
+	"debug":  0,
+	"info":   1,
+	"warn":   2,
+	"error":  3,
+	"dpanic": 4,
+	"panic":  4,
+	"fatal":  4,
+}
+
+// coreKeys are rendered in the line prefix rather than as fields.
+var coreKeys = map[string]bool{"level": true, "ts": true, "msg": true, "caller": true}
+
+// options controls rendering.
+type options struct {
+	minLevel int
+	fields   []string
+	color    bool
+	caller   bool
+}
+
+func main() {
+	level := flag.String("level", "debug", "minimum level to show (debug, info, warn, error, fatal)")
+	fields := flag.String("fields", "", "comma-separated fields to show; all fields when empty")
+	noColor := flag.Bool("no-color", false, "disable colorized output")
+	caller := flag.Bool("caller", false, "show the caller")
+	flag.Parse()
+
+	minLevel, ok := levels[strings.ToLower(*level)]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "logpretty: unknown level %q\n", *level)
+		os.Exit(2)
+	}
+
+	opts := options{minLevel: minLevel, color: !*noColor, caller: *caller}
+	if *fields != "" {
+		opts.fields = strings.Split(*fields, ",")
+	}
+
+	if err := pretty(os.Stdin, os.Stdout, opts); err != nil {
+		fmt.Fprintf(os.Stderr, "logpretty: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// pretty renders every line of in to out. Lines that are not JSON objects are
+// copied unchanged.
+func pretty(in io.Reader, out io.Writer, opts options) error {
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	writer := bufio.NewWriter(out)
+	defer writer.Flush()
+
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		entry, ok := decode(line)
+		if !ok {
+			writer.Write(line)
+			writer.WriteByte('\n')
+			continue
+		}
+		if level, ok := levels[fmt.Sprint(entry["level"])]; ok && level < opts.minLevel {
+			continue
+		}
+		writer.WriteString(render(entry, opts))
+		writer.WriteByte('\n')
+	}
+	return scanner.Err()
+}
+
+// decode parses a JSON object, reporting whether line was one.
+func decode(line []byte) (map[string]interface{}, bool) {
+	line = bytes.TrimSpace(line)
+	if len(line) == 0 || line[0] != '{' {
+		return nil, false
+	}
+	decoder := json.NewDecoder(bytes.NewReader(line))
+	decoder.UseNumber()
+	var entry map[string]interface{}
+	if err := decoder.Decode(&entry); err != nil {
+		return nil, false
+	}
+	return entry, true
+}
+
+// render formats a decoded entry as a single line.
+func render(entry map[string]interface{}, opts options) string {
+	var b strings.Builder
+
+	if ts, ok := entry["ts"]; ok {
+		b.WriteString(paint(fmt.Sprint(ts), colorGray, opts.color))
+		b.WriteByte(' ')
+	}
+	level := fmt.Sprint(entry["level"])
+	b.WriteString(paint(fmt.Sprintf("%-5s", strings.ToUpper(level)), levelColor(level), opts.color))
+	b.WriteByte(' ')
+	b.WriteString(fmt.Sprint(entry["msg"]))
+
+	for _, key := range fieldKeys(entry, opts.fields) {
+		value, ok := entry[key]
+		if !ok {
+			continue
+		}
+		b.WriteByte(' ')
+		b.WriteString(paint(key+"=", colorCyan, opts.color))
+		b.WriteString(formatValue(value))
+	}
+
+	if caller, ok := entry["caller"]; ok && opts.caller {
+		b.WriteByte(' ')
+		b.WriteString(paint(fmt.Sprint(caller), colorGray, opts.color))
+	}
+	return b.String()
+}
+
+// fieldKeys returns the keys to render: the selected ones, or all non-core keys sorted.
+func fieldKeys(entry map[string]interface{}, selected []string) []string {
+	if len(selected) > 0 {
+		return selected
+	}
+	keys := make([]string, 0, len(entry))
+	for key := range entry {
+		if !coreKeys[key] {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// formatValue renders a field value, quoting strings that contain spaces.
+func formatValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		if strings.ContainsAny(v, " \t\"=") {
+			return fmt.Sprintf("%q", v)
+		}
+		return v
+	case json.Number:
+		return v.String()
+	default:
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(encoded)
+	}
+}
+
+// levelColor returns the color used for a level name.
+func levelColor(level string) string {
+	switch level {
+	case "debug":
+		return colorBlue
+	case "info":
+		return colorCyan
+	case "warn":
+		return colorYellow
+	default:
+		return colorRed
+	}
+}
+
+// paint wraps s in color when enabled.
+func paint(s, color string, enabled bool) string {
+	if !enabled {
+		return s
+	}
+	return color + s + colorReset
+}
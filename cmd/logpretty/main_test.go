@@ -0,0 +1,47 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// TestPretty tests rendering, level filtering, and field selection.
+func TestPretty(t *testing.T) {
+	input := strings.Join([]string{
+		`{"level":"debug","ts":"2024-03-01T12:00:00Z","msg":"noise"}`,
+		`{"level":"info","ts":"2024-03-01T12:00:01Z","caller":"app/main.go:10","msg":"user created","user":"alice","admin":false}`,
+		`plain text line`,
+		`{"level":"error","ts":"2024-03-01T12:00:02Z","msg":"payment failed","order_id":42,"reason":"card declined"}`,
+	}, "\n")
+
+	out := new(bytes.Buffer)
+	if err := pretty(strings.NewReader(input), out, options{minLevel: levels["info"]}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := strings.Join([]string{
+		`2024-03-01T12:00:01Z INFO  user created admin=false user=alice`,
+		`plain text line`,
+		`2024-03-01T12:00:02Z ERROR payment failed order_id=42 reason="card declined"`,
+	}, "\n") + "\n"
+	if out.String() != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, out.String())
+	}
+
+	out.Reset()
+	if err := pretty(strings.NewReader(input), out, options{minLevel: levels["error"], fields: []string{"order_id"}}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(out.String(), "payment failed order_id=42\n") {
+		t.Errorf("Expected only the selected field, got %s", out.String())
+	}
+}
+
+// TestRender_Color tests that levels are colorized when enabled.
+func TestRender_Color(t *testing.T) {
+	line := render(map[string]interface{}{"level": "warn", "msg": "m"}, options{color: true})
+	if !strings.Contains(line, colorYellow+"WARN ") {
+		t.Errorf("Expected a yellow level, got %q", line)
+	}
+}
@@ -0,0 +1,303 @@
+// Command logreplay replays NDJSON entries captured from the logger package
+// through a configured set of outputs and encodings, at a controlled rate, to
+// try out new sink configurations or load-test collectors.
+//
+//	logreplay -in captured.ndjson -out gelf:unix:///run/graylog.sock -rate 500
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ralonr/logger"
+)
+
+// coreKeys are replayed as the entry's level, time, and message rather than as fields.
+var coreKeys = map[string]bool{"level": true, "ts": true, "msg": true}
+
+// levels maps level names that logger.ParseLevel does not know.
+var levels = map[string]logger.Level{
+	"dpanic": logger.ErrorLevel,
+	"panic":  logger.ErrorLevel,
+}
+
+// outputs collects the repeatable -out flag.
+type outputs []string
+
+// String implements flag.Value.
+func (o *outputs) String() string {
+	return strings.Join(*o, ",")
+}
+
+// Set implements flag.Value.
+func (o *outputs) Set(s string) error {
+	*o = append(*o, s)
+	return nil
+}
+
+// options controls replaying.
+type options struct {
+	// rate is the number of entries per second; zero replays as fast as possible.
+	rate float64
+	// minLevel drops entries below it.
+	minLevel logger.Level
+	// now replaces the original timestamps with the replay time.
+	now bool
+}
+
+func main() {
+	var outs outputs
+	in := flag.String("in", "-", "file of captured NDJSON entries; - reads stdin")
+	flag.Var(&outs, "out", "output as format:path, e.g. json:stdout, gelf:/var/log/app.gelf or cef:unix:///dev/log; repeatable (default json:stdout)")
+	rate := flag.Float64("rate", 0, "entries per second; 0 replays as fast as possible")
+	level := flag.String("level", "debug", "minimum level to replay (debug, info, warn, error, fatal)")
+	repeat := flag.Int("repeat", 1, "number of times to replay the input; requires -in")
+	now := flag.Bool("now", false, "stamp entries with the replay time instead of the original")
+	flag.Parse()
+
+	minLevel, err := logger.ParseLevel(*level)
+	if err != nil {
+		fail(2, err)
+	}
+	if len(outs) == 0 {
+		outs = outputs{"json:stdout"}
+	}
+	if *repeat > 1 && *in == "-" {
+		fail(2, fmt.Errorf("-repeat requires -in"))
+	}
+
+	clock := &replayClock{}
+	var loggers []*logger.Zap
+	for _, out := range outs {
+		z, err := newOutput(out, clock)
+		if err != nil {
+			fail(2, err)
+		}
+		loggers = append(loggers, z)
+	}
+
+	opts := options{rate: *rate, minLevel: minLevel, now: *now}
+	p := newPacer(opts.rate)
+	total := 0
+	for i := 0; i < *repeat; i++ {
+		input, closeInput, err := openInput(*in)
+		if err != nil {
+			fail(1, err)
+		}
+		n, err := replay(input, loggers, clock, p, opts)
+		closeInput()
+		total += n
+		if err != nil {
+			fail(1, err)
+		}
+	}
+	for _, z := range loggers {
+		z.Sync()
+	}
+	fmt.Fprintf(os.Stderr, "logreplay: replayed %d entries\n", total)
+}
+
+// fail reports err and exits with code.
+func fail(code int, err error) {
+	fmt.Fprintf(os.Stderr, "logreplay: %v\n", err)
+	os.Exit(code)
+}
+
+// openInput opens the input file, or stdin for "-".
+func openInput(path string) (io.Reader, func(), error) {
+	if path == "-" {
+		return os.Stdin, func() {}, nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	return f, func() { f.Close() }, nil
+}
+
+// newOutput returns a logger writing to the output described by spec, in the
+// form format:path, with timestamps taken from clock.
+func newOutput(spec string, clock logger.Clock) (*logger.Zap, error) {
+	name, path := spec, "stdout"
+	if i := strings.Index(spec, ":"); i >= 0 {
+		name, path = spec[:i], spec[i+1:]
+	}
+	format, err := logger.ParseFormat(name)
+	if err != nil {
+		return nil, fmt.Errorf("output %q: %w", spec, err)
+	}
+	return logger.NewZapE(logger.Config{
+		Level:         logger.DebugLevel,
+		OutputPath:    path,
+		Format:        format,
+		DisableCaller: true,
+		Clock:         clock,
+		OnFatal:       logger.FatalNone,
+	})
+}
+
+// replayClock reports the time of the entry being replayed.
+type replayClock struct {
+	t time.Time
+}
+
+// Now returns the time of the current entry.
+func (c *replayClock) Now() time.Time {
+	return c.t
+}
+
+// pacer spaces entries to a fixed rate.
+type pacer struct {
+	interval time.Duration
+	next     time.Time
+	now      func() time.Time
+	sleep    func(time.Duration)
+}
+
+// newPacer returns a pacer for rate entries per second; zero does not wait.
+func newPacer(rate float64) *pacer {
+	p := &pacer{now: time.Now, sleep: time.Sleep}
+	if rate > 0 {
+		p.interval = time.Duration(float64(time.Second) / rate)
+	}
+	return p
+}
+
+// wait blocks until the next entry may be replayed.
+func (p *pacer) wait() {
+	if p.interval == 0 {
+		return
+	}
+	now := p.now()
+	if p.next.IsZero() || p.next.Before(now) {
+		p.next = now
+	}
+	if d := p.next.Sub(now); d > 0 {
+		p.sleep(d)
+	}
+	p.next = p.next.Add(p.interval)
+}
+
+// replay writes every JSON entry of in to loggers and returns the number of
+// entries replayed. Lines that are not JSON objects are skipped.
+func replay(in io.Reader, loggers []*logger.Zap, clock *replayClock, p *pacer, opts options) (int, error) {
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	n := 0
+	for scanner.Scan() {
+		entry, ok := decode(scanner.Bytes())
+		if !ok {
+			continue
+		}
+		level := entryLevel(entry)
+		if level < opts.minLevel {
+			continue
+		}
+		p.wait()
+
+		clock.t = time.Now()
+		if t, ok := entryTime(entry["ts"]); ok && !opts.now {
+			clock.t = t
+		}
+		msg, _ := entry["msg"].(string)
+		fields := logger.Fields{}
+		for k, v := range entry {
+			if !coreKeys[k] {
+				fields[k] = number(v)
+			}
+		}
+		for _, z := range loggers {
+			write(z, level, msg, fields)
+		}
+		n++
+	}
+	return n, scanner.Err()
+}
+
+// write logs the entry at level.
+func write(z *logger.Zap, level logger.Level, msg string, fields logger.Fields) {
+	switch level {
+	case logger.DebugLevel:
+		z.Debug(msg, fields)
+	case logger.InfoLevel:
+		z.Info(msg, fields)
+	case logger.WarnLevel:
+		z.Warn(msg, fields)
+	case logger.ErrorLevel:
+		z.Error(msg, fields)
+	default:
+		z.Fatal(msg, fields)
+	}
+}
+
+// decode parses a JSON object, reporting whether line was one.
+func decode(line []byte) (map[string]interface{}, bool) {
+	line = bytes.TrimSpace(line)
+	if len(line) == 0 || line[0] != '{' {
+		return nil, false
+	}
+	decoder := json.NewDecoder(bytes.NewReader(line))
+	decoder.UseNumber()
+	var entry map[string]interface{}
+	if err := decoder.Decode(&entry); err != nil {
+		return nil, false
+	}
+	return entry, true
+}
+
+// entryLevel returns the level of a decoded entry, defaulting to info.
+func entryLevel(entry map[string]interface{}) logger.Level {
+	name, _ := entry["level"].(string)
+	if level, err := logger.ParseLevel(name); err == nil {
+		return level
+	}
+	if level, ok := levels[strings.ToLower(name)]; ok {
+		return level
+	}
+	return logger.InfoLevel
+}
+
+// entryTime parses a ts value written as an RFC 3339 string or Unix milliseconds.
+func entryTime(v interface{}) (time.Time, bool) {
+	switch ts := v.(type) {
+	case string:
+		if t, err := time.Parse(time.RFC3339Nano, ts); err == nil {
+			return t, true
+		}
+	case json.Number:
+		if millis, err := ts.Int64(); err == nil {
+			return time.Unix(0, millis*int64(time.Millisecond)), true
+		}
+	}
+	return time.Time{}, false
+}
+
+// number converts JSON numbers to int64 or float64, recursively, so that they
+// are re-encoded as numbers.
+func number(v interface{}) interface{} {
+	switch val := v.(type) {
+	case json.Number:
+		if i, err := val.Int64(); err == nil {
+			return i
+		}
+		f, _ := val.Float64()
+		return f
+	case map[string]interface{}:
+		for k, item := range val {
+			val[k] = number(item)
+		}
+	case []interface{}:
+		for i, item := range val {
+			val[i] = number(item)
+		}
+	}
+	return v
+}
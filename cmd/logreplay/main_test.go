@@ -0,0 +1,71 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ralonr/logger"
+)
+
+// TestReplay tests that entries are replayed with their level, time, and fields.
+func TestReplay(t *testing.T) {
+	input := strings.Join([]string{
+		`{"level":"debug","ts":"2024-03-01T12:00:00Z","msg":"noise"}`,
+		`{"level":"info","ts":"2024-03-01T12:00:01Z","msg":"user created","user":"alice"}`,
+		`plain text line`,
+		`{"level":"dpanic","ts":1709294402000,"msg":"payment failed","order":{"id":7}}`,
+	}, "\n")
+
+	clock := &replayClock{}
+	jsonOut := new(bytes.Buffer)
+	csvOut := new(bytes.Buffer)
+	loggers := []*logger.Zap{
+		logger.NewZap(logger.Config{Level: logger.DebugLevel, Output: jsonOut, DisableCaller: true, Clock: clock}),
+		logger.NewZap(logger.Config{Level: logger.DebugLevel, Output: csvOut, Format: logger.FormatCSV, CSV: logger.CSVConfig{Columns: []string{"user"}}, DisableCaller: true, Clock: clock}),
+	}
+
+	n, err := replay(strings.NewReader(input), loggers, clock, newPacer(0), options{minLevel: logger.InfoLevel})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if n != 2 {
+		t.Errorf("Expected 2 entries, got %d", n)
+	}
+
+	expected := `{"level":"info","ts":"2024-03-01T12:00:01Z","msg":"user created","user":"alice"}` + "\n" +
+		`{"level":"error","ts":"2024-03-01T12:00:02Z","msg":"payment failed","order":{"id":7}}` + "\n"
+	if jsonOut.String() != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, jsonOut.String())
+	}
+	if csvOut.String() != "2024-03-01T12:00:01Z,info,user created,alice\n2024-03-01T12:00:02Z,error,payment failed,\n" {
+		t.Errorf("Expected CSV rows, got %q", csvOut.String())
+	}
+}
+
+// TestPacer tests that entries are spaced at the configured rate.
+func TestPacer(t *testing.T) {
+	p := newPacer(10)
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	start := now
+	p.now = func() time.Time { return now }
+	p.sleep = func(d time.Duration) { now = now.Add(d) }
+
+	for i := 0; i < 3; i++ {
+		p.wait()
+	}
+	if waited := now.Sub(start); waited != 200*time.Millisecond {
+		t.Errorf("Expected 200ms of waiting, got %s", waited)
+	}
+}
+
+// TestNewOutput tests parsing of output specifications.
+func TestNewOutput(t *testing.T) {
+	if _, err := newOutput("xml:stdout", &replayClock{}); err == nil {
+		t.Errorf("Expected an error for an unknown format")
+	}
+	if _, err := newOutput("console", &replayClock{}); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
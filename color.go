@@ -0,0 +1,26 @@
+package logger
+
+import "go.uber.org/zap/zapcore"
+
+// ANSI escape sequences used for colorized console output.
+const (
+	colorReset   = "\x1b[0m"
+	colorRed     = "\x1b[31m"
+	colorYellow  = "\x1b[33m"
+	colorBlue    = "\x1b[34m"
+	colorMagenta = "\x1b[35m"
+)
+
+// levelColor returns the escape sequence that colors entries at l.
+func levelColor(l zapcore.Level) string {
+	switch {
+	case l <= zapcore.DebugLevel:
+		return colorMagenta
+	case l == zapcore.InfoLevel:
+		return colorBlue
+	case l == zapcore.WarnLevel:
+		return colorYellow
+	default:
+		return colorRed
+	}
+}
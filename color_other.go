@@ -0,0 +1,11 @@
+//go:build !windows
+
+package logger
+
+import "io"
+
+// colorOutput returns the writer to encode colorized output to and whether
+// color can be shown. Terminals outside Windows understand ANSI escapes.
+func colorOutput(w io.Writer) (io.Writer, bool) {
+	return w, true
+}
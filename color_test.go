@@ -0,0 +1,34 @@
+package logger
+
+import (
+	"bytes"
+	"testing"
+)
+
+// TestZap_Color tests that levels are colorized only in console output.
+func TestZap_Color(t *testing.T) {
+	console := new(bytes.Buffer)
+	dest := new(bytes.Buffer)
+	zapLogger := NewZap(Config{
+		Level:           InfoLevel,
+		Output:          console,
+		Format:          FormatConsole,
+		Color:           true,
+		UppercaseLevels: true,
+		Destinations:    []Destination{{Output: dest, Format: FormatConsole}},
+	})
+
+	zapLogger.Warn("Warn message", nil)
+	if !bytes.Contains(console.Bytes(), []byte("\t"+colorYellow+"WARN"+colorReset+"\t")) {
+		t.Errorf("Expected a colorized level, got %q", console.String())
+	}
+	if bytes.Contains(dest.Bytes(), []byte("\x1b[")) {
+		t.Errorf("Expected no color in destinations, got %q", dest.String())
+	}
+
+	jsonOut := new(bytes.Buffer)
+	NewZap(Config{Level: InfoLevel, Output: jsonOut, Color: true}).Info("Info message", nil)
+	if bytes.Contains(jsonOut.Bytes(), []byte("\x1b[")) {
+		t.Errorf("Expected no color in JSON output, got %q", jsonOut.String())
+	}
+}
@@ -0,0 +1,125 @@
+//go:build windows
+
+package logger
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strconv"
+	"sync"
+	"syscall"
+	"unsafe"
+)
+
+// enableVirtualTerminalProcessing is the console mode flag that makes the
+// console interpret ANSI escapes, available since Windows 10.
+const enableVirtualTerminalProcessing = 0x0004
+
+// Console text attributes used when falling back to the console API.
+const (
+	foregroundBlue      = 0x1
+	foregroundGreen     = 0x2
+	foregroundRed       = 0x4
+	foregroundIntensity = 0x8
+)
+
+var (
+	kernel32                       = syscall.NewLazyDLL("kernel32.dll")
+	procSetConsoleMode             = kernel32.NewProc("SetConsoleMode")
+	procSetConsoleTextAttribute    = kernel32.NewProc("SetConsoleTextAttribute")
+	procGetConsoleScreenBufferInfo = kernel32.NewProc("GetConsoleScreenBufferInfo")
+)
+
+// consoleScreenBufferInfo mirrors CONSOLE_SCREEN_BUFFER_INFO.
+type consoleScreenBufferInfo struct {
+	size              [2]int16
+	cursorPosition    [2]int16
+	attributes        uint16
+	window            [4]int16
+	maximumWindowSize [2]int16
+}
+
+// colorOutput returns the writer to encode colorized output to and whether
+// color can be shown. Writers that are not files are left alone; files that are
+// not consoles get no color. On consoles, virtual terminal processing is
+// enabled, or, where it is unsupported, escapes are translated into console
+// API calls.
+func colorOutput(w io.Writer) (io.Writer, bool) {
+	f, ok := w.(*os.File)
+	if !ok {
+		return w, true
+	}
+	handle := syscall.Handle(f.Fd())
+	var mode uint32
+	if err := syscall.GetConsoleMode(handle, &mode); err != nil {
+		return w, false
+	}
+	if r, _, _ := procSetConsoleMode.Call(uintptr(handle), uintptr(mode|enableVirtualTerminalProcessing)); r != 0 {
+		return w, true
+	}
+	var info consoleScreenBufferInfo
+	if r, _, _ := procGetConsoleScreenBufferInfo.Call(uintptr(handle), uintptr(unsafe.Pointer(&info))); r == 0 {
+		return w, false
+	}
+	return &consoleWriter{file: f, handle: handle, reset: info.attributes}, true
+}
+
+// consoleWriter writes to a console without virtual terminal processing,
+// turning the color escapes of this package into text attributes.
+type consoleWriter struct {
+	mu     sync.Mutex
+	file   *os.File
+	handle syscall.Handle
+	reset  uint16
+}
+
+// Write writes p, applying each "ESC [ n m" escape as a text attribute.
+func (c *consoleWriter) Write(p []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	rest := p
+	for len(rest) > 0 {
+		i := bytes.Index(rest, []byte("\x1b["))
+		if i < 0 {
+			break
+		}
+		if _, err := c.file.Write(rest[:i]); err != nil {
+			return 0, err
+		}
+		end := bytes.IndexByte(rest[i:], 'm')
+		if end < 0 {
+			rest = rest[i:]
+			break
+		}
+		code, _ := strconv.Atoi(string(rest[i+2 : i+end]))
+		procSetConsoleTextAttribute.Call(uintptr(c.handle), uintptr(c.attribute(code)))
+		rest = rest[i+end+1:]
+	}
+	if _, err := c.file.Write(rest); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Sync flushes the console.
+func (c *consoleWriter) Sync() error {
+	return c.file.Sync()
+}
+
+// attribute returns the text attribute for an ANSI color code.
+func (c *consoleWriter) attribute(code int) uint16 {
+	background := c.reset &^ 0xf
+	switch code {
+	case 31:
+		return background | foregroundRed | foregroundIntensity
+	case 33:
+		return background | foregroundRed | foregroundGreen | foregroundIntensity
+	case 34:
+		return background | foregroundBlue | foregroundIntensity
+	case 35:
+		return background | foregroundRed | foregroundBlue | foregroundIntensity
+	}
+	return c.reset
+}
@@ -0,0 +1,70 @@
+package logger
+
+import "context"
+
+// contextKey is the type of the keys this package stores in a context.
+type contextKey int
+
+const (
+	loggerKey contextKey = iota
+	requestIDKey
+	correlationIDKey
+	workerKey
+	fieldsKey
+)
+
+// NewContext returns a copy of ctx carrying l.
+func NewContext(ctx context.Context, l Logger) context.Context {
+	return context.WithValue(ctx, loggerKey, l)
+}
+
+// FromContext returns the logger carried by ctx, or Default if there is none.
+func FromContext(ctx context.Context) Logger {
+	if l, ok := ctx.Value(loggerKey).(Logger); ok {
+		return l
+	}
+	return Default()
+}
+
+// contextLogger returns the logger carried by ctx, or l if there is none, so
+// middleware composes by extending the logger of an outer middleware.
+func contextLogger(ctx context.Context, l Logger) Logger {
+	if cl, ok := ctx.Value(loggerKey).(Logger); ok {
+		return cl
+	}
+	return l
+}
+
+// AppendCtxFields returns a copy of ctx carrying fields in addition to those
+// added earlier along the call chain, so that middleware, authentication, and
+// handlers can each contribute fields without passing a logger around. Later
+// values win for repeated keys. The *Ctx logging methods write the
+// accumulated fields with every entry.
+func AppendCtxFields(ctx context.Context, fields Fields) context.Context {
+	if len(fields) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, fieldsKey, mergeFields(CtxFields(ctx), fields))
+}
+
+// CtxFields returns the fields accumulated in ctx with AppendCtxFields. The
+// returned map must not be modified.
+func CtxFields(ctx context.Context) Fields {
+	if ctx == nil {
+		return nil
+	}
+	fields, _ := ctx.Value(fieldsKey).(Fields)
+	return fields
+}
+
+// withCtxFields returns fields merged over the fields accumulated in ctx.
+func withCtxFields(ctx context.Context, fields Fields) Fields {
+	accumulated := CtxFields(ctx)
+	if len(accumulated) == 0 {
+		return fields
+	}
+	if len(fields) == 0 {
+		return accumulated
+	}
+	return mergeFields(accumulated, fields)
+}
@@ -0,0 +1,320 @@
+package logger
+
+import (
+	"encoding"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// fieldConverter converts field values to zap fields according to the
+// conversion settings of a Config.
+type fieldConverter struct {
+	humanFields     HumanFieldMode
+	binary          BinaryEncoding
+	maxFieldBytes   int
+	maxMessageBytes int
+	maxFields       int
+	sanitize        bool
+	keyCase         KeyCase
+	reserved        map[string]struct{}
+	reservedPolicy  ReservedKeyPolicy
+	reservedPrefix  string
+	durations       DurationFormat
+	timeLayout      string
+	timeZone        *time.Location
+}
+
+// newFieldConverter returns the converter for config.
+func newFieldConverter(config Config) *fieldConverter {
+	prefix := config.ReservedPrefix
+	if prefix == "" {
+		prefix = defaultReservedPrefix
+	}
+	return &fieldConverter{
+		humanFields:     config.HumanFields,
+		binary:          config.BinaryEncoding,
+		maxFieldBytes:   config.MaxFieldBytes,
+		maxMessageBytes: config.MaxMessageBytes,
+		maxFields:       config.MaxFields,
+		sanitize:        config.SanitizeUTF8,
+		keyCase:         config.KeyCase,
+		reserved:        reservedKeys(config),
+		reservedPolicy:  config.ReservedKeys,
+		reservedPrefix:  prefix,
+		durations:       config.DurationFormat,
+		timeLayout:      fieldTimeLayout(config),
+		timeZone:        config.TimeZone,
+	}
+}
+
+// mapToZapFields converts Fields to zap.Field with type-specific handling for better performance.
+func mapToZapFields(fields Fields) []zap.Field {
+	if len(fields) == 0 {
+		return nil
+	}
+	return (&fieldConverter{}).appendFields(make([]zap.Field, 0, len(fields)), fields)
+}
+
+// appendFields appends the conversion of fields to zapFields.
+func (c *fieldConverter) appendFields(zapFields []zap.Field, fields Fields) []zap.Field {
+	for k, v := range fields {
+		zapFields = c.appendField(zapFields, k, v)
+	}
+	return zapFields
+}
+
+// appendTyped appends the conversion of typed fields to zapFields, preserving their order.
+func (c *fieldConverter) appendTyped(zapFields []zap.Field, fields []Field) []zap.Field {
+	for _, f := range fields {
+		zapFields = c.appendField(zapFields, f.Key, f.Value)
+	}
+	return zapFields
+}
+
+// appendField appends the conversion of a single key-value pair, which may
+// produce more than one zap field.
+func (c *fieldConverter) appendField(zapFields []zap.Field, k string, v interface{}) []zap.Field {
+	switch val := v.(type) {
+	case humanDuration:
+		return c.appendHuman(zapFields, k, c.durationField(k, time.Duration(val)), time.Duration(val).String())
+	case humanBytes:
+		return c.appendHuman(zapFields, k, zap.Int64(k, int64(val)), formatBytes(int64(val)))
+	case routeTarget:
+		return append(zapFields, routeField(val))
+	case time.Duration:
+		return append(zapFields, c.durationField(k, val))
+	case time.Time:
+		return append(zapFields, c.timeField(k, val))
+	case *time.Duration:
+		if val == nil {
+			return append(zapFields, zap.Reflect(k, nil))
+		}
+		return append(zapFields, c.durationField(k, *val))
+	case *time.Time:
+		if val == nil {
+			return append(zapFields, zap.Reflect(k, nil))
+		}
+		return append(zapFields, c.timeField(k, *val))
+	case []string:
+		return append(zapFields, zap.Strings(k, val))
+	case []int:
+		return append(zapFields, zap.Ints(k, val))
+	case map[string]string:
+		return append(zapFields, zap.Object(k, stringMap(val)))
+	case []error:
+		return append(zapFields, zap.Strings(k, errorMessages(nil, val)))
+	case multiError:
+		return append(zapFields, zap.Strings(k, errorMessages(nil, val.Unwrap())))
+	case []byte:
+		return append(zapFields, c.binaryField(k, val))
+	case json.RawMessage:
+		return append(zapFields, rawJSONField(k, val))
+	case RawJSON:
+		return append(zapFields, rawJSONField(k, val))
+	case string, int, int64, float64, bool, error,
+		zapcore.ObjectMarshaler, zapcore.ArrayMarshaler:
+		return append(zapFields, toZapField(k, v))
+	case json.Marshaler:
+		if raw, err := json.Marshal(val); err == nil {
+			return append(zapFields, zap.Reflect(k, json.RawMessage(raw)))
+		}
+	case encoding.TextMarshaler:
+		if text, err := marshalText(val); err == nil {
+			return append(zapFields, zap.ByteString(k, text))
+		}
+	case fmt.Stringer:
+		return append(zapFields, zap.Stringer(k, val))
+	}
+	return append(zapFields, toZapField(k, v))
+}
+
+// finish applies sanitization, key normalization, the reserved key policy, and
+// the field limits to converted fields; truncated reports whether the message
+// was shortened.
+func (c *fieldConverter) finish(zapFields []zap.Field, truncated bool) []zap.Field {
+	zapFields = c.sanitizeFields(zapFields)
+	zapFields = c.normalizeKeys(zapFields)
+	zapFields = c.resolveReserved(zapFields)
+	zapFields = c.limitFields(zapFields)
+	return c.truncate(zapFields, truncated)
+}
+
+// limitMessage cuts msg to maxMessageBytes and reports whether it was shortened.
+func (c *fieldConverter) limitMessage(msg string) (string, bool) {
+	if c.maxMessageBytes <= 0 || len(msg) <= c.maxMessageBytes {
+		return msg, false
+	}
+	return truncateUTF8(msg, c.maxMessageBytes), true
+}
+
+// limitFields drops the fields beyond maxFields and summarizes them in a single
+// dropped_fields field listing their keys.
+func (c *fieldConverter) limitFields(zapFields []zap.Field) []zap.Field {
+	if c.maxFields <= 0 || len(zapFields) <= c.maxFields {
+		return zapFields
+	}
+
+	dropped := make([]string, 0, len(zapFields)-c.maxFields)
+	for _, f := range zapFields[c.maxFields:] {
+		dropped = append(dropped, f.Key)
+	}
+	sort.Strings(dropped)
+	for i := c.maxFields; i < len(zapFields); i++ {
+		zapFields[i] = zap.Field{}
+	}
+	return append(zapFields[:c.maxFields], zap.Strings("dropped_fields", dropped))
+}
+
+// truncate shortens string and byte values longer than maxFieldBytes in place
+// and appends a truncated=true marker if any value was shortened or the message
+// was already truncated.
+func (c *fieldConverter) truncate(zapFields []zap.Field, truncated bool) []zap.Field {
+	if c.maxFieldBytes <= 0 {
+		if truncated {
+			zapFields = append(zapFields, zap.Bool("truncated", true))
+		}
+		return zapFields
+	}
+
+	for i := range zapFields {
+		f := &zapFields[i]
+		switch f.Type {
+		case zapcore.StringType:
+			if len(f.String) > c.maxFieldBytes {
+				f.String = truncateUTF8(f.String, c.maxFieldBytes)
+				truncated = true
+			}
+		case zapcore.ByteStringType, zapcore.BinaryType:
+			if b, ok := f.Interface.([]byte); ok && len(b) > c.maxFieldBytes {
+				f.Interface = b[:c.maxFieldBytes]
+				truncated = true
+			}
+		}
+	}
+	if truncated {
+		zapFields = append(zapFields, zap.Bool("truncated", true))
+	}
+	return zapFields
+}
+
+// truncateUTF8 cuts s to at most n bytes without splitting a UTF-8 sequence.
+func truncateUTF8(s string, n int) string {
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
+
+// binaryPreviewBytes is the number of leading bytes shown by BinaryPreview.
+const binaryPreviewBytes = 32
+
+// binaryField encodes a []byte value according to the binary encoding policy.
+func (c *fieldConverter) binaryField(k string, b []byte) zap.Field {
+	switch c.binary {
+	case BinaryHex:
+		return zap.String(k, hex.EncodeToString(b))
+	case BinaryPreview:
+		if len(b) <= binaryPreviewBytes {
+			return zap.String(k, hex.EncodeToString(b))
+		}
+		return zap.String(k, fmt.Sprintf("%s... (%d bytes)", hex.EncodeToString(b[:binaryPreviewBytes]), len(b)))
+	default:
+		return zap.Binary(k, b)
+	}
+}
+
+// rawJSONField embeds raw into the output verbatim when it is valid JSON and
+// falls back to writing it as a string otherwise, so the payload is not lost.
+func rawJSONField(k string, raw []byte) zap.Field {
+	if !json.Valid(raw) {
+		return zap.ByteString(k, raw)
+	}
+	return zap.Reflect(k, json.RawMessage(raw))
+}
+
+// marshalText calls MarshalText, turning a panic (typically from a nil pointer
+// receiver) into an error.
+func marshalText(m encoding.TextMarshaler) (text []byte, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("MarshalText panicked: %v", r)
+		}
+	}()
+	return m.MarshalText()
+}
+
+// appendHuman appends the machine and/or human rendering of a value.
+func (c *fieldConverter) appendHuman(zapFields []zap.Field, k string, machine zap.Field, human string) []zap.Field {
+	switch c.humanFields {
+	case HumanMachineOnly:
+		return append(zapFields, machine)
+	case HumanTextOnly:
+		return append(zapFields, zap.String(k, human))
+	default:
+		return append(zapFields, machine, zap.String(k+"_human", human))
+	}
+}
+
+// stringMap encodes a map[string]string as an object with sorted keys, avoiding
+// the reflection zap.Any would use, e.g. for headers and tags.
+type stringMap map[string]string
+
+// MarshalLogObject implements zapcore.ObjectMarshaler.
+func (m stringMap) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		enc.AddString(k, m[k])
+	}
+	return nil
+}
+
+// multiError is implemented by errors that wrap several errors, such as the
+// result of errors.Join.
+type multiError interface {
+	error
+	Unwrap() []error
+}
+
+// errorMessages appends the messages of errs to dst, flattening nested
+// multi-errors and skipping nil errors.
+func errorMessages(dst []string, errs []error) []string {
+	for _, err := range errs {
+		switch e := err.(type) {
+		case nil:
+		case multiError:
+			dst = errorMessages(dst, e.Unwrap())
+		default:
+			dst = append(dst, e.Error())
+		}
+	}
+	return dst
+}
+
+// toZapField converts a single key-value pair to a zap.Field.
+func toZapField(k string, v interface{}) zap.Field {
+	switch val := v.(type) {
+	case string:
+		return zap.String(k, val)
+	case int:
+		return zap.Int(k, val)
+	case int64:
+		return zap.Int64(k, val)
+	case float64:
+		return zap.Float64(k, val)
+	case bool:
+		return zap.Bool(k, val)
+	default:
+		return zap.Any(k, v)
+	}
+}
@@ -0,0 +1,226 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// TestConvert_ErrorSlices tests that error slices and multi-errors become arrays of messages.
+func TestConvert_ErrorSlices(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	zapLogger := NewZap(Config{Level: InfoLevel, Output: buffer})
+
+	joined := joinedErrors{errors.New("a"), joinedErrors{errors.New("b"), errors.New("c")}}
+	zapLogger.Error("Error message", Fields{
+		"errors": []error{errors.New("x"), nil, errors.New("y")},
+		"joined": joined,
+		"single": errors.New("z"),
+	})
+
+	for _, expected := range []string{`"errors":["x","y"]`, `"joined":["a","b","c"]`, `"single":"z"`} {
+		if !bytes.Contains(buffer.Bytes(), []byte(expected)) {
+			t.Errorf("Expected %s to contain %s", buffer.String(), expected)
+		}
+	}
+}
+
+// joinedErrors mirrors the error returned by errors.Join.
+type joinedErrors []error
+
+func (j joinedErrors) Error() string {
+	messages := make([]string, 0, len(j))
+	for _, err := range j {
+		messages = append(messages, err.Error())
+	}
+	return strings.Join(messages, "\n")
+}
+
+func (j joinedErrors) Unwrap() []error {
+	return j
+}
+
+// TestConvert_Marshalers tests that custom marshalers and Stringers are respected.
+func TestConvert_Marshalers(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	zapLogger := NewZap(Config{Level: InfoLevel, Output: buffer})
+
+	var nilText *textValue
+	zapLogger.InfoFields("Info message",
+		Any("json", jsonValue{secret: "s"}),
+		Any("text", &textValue{id: 7}),
+		Any("stringer", stringerValue{name: "n"}),
+		Any("nil_text", nilText),
+	)
+
+	for _, expected := range []string{
+		`"json":{"redacted":true}`,
+		`"text":"id-7"`,
+		`"stringer":"stringer n"`,
+		`"nil_text":`,
+	} {
+		if !bytes.Contains(buffer.Bytes(), []byte(expected)) {
+			t.Errorf("Expected %s to contain %s", buffer.String(), expected)
+		}
+	}
+	if bytes.Contains(buffer.Bytes(), []byte(`"s"`)) {
+		t.Errorf("Expected unexported fields not to be written, got %s", buffer.String())
+	}
+}
+
+type jsonValue struct {
+	secret string
+}
+
+func (jsonValue) MarshalJSON() ([]byte, error) {
+	return []byte(`{"redacted":true}`), nil
+}
+
+type textValue struct {
+	id int
+}
+
+func (v *textValue) MarshalText() ([]byte, error) {
+	return []byte(fmt.Sprintf("id-%d", v.id)), nil
+}
+
+type stringerValue struct {
+	name string
+}
+
+func (v stringerValue) String() string {
+	return "stringer " + v.name
+}
+
+// TestConvert_Composites tests the string map, string slice, and int slice fast paths.
+func TestConvert_Composites(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	zapLogger := NewZap(Config{Level: InfoLevel, Output: buffer})
+
+	zapLogger.Info("Info message", Fields{
+		"headers": map[string]string{"User-Agent": "curl", "Accept": "*/*"},
+		"tags":    []string{"a", "b"},
+		"ports":   []int{80, 443},
+	})
+	for _, expected := range []string{
+		`"headers":{"Accept":"*/*","User-Agent":"curl"}`,
+		`"tags":["a","b"]`,
+		`"ports":[80,443]`,
+	} {
+		if !bytes.Contains(buffer.Bytes(), []byte(expected)) {
+			t.Errorf("Expected %s to contain %s", buffer.String(), expected)
+		}
+	}
+}
+
+// TestConvert_RawJSON tests that raw JSON values are embedded verbatim.
+func TestConvert_RawJSON(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	zapLogger := NewZap(Config{Level: InfoLevel, Output: buffer})
+
+	zapLogger.InfoFields("Info message",
+		Any("payload", json.RawMessage(`{"order":{"id":42,"items":[1,2]}}`)),
+		Any("raw", RawJSON(`[true, null]`)),
+		Any("invalid", RawJSON(`{"broken"`)),
+	)
+
+	for _, expected := range []string{
+		`"payload":{"order":{"id":42,"items":[1,2]}}`,
+		`"raw":[true,null]`,
+		`"invalid":"{\"broken\""`,
+	} {
+		if !bytes.Contains(buffer.Bytes(), []byte(expected)) {
+			t.Errorf("Expected %s to contain %s", buffer.String(), expected)
+		}
+	}
+}
+
+// TestConvert_BinaryEncoding tests the []byte encoding policies.
+func TestConvert_BinaryEncoding(t *testing.T) {
+	long := bytes.Repeat([]byte{0xab}, 40)
+	tests := []struct {
+		encoding BinaryEncoding
+		value    []byte
+		expected string
+	}{
+		{BinaryBase64, []byte("hi"), `"data":"aGk="`},
+		{BinaryHex, []byte("hi"), `"data":"6869"`},
+		{BinaryPreview, []byte("hi"), `"data":"6869"`},
+		{BinaryPreview, long, `"data":"` + strings.Repeat("ab", 32) + `... (40 bytes)"`},
+	}
+
+	for _, test := range tests {
+		buffer := new(bytes.Buffer)
+		zapLogger := NewZap(Config{Level: InfoLevel, Output: buffer, BinaryEncoding: test.encoding})
+		zapLogger.Info("Info message", Fields{"data": test.value})
+		if !bytes.Contains(buffer.Bytes(), []byte(test.expected)) {
+			t.Errorf("Encoding %d: expected %s to contain %s", test.encoding, buffer.String(), test.expected)
+		}
+	}
+}
+
+// TestConvert_MaxFieldBytes tests that oversized values are truncated and marked.
+func TestConvert_MaxFieldBytes(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	zapLogger := NewZap(Config{Level: InfoLevel, Output: buffer, MaxFieldBytes: 4, BinaryEncoding: BinaryHex})
+
+	zapLogger.InfoFields("Info message",
+		String("short", "abc"),
+		String("long", "abcdefgh"),
+		String("utf8", "日本語"),
+	)
+	for _, expected := range []string{`"short":"abc"`, `"long":"abcd"`, `"utf8":"日"`, `"truncated":true`} {
+		if !bytes.Contains(buffer.Bytes(), []byte(expected)) {
+			t.Errorf("Expected %s to contain %s", buffer.String(), expected)
+		}
+	}
+
+	buffer.Reset()
+	zapLogger.Info("Info message", Fields{"short": "abc"})
+	if bytes.Contains(buffer.Bytes(), []byte("truncated")) {
+		t.Errorf("Expected no marker when nothing was truncated, got %s", buffer.String())
+	}
+
+	buffer.Reset()
+	NewZap(Config{Output: buffer, MaxFieldBytes: 2}).Info("Info message", Fields{"data": []byte("hello")})
+	if !bytes.Contains(buffer.Bytes(), []byte(`"data":"aGU="`)) {
+		t.Errorf("Expected binary value to be truncated, got %s", buffer.String())
+	}
+}
+
+// TestConvert_MessageAndFieldLimits tests that long messages are truncated and
+// excess fields are summarized.
+func TestConvert_MessageAndFieldLimits(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	zapLogger := NewZap(Config{Level: InfoLevel, Output: buffer, MaxMessageBytes: 4, MaxFields: 2})
+
+	zapLogger.Info("Info message", nil)
+	for _, expected := range []string{`"msg":"Info"`, `"truncated":true`} {
+		if !bytes.Contains(buffer.Bytes(), []byte(expected)) {
+			t.Errorf("Expected %s to contain %s", buffer.String(), expected)
+		}
+	}
+
+	buffer.Reset()
+	zapLogger.InfoFields("Info",
+		String("a", "1"),
+		String("b", "2"),
+		String("d", "4"),
+		String("c", "3"),
+	)
+	for _, expected := range []string{`"a":"1"`, `"b":"2"`, `"dropped_fields":["c","d"]`} {
+		if !bytes.Contains(buffer.Bytes(), []byte(expected)) {
+			t.Errorf("Expected %s to contain %s", buffer.String(), expected)
+		}
+	}
+	if bytes.Contains(buffer.Bytes(), []byte("truncated")) {
+		t.Errorf("Expected no marker for a message within the limit, got %s", buffer.String())
+	}
+
+	if _, err := NewZapE(Config{Output: buffer, MaxFields: -1}); err == nil {
+		t.Errorf("Expected an error for a negative field limit")
+	}
+}
@@ -0,0 +1,90 @@
+package logger
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// CorrelationIDHeader is the HTTP header carrying correlation IDs between services.
+const CorrelationIDHeader = "X-Correlation-ID"
+
+// correlationMetadataKey is the gRPC metadata key carrying correlation IDs;
+// gRPC metadata keys are lowercase.
+var correlationMetadataKey = strings.ToLower(CorrelationIDHeader)
+
+// WithCorrelationID returns a copy of ctx carrying id.
+func WithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationIDKey, id)
+}
+
+// CorrelationIDFromContext returns the correlation ID carried by ctx, or "" if there is none.
+func CorrelationIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(correlationIDKey).(string)
+	return id
+}
+
+// SetCorrelationHeader sets the correlation ID carried by ctx on an outgoing
+// request's header. It does nothing if ctx carries no ID.
+func SetCorrelationHeader(ctx context.Context, header http.Header) {
+	if id := CorrelationIDFromContext(ctx); id != "" {
+		header.Set(CorrelationIDHeader, id)
+	}
+}
+
+// CorrelationIDFromHeader returns the correlation ID of an incoming request's header.
+func CorrelationIDFromHeader(header http.Header) string {
+	return header.Get(CorrelationIDHeader)
+}
+
+// SetCorrelationMetadata sets the correlation ID carried by ctx on outgoing
+// gRPC metadata, which can be passed as a metadata.MD. It does nothing if ctx
+// carries no ID.
+func SetCorrelationMetadata(ctx context.Context, md map[string][]string) {
+	if id := CorrelationIDFromContext(ctx); id != "" {
+		md[correlationMetadataKey] = []string{id}
+	}
+}
+
+// CorrelationIDFromMetadata returns the correlation ID of incoming gRPC metadata.
+func CorrelationIDFromMetadata(md map[string][]string) string {
+	if values := md[correlationMetadataKey]; len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// CorrelationTransport is an http.RoundTripper that sets the correlation ID of
+// each request's context on its header.
+type CorrelationTransport struct {
+	// Base performs the requests; defaults to http.DefaultTransport.
+	Base http.RoundTripper
+}
+
+// RoundTrip sets the correlation header on a copy of req and sends it with Base.
+func (t *CorrelationTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if id := CorrelationIDFromContext(req.Context()); id != "" {
+		req = req.Clone(req.Context())
+		req.Header.Set(CorrelationIDHeader, id)
+	}
+	return base.RoundTrip(req)
+}
+
+// CorrelationHandler returns an http.Handler that takes the correlation ID
+// from the incoming request, minting one if absent, and serves the request
+// with a context carrying the ID and a child of l with a correlation_id field.
+func CorrelationHandler(l Logger, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := CorrelationIDFromHeader(r.Header)
+		if id == "" {
+			id = NewRequestID()
+		}
+		ctx := WithCorrelationID(r.Context(), id)
+		ctx = NewContext(ctx, With(contextLogger(r.Context(), l), Fields{"correlation_id": id}))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
@@ -0,0 +1,65 @@
+package logger
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestCorrelationID tests that the correlation ID round-trips through headers and metadata.
+func TestCorrelationID(t *testing.T) {
+	ctx := WithCorrelationID(context.Background(), "abc")
+	if got := CorrelationIDFromContext(ctx); got != "abc" {
+		t.Errorf("Expected correlation ID abc, got %q", got)
+	}
+
+	header := http.Header{}
+	SetCorrelationHeader(ctx, header)
+	if got := CorrelationIDFromHeader(header); got != "abc" {
+		t.Errorf("Expected header correlation ID abc, got %q", got)
+	}
+
+	md := map[string][]string{}
+	SetCorrelationMetadata(ctx, md)
+	if got := CorrelationIDFromMetadata(md); got != "abc" {
+		t.Errorf("Expected metadata correlation ID abc, got %q", got)
+	}
+
+	empty := map[string][]string{}
+	SetCorrelationMetadata(context.Background(), empty)
+	if len(empty) != 0 {
+		t.Errorf("Expected no metadata without a correlation ID, got %v", empty)
+	}
+}
+
+// TestCorrelationHandler tests that incoming IDs are logged and propagated to outgoing requests.
+func TestCorrelationHandler(t *testing.T) {
+	var outgoing string
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		outgoing = r.Header.Get(CorrelationIDHeader)
+	}))
+	defer upstream.Close()
+	client := &http.Client{Transport: &CorrelationTransport{}}
+
+	observed, logs := Observe(Config{Level: InfoLevel})
+	handler := RequestIDHandler(observed, RequestIDConfig{}, CorrelationHandler(observed, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		FromContext(r.Context()).Info("handled", nil)
+		req, _ := http.NewRequestWithContext(r.Context(), http.MethodGet, upstream.URL, nil)
+		if resp, err := client.Do(req); err == nil {
+			resp.Body.Close()
+		}
+	})))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(CorrelationIDHeader, "abc")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	if outgoing != "abc" {
+		t.Errorf("Expected outgoing correlation ID abc, got %q", outgoing)
+	}
+	entries := logs.FilterField("correlation_id", "abc").FilterFieldKey("request_id")
+	if entries.Len() != 1 {
+		t.Errorf("Expected an entry with both IDs, got %v", logs.All())
+	}
+}
@@ -0,0 +1,177 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"time"
+)
+
+// defaultCrashEntries is the default CrashReportConfig.Entries.
+const defaultCrashEntries = 100
+
+// CrashReportConfig controls the crash report files written on Fatal.
+type CrashReportConfig struct {
+	// Dir is the directory crash reports are written to; empty disables them.
+	Dir string
+	// Entries is the number of most recent entries kept for the report;
+	// defaults to 100.
+	Entries int
+	// Stack controls the depth, trimming, and rendering of the stack trace.
+	Stack StackConfig
+}
+
+// CrashReport is the content of a crash report file: the entry that caused
+// it, the most recent entries written before it, the stack, the build, and
+// the logger configuration.
+type CrashReport struct {
+	Time     time.Time              `json:"time"`
+	Message  string                 `json:"message"`
+	Fields   map[string]interface{} `json:"fields,omitempty"`
+	PID      int                    `json:"pid"`
+	Hostname string                 `json:"hostname,omitempty"`
+	Stack    string                 `json:"stack"`
+	Build    CrashBuild             `json:"build"`
+	Config   CrashConfig            `json:"config"`
+	Entries  []CrashEntry           `json:"entries"`
+}
+
+// CrashBuild describes the binary that crashed.
+type CrashBuild struct {
+	GoVersion string            `json:"go_version"`
+	Path      string            `json:"path,omitempty"`
+	Version   string            `json:"version,omitempty"`
+	Settings  map[string]string `json:"settings,omitempty"`
+}
+
+// CrashConfig is a snapshot of the logger configuration.
+type CrashConfig struct {
+	Level         string            `json:"level"`
+	Format        string            `json:"format"`
+	OutputPath    string            `json:"output_path,omitempty"`
+	Destinations  int               `json:"destinations,omitempty"`
+	PackageLevels map[string]string `json:"package_levels,omitempty"`
+}
+
+// CrashEntry is an entry recorded in a crash report.
+type CrashEntry struct {
+	Level   string                 `json:"level"`
+	Time    time.Time              `json:"time"`
+	Message string                 `json:"message"`
+	Fields  map[string]interface{} `json:"fields,omitempty"`
+}
+
+// newCrashHistory returns the ring buffer of recent entries for crash reports,
+// or nil if they are disabled.
+func newCrashHistory(config CrashReportConfig) *ringBuffer {
+	if config.Dir == "" {
+		return nil
+	}
+	if config.Entries <= 0 {
+		config.Entries = defaultCrashEntries
+	}
+	return newRingBuffer(config.Entries)
+}
+
+// writeCrashReport writes a crash report for an entry to Config.CrashReport.Dir,
+// taking the stack skip frames above its caller, and returns the file path.
+func (z *Zap) writeCrashReport(skip int, msg string, fields Fields) (string, error) {
+	config := z.Config.CrashReport
+	now := z.now()
+	report := CrashReport{
+		Time:    now,
+		Message: msg,
+		Fields:  crashFields(fields),
+		PID:     os.Getpid(),
+		Stack:   captureStack(skip+1, config.Stack),
+		Build:   crashBuild(),
+		Config:  crashConfig(z.Config, z.Level()),
+		Entries: []CrashEntry{},
+	}
+	report.Hostname, _ = os.Hostname()
+	if z.state.history != nil {
+		for _, entry := range z.state.history.snapshot() {
+			report.Entries = append(report.Entries, CrashEntry{
+				Level:   entry.Level.zapLevel().String(),
+				Time:    entry.Time,
+				Message: entry.Message,
+				Fields:  crashFields(entry.Fields),
+			})
+		}
+	}
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("crash report: %w", err)
+	}
+	if err := os.MkdirAll(config.Dir, 0o755); err != nil {
+		return "", fmt.Errorf("crash report: %w", err)
+	}
+	path := filepath.Join(config.Dir, fmt.Sprintf("crash-%s-%d.json", now.UTC().Format("20060102T150405.000000000Z"), report.PID))
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return "", fmt.Errorf("crash report: %w", err)
+	}
+	return path, nil
+}
+
+// crashFields converts field values that encoding/json cannot represent
+// faithfully, such as errors, to strings.
+func crashFields(fields Fields) map[string]interface{} {
+	if len(fields) == 0 {
+		return nil
+	}
+	converted := make(map[string]interface{}, len(fields))
+	for k, v := range fields {
+		switch val := v.(type) {
+		case error:
+			converted[k] = val.Error()
+		case fmt.Stringer:
+			converted[k] = val.String()
+		default:
+			if _, err := json.Marshal(v); err != nil {
+				converted[k] = fmt.Sprint(v)
+			} else {
+				converted[k] = v
+			}
+		}
+	}
+	return converted
+}
+
+// crashBuild returns the build information of the running binary.
+func crashBuild() CrashBuild {
+	build := CrashBuild{GoVersion: runtime.Version()}
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return build
+	}
+	build.Path = info.Main.Path
+	build.Version = info.Main.Version
+	for _, setting := range info.Settings {
+		if build.Settings == nil {
+			build.Settings = make(map[string]string)
+		}
+		build.Settings[setting.Key] = setting.Value
+	}
+	return build
+}
+
+// crashConfig returns the snapshot of config, with the current level.
+func crashConfig(config Config, level Level) CrashConfig {
+	snapshot := CrashConfig{
+		Level:        level.zapLevel().String(),
+		Format:       config.Format.String(),
+		OutputPath:   config.OutputPath,
+		Destinations: len(config.Destinations),
+	}
+	for prefix, l := range config.PackageLevels {
+		if snapshot.PackageLevels == nil {
+			snapshot.PackageLevels = make(map[string]string)
+		}
+		snapshot.PackageLevels[prefix] = l.zapLevel().String()
+	}
+	return snapshot
+}
@@ -0,0 +1,84 @@
+package logger
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// readCrashReport returns the only crash report in dir.
+func readCrashReport(t *testing.T, dir string) CrashReport {
+	t.Helper()
+	paths, _ := filepath.Glob(filepath.Join(dir, "crash-*.json"))
+	if len(paths) != 1 {
+		t.Fatalf("Expected 1 crash report, got %v", paths)
+	}
+	data, err := os.ReadFile(paths[0])
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var report CrashReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return report
+}
+
+// TestZap_CrashReport tests that a fatal entry writes a crash report before exiting.
+func TestZap_CrashReport(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "crashes")
+	exited := false
+	zapLogger := NewZap(Config{
+		Level:       InfoLevel,
+		Output:      io.Discard,
+		OutputPath:  "/var/log/app.log",
+		CrashReport: CrashReportConfig{Dir: dir, Entries: 2},
+		ExitFunc: func(int) {
+			if _, err := os.Stat(dir); err != nil {
+				t.Errorf("Expected the crash report before exiting")
+			}
+			exited = true
+		},
+	})
+
+	zapLogger.Info("starting", nil)
+	zapLogger.Warn("disk almost full", Fields{"free": 10})
+	zapLogger.Fatal("cannot write state", Fields{"error": errors.New("no space left on device")})
+	if !exited {
+		t.Fatalf("Expected the fatal entry to exit")
+	}
+
+	report := readCrashReport(t, dir)
+	if report.Message != "cannot write state" || report.Fields["error"] != "no space left on device" {
+		t.Errorf("Expected the fatal entry in the report, got %+v", report)
+	}
+	if len(report.Entries) != 2 || report.Entries[0].Message != "disk almost full" || report.Entries[1].Level != "fatal" {
+		t.Errorf("Expected the last 2 entries, got %+v", report.Entries)
+	}
+	if !strings.Contains(report.Stack, "TestZap_CrashReport") || strings.Contains(report.Stack, "afterFatal") {
+		t.Errorf("Expected the stack to start at the caller, got %s", report.Stack)
+	}
+	if report.Config.Level != "info" || report.Config.OutputPath != "/var/log/app.log" || report.Build.GoVersion == "" || report.PID != os.Getpid() {
+		t.Errorf("Expected the configuration and build, got %+v %+v", report.Config, report.Build)
+	}
+}
+
+// TestRecoverAndLogWith_CrashReport tests that recovered panics can write crash reports.
+func TestRecoverAndLogWith_CrashReport(t *testing.T) {
+	dir := t.TempDir()
+	zapLogger := NewZap(Config{Level: InfoLevel, Output: io.Discard, CrashReport: CrashReportConfig{Dir: dir}})
+
+	func() {
+		defer RecoverAndLogWith(zapLogger, RecoverConfig{CrashReport: true})
+		panic("boom")
+	}()
+
+	report := readCrashReport(t, dir)
+	if report.Message != "panic recovered" || report.Fields["panic"] != "boom" {
+		t.Errorf("Expected the panic in the report, got %+v", report)
+	}
+}
@@ -0,0 +1,70 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/csv"
+	"sync"
+
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+// CSVConfig declares the columns of FormatCSV output.
+type CSVConfig struct {
+	// Columns are the field keys written after the time, level, and message
+	// columns, in order. Entries without a field leave its column blank;
+	// fields that are not columns are not written.
+	Columns []string
+	// Header writes a header row before the first entry of each output.
+	Header bool
+}
+
+// newCSVCore returns a core writing one CSV row per entry: the time, level,
+// message, and the configured columns.
+func newCSVCore(config CSVConfig, encoderConfig zapcore.EncoderConfig, ws zapcore.WriteSyncer, enabler zapcore.LevelEnabler) zapcore.Core {
+	columns := append([]string(nil), config.Columns...)
+	var header sync.Once
+	render := func(buf *buffer.Buffer, entry zapcore.Entry, fields map[string]interface{}) error {
+		if config.Header {
+			// The header is written to ws within the Once, which holds back
+			// every other row until it is done.
+			var err error
+			header.Do(func() {
+				var b bytes.Buffer
+				hw := csv.NewWriter(&b)
+				if err = hw.Write(append([]string{encoderConfig.TimeKey, encoderConfig.LevelKey, encoderConfig.MessageKey}, columns...)); err != nil {
+					return
+				}
+				hw.Flush()
+				if err = hw.Error(); err == nil {
+					_, err = ws.Write(b.Bytes())
+				}
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		w := csv.NewWriter(buf)
+
+		record := make([]string, 0, len(columns)+3)
+		record = append(record,
+			formatValue(encodePrimitive(func(enc zapcore.PrimitiveArrayEncoder) { encoderConfig.EncodeTime(entry.Time, enc) })),
+			formatValue(encodePrimitive(func(enc zapcore.PrimitiveArrayEncoder) { encoderConfig.EncodeLevel(entry.Level, enc) })),
+			entry.Message,
+		)
+		for _, column := range columns {
+			value := ""
+			if v, ok := fields[column]; ok {
+				value = formatValue(v)
+			}
+			record = append(record, value)
+		}
+		if err := w.Write(record); err != nil {
+			return err
+		}
+		w.Flush()
+		return w.Error()
+	}
+	return newMapCore(render, ws, enabler)
+}
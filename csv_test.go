@@ -0,0 +1,78 @@
+package logger
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestFormat_CSV tests that entries are written as rows of the declared columns.
+func TestFormat_CSV(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	zapLogger := NewZap(Config{
+		Level:         InfoLevel,
+		Output:        buffer,
+		Format:        FormatCSV,
+		DisableCaller: true,
+		Clock:         fixedClock(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)),
+		CSV:           CSVConfig{Columns: []string{"user", "amount"}, Header: true},
+	})
+
+	zapLogger.Info("payment", Fields{"user": "alice", "amount": 12.5, "ignored": true})
+	zapLogger.With(Fields{"user": "bob"}).Warn("refund, partial", nil)
+
+	expected := "ts,level,msg,user,amount\n" +
+		"2024-01-02T03:04:05Z,info,payment,alice,12.5\n" +
+		"2024-01-02T03:04:05Z,warn,\"refund, partial\",bob,\n"
+	if buffer.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, buffer.String())
+	}
+
+	if _, err := NewZapE(Config{Output: buffer, Format: FormatCSV}); err == nil {
+		t.Errorf("Expected an error without columns")
+	}
+}
+
+// lockedBuffer is a buffer safe for concurrent writes.
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+// TestFormat_CSVHeaderConcurrent tests that the header comes first when the
+// first entries are logged concurrently.
+func TestFormat_CSVHeaderConcurrent(t *testing.T) {
+	for run := 0; run < 20; run++ {
+		output := &lockedBuffer{}
+		zapLogger := NewZap(Config{
+			Level:         InfoLevel,
+			Output:        output,
+			Format:        FormatCSV,
+			DisableCaller: true,
+			CSV:           CSVConfig{Columns: []string{"n"}, Header: true},
+		})
+
+		var wg sync.WaitGroup
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				zapLogger.Info("entry", Fields{"n": i})
+			}(i)
+		}
+		wg.Wait()
+
+		lines := strings.Split(strings.TrimSpace(output.buf.String()), "\n")
+		if lines[0] != "ts,level,msg,n" || len(lines) != 9 {
+			t.Fatalf("Expected the header before 8 rows, got %q", output.buf.String())
+		}
+	}
+}
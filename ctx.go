@@ -0,0 +1,62 @@
+package logger
+
+import (
+	"context"
+	"time"
+)
+
+// defaultNearDeadline is the default Config.NearDeadline.
+const defaultNearDeadline = time.Second
+
+// InfoCtx logs an info message with structured fields, adding the fields
+// accumulated in ctx and the state of ctx.
+func (z *Zap) InfoCtx(ctx context.Context, msg string, fields Fields) {
+	z.log(InfoLevel, msg, withCtxFields(ctx, fields), z.contextFields(ctx))
+}
+
+// WarnCtx logs a warning message with structured fields, adding the fields
+// accumulated in ctx and the state of ctx.
+func (z *Zap) WarnCtx(ctx context.Context, msg string, fields Fields) {
+	z.log(WarnLevel, msg, withCtxFields(ctx, fields), z.contextFields(ctx))
+}
+
+// ErrorCtx logs an error message with structured fields, adding the fields
+// accumulated in ctx and the state of ctx.
+func (z *Zap) ErrorCtx(ctx context.Context, msg string, fields Fields) {
+	z.log(ErrorLevel, msg, withCtxFields(ctx, fields), z.contextFields(ctx))
+}
+
+// FatalCtx logs a fatal message with structured fields, adding the fields
+// accumulated in ctx and the state of ctx, then exits, panics, or returns
+// according to Config.OnFatal.
+func (z *Zap) FatalCtx(ctx context.Context, msg string, fields Fields) {
+	fields = withCtxFields(ctx, fields)
+	typed := z.contextFields(ctx)
+	if z.log(FatalLevel, msg, fields, typed) {
+		z.afterFatal(msg, fieldsToMap(fields, typed))
+	}
+}
+
+// contextFields returns the fields describing ctx: "ctx_err" once it is
+// canceled or expired, and "ctx_remaining" when its deadline is within
+// Config.NearDeadline. A healthy context adds nothing.
+func (z *Zap) contextFields(ctx context.Context) []Field {
+	if ctx == nil {
+		return nil
+	}
+	if err := ctx.Err(); err != nil {
+		return []Field{String("ctx_err", err.Error())}
+	}
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return nil
+	}
+	near := z.Config.NearDeadline
+	if near <= 0 {
+		near = defaultNearDeadline
+	}
+	if remaining := time.Until(deadline); remaining < near {
+		return []Field{String("ctx_remaining", remaining.String())}
+	}
+	return nil
+}
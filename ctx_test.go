@@ -0,0 +1,93 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+)
+
+// TestZap_Ctx tests that the *Ctx methods record canceled and nearly expired contexts.
+func TestZap_Ctx(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	zapLogger := NewZap(Config{Level: InfoLevel, Output: buffer, NearDeadline: time.Minute})
+
+	zapLogger.InfoCtx(context.Background(), "Info message", nil)
+	if bytes.Contains(buffer.Bytes(), []byte(`"ctx_`)) {
+		t.Errorf("Expected no context fields for a healthy context, got %s", buffer.String())
+	}
+
+	buffer.Reset()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	zapLogger.ErrorCtx(ctx, "Error message", Fields{"key": "value"})
+	for _, expected := range []string{`"ctx_err":"context canceled"`, `"key":"value"`, `ctx_test.go`} {
+		if !bytes.Contains(buffer.Bytes(), []byte(expected)) {
+			t.Errorf("Expected %s to contain %s", buffer.String(), expected)
+		}
+	}
+
+	buffer.Reset()
+	ctx, cancel = context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	zapLogger.WarnCtx(ctx, "Warn message", nil)
+	if !bytes.Contains(buffer.Bytes(), []byte(`"ctx_remaining":"`)) {
+		t.Errorf("Expected the remaining time near the deadline, got %s", buffer.String())
+	}
+
+	buffer.Reset()
+	ctx, cancel = context.WithTimeout(context.Background(), time.Hour)
+	defer cancel()
+	zapLogger.WarnCtx(ctx, "Warn message", nil)
+	if bytes.Contains(buffer.Bytes(), []byte("ctx_remaining")) {
+		t.Errorf("Expected no remaining time far from the deadline, got %s", buffer.String())
+	}
+}
+
+// TestZap_FatalCtx tests that FatalCtx adds the context fields and exits like Fatal.
+func TestZap_FatalCtx(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	exitCode := -1
+	zapLogger := NewZap(Config{Level: InfoLevel, Output: buffer, ExitFunc: func(code int) { exitCode = code }})
+
+	ctx, cancel := context.WithCancel(AppendCtxFields(context.Background(), Fields{"request_id": "r1"}))
+	cancel()
+	zapLogger.FatalCtx(ctx, "Fatal message", Fields{"key": "value"})
+
+	if exitCode != 1 {
+		t.Errorf("Expected exit code 1, got %d", exitCode)
+	}
+	for _, expected := range []string{`"level":"fatal"`, `"request_id":"r1"`, `"key":"value"`, `"ctx_err":"context canceled"`, `ctx_test.go`} {
+		if !bytes.Contains(buffer.Bytes(), []byte(expected)) {
+			t.Errorf("Expected %s to contain %s", buffer.String(), expected)
+		}
+	}
+}
+
+// TestAppendCtxFields tests that fields accumulated along the call chain are written.
+func TestAppendCtxFields(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	zapLogger := NewZap(Config{Level: InfoLevel, Output: buffer})
+
+	ctx := AppendCtxFields(context.Background(), Fields{"request_id": "r1", "user_id": "anonymous"})
+	ctx = AppendCtxFields(ctx, Fields{"user_id": "u7"})
+	zapLogger.InfoCtx(ctx, "order placed", Fields{"order_id": 42})
+
+	for _, expected := range []string{`"request_id":"r1"`, `"user_id":"u7"`, `"order_id":42`} {
+		if !bytes.Contains(buffer.Bytes(), []byte(expected)) {
+			t.Errorf("Expected %s to contain %s", buffer.String(), expected)
+		}
+	}
+	if bytes.Contains(buffer.Bytes(), []byte("anonymous")) {
+		t.Errorf("Expected later fields to win, got %s", buffer.String())
+	}
+
+	buffer.Reset()
+	zapLogger.WarnCtx(ctx, "retrying", Fields{"user_id": "override"})
+	if !bytes.Contains(buffer.Bytes(), []byte(`"user_id":"override"`)) {
+		t.Errorf("Expected the call's fields to win, got %s", buffer.String())
+	}
+	if len(CtxFields(context.Background())) != 0 {
+		t.Errorf("Expected no fields in an empty context")
+	}
+}
@@ -0,0 +1,36 @@
+//go:build !logger_nodebug
+
+package logger
+
+import "context"
+
+// debugCompiled reports whether the debug logging methods are compiled in.
+const debugCompiled = true
+
+// Debug logs a debug message with structured fields using the default logger.
+func Debug(msg string, fields Fields) {
+	direct().Debug(msg, fields)
+}
+
+// Debug logs a debug message with structured fields.
+func (z *Zap) Debug(msg string, fields Fields) {
+	z.log(DebugLevel, msg, fields, nil)
+}
+
+// DebugFields logs a debug message with typed fields.
+func (z *Zap) DebugFields(msg string, fields ...Field) {
+	z.log(DebugLevel, msg, nil, fields)
+}
+
+// DebugCtx logs a debug message with structured fields, adding the fields
+// accumulated in ctx and the state of ctx.
+func (z *Zap) DebugCtx(ctx context.Context, msg string, fields Fields) {
+	z.log(DebugLevel, msg, withCtxFields(ctx, fields), z.contextFields(ctx))
+}
+
+// Debug logs a debug message with structured fields if the call site is allowed.
+func (l *limitedZap) Debug(msg string, fields Fields) {
+	if l.allow(DebugLevel) {
+		l.z.log(DebugLevel, msg, fields, nil)
+	}
+}
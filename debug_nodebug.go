@@ -0,0 +1,30 @@
+//go:build logger_nodebug
+
+package logger
+
+import "context"
+
+// Building with the logger_nodebug tag compiles the debug logging methods of
+// this package into empty functions that the compiler inlines away, so debug
+// calls cost nothing, not even a level check. Arguments are still evaluated
+// unless the compiler can prove them free of side effects, so wrap expensive
+// ones in a DebugEnabled check, which then always reports false. The flight
+// recorder receives no debug entries.
+
+// debugCompiled reports whether the debug logging methods are compiled in.
+const debugCompiled = false
+
+// Debug does nothing; debug logging is compiled out.
+func Debug(msg string, fields Fields) {}
+
+// Debug does nothing; debug logging is compiled out.
+func (z *Zap) Debug(msg string, fields Fields) {}
+
+// DebugFields does nothing; debug logging is compiled out.
+func (z *Zap) DebugFields(msg string, fields ...Field) {}
+
+// DebugCtx does nothing; debug logging is compiled out.
+func (z *Zap) DebugCtx(ctx context.Context, msg string, fields Fields) {}
+
+// Debug does nothing; debug logging is compiled out.
+func (l *limitedZap) Debug(msg string, fields Fields) {}
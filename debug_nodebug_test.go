@@ -0,0 +1,24 @@
+//go:build logger_nodebug
+
+package logger
+
+import (
+	"bytes"
+	"testing"
+)
+
+// TestZap_NoDebug tests that debug entries are compiled out.
+func TestZap_NoDebug(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	zapLogger := NewZap(Config{Level: DebugLevel, Output: buffer})
+
+	zapLogger.Debug("Debug message", nil)
+	zapLogger.DebugFields("Debug message", String("key", "value"))
+	zapLogger.Once().Debug("Debug message", nil)
+	if buffer.Len() != 0 {
+		t.Errorf("Expected no output, got %s", buffer.String())
+	}
+	if zapLogger.DebugEnabled() || Enabled(zapLogger, DebugLevel) {
+		t.Errorf("Expected debug to be disabled")
+	}
+}
@@ -0,0 +1,91 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// decodeEntry decodes a JSON entry written by Zap, keeping numbers as json.Number.
+func decodeEntry(p []byte) (map[string]interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(p))
+	decoder.UseNumber()
+
+	var entry map[string]interface{}
+	if err := decoder.Decode(&entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// entryCore holds the time, level, and message of a decoded entry.
+type entryCore struct {
+	time    time.Time
+	hasTime bool
+	level   string
+	message string
+}
+
+// takeEntryCore removes the time, level, and message keys named by names from
+// entry and returns their values. A numeric level, as written with
+// SeverityOnly, is mapped back to the level name.
+func takeEntryCore(entry map[string]interface{}, names KeyNames) entryCore {
+	names = names.withDefaults()
+	var core entryCore
+	if core.time, core.hasTime = entryTime(entry[names.Time]); core.hasTime {
+		delete(entry, names.Time)
+	}
+	switch level := entry[names.Level].(type) {
+	case string:
+		core.level = level
+		delete(entry, names.Level)
+	case json.Number:
+		if severity, err := level.Int64(); err == nil {
+			core.level = severityLevel(int(severity)).zapLevel().String()
+			delete(entry, names.Level)
+		}
+	}
+	core.message, _ = entry[names.Message].(string)
+	delete(entry, names.Message)
+	return core
+}
+
+// decodeJSONValue decodes any JSON value, keeping numbers as json.Number.
+func decodeJSONValue(p []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(p))
+	decoder.UseNumber()
+
+	var v interface{}
+	if err := decoder.Decode(&v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// entryTime parses a decoded ts value, written either as an RFC 3339 string or
+// as Unix milliseconds.
+func entryTime(v interface{}) (time.Time, bool) {
+	switch ts := v.(type) {
+	case string:
+		if t, err := time.Parse(time.RFC3339Nano, ts); err == nil {
+			return t, true
+		}
+	case json.Number:
+		if millis, err := ts.Int64(); err == nil {
+			return time.Unix(0, millis*int64(time.Millisecond)), true
+		}
+	}
+	return time.Time{}, false
+}
+
+// mapSeverity returns the severity configured in severities for the level
+// named by label, or else the default severity for label.
+func mapSeverity(label string, severities map[Level]int, defaults map[string]int) int {
+	if level, err := ParseLevel(label); err == nil {
+		if severity, ok := severities[level]; ok {
+			return severity
+		}
+	}
+	return defaults[strings.ToLower(label)]
+}
@@ -0,0 +1,60 @@
+package logger
+
+import (
+	"sync/atomic"
+)
+
+// defaultHolder pairs the logger returned by Default with the one used by the
+// package-level functions, which may be adjusted to report the right caller.
+type defaultHolder struct {
+	logger Logger
+	direct Logger
+}
+
+// defaultLogger holds the current *defaultHolder.
+var defaultLogger atomic.Value
+
+func init() {
+	SetDefault(NewZap(Config{Level: InfoLevel}))
+}
+
+// SetDefault makes l the logger used by the package-level logging functions.
+// It is safe to call concurrently with logging.
+func SetDefault(l Logger) {
+	holder := &defaultHolder{logger: l, direct: l}
+	if z, ok := l.(*Zap); ok {
+		holder.direct = z.withCallerSkip(1)
+	}
+	defaultLogger.Store(holder)
+}
+
+// Default returns the logger used by the package-level logging functions. Until
+// SetDefault is called it is a *Zap writing info and above to stderr.
+func Default() Logger {
+	return defaultLogger.Load().(*defaultHolder).logger
+}
+
+// direct returns the default logger adjusted for use by the package-level functions.
+func direct() Logger {
+	return defaultLogger.Load().(*defaultHolder).direct
+}
+
+// Info logs an info message with structured fields using the default logger.
+func Info(msg string, fields Fields) {
+	direct().Info(msg, fields)
+}
+
+// Warn logs a warning message with structured fields using the default logger.
+func Warn(msg string, fields Fields) {
+	direct().Warn(msg, fields)
+}
+
+// Error logs an error message with structured fields using the default logger.
+func Error(msg string, fields Fields) {
+	direct().Error(msg, fields)
+}
+
+// Fatal logs a fatal message with structured fields using the default logger.
+func Fatal(msg string, fields Fields) {
+	direct().Fatal(msg, fields)
+}
@@ -0,0 +1,50 @@
+package logger
+
+import (
+	"bytes"
+	"fmt"
+	"runtime"
+	"testing"
+)
+
+// TestSetDefault tests that the package-level functions use the default logger.
+func TestSetDefault(t *testing.T) {
+	requireDebug(t)
+	previous := Default()
+	defer SetDefault(previous)
+
+	log, logs := Observe(Config{})
+	SetDefault(log)
+	if Default() != log {
+		t.Errorf("Expected Default to return the logger passed to SetDefault")
+	}
+
+	Debug("Debug message", nil)
+	Info("Info message", nil)
+	Warn("Warn message", nil)
+	Error("Error message", Fields{"key": "value"})
+
+	if logs.Len() != 4 {
+		t.Errorf("Expected 4 entries, got %d", logs.Len())
+	}
+	if logs.FilterLevelExact(ErrorLevel).FilterField("key", "value").Len() != 1 {
+		t.Errorf("Expected the error entry to be recorded, got %+v", logs.All())
+	}
+}
+
+// TestSetDefault_Caller tests that the package-level functions report their caller.
+func TestSetDefault_Caller(t *testing.T) {
+	previous := Default()
+	defer SetDefault(previous)
+
+	buffer := new(bytes.Buffer)
+	SetDefault(NewZap(Config{Output: buffer}))
+
+	_, _, line, _ := runtime.Caller(0)
+	Info("Info message", nil)
+
+	expected := fmt.Sprintf("default_test.go:%d", line+1)
+	if !bytes.Contains(buffer.Bytes(), []byte(expected)) {
+		t.Errorf("Expected %s to contain %s", buffer.String(), expected)
+	}
+}
@@ -0,0 +1,267 @@
+package logger
+
+import (
+	"bufio"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// maxEncryptChunk is the largest plaintext sealed into a single chunk.
+const maxEncryptChunk = 64 * 1024
+
+// encryptStreamIDSize is the length of the random ID that starts each stream.
+const encryptStreamIDSize = 16
+
+// Record types of an encrypted stream.
+const (
+	encryptHeader byte = iota
+	encryptChunk
+	encryptFinal
+)
+
+// ErrCorruptChunk is returned by the decrypt reader when a chunk cannot be authenticated.
+var ErrCorruptChunk = errors.New("encrypted log chunk is corrupt")
+
+// ErrTruncatedStream is returned by the decrypt reader when a stream ends
+// without the final chunk written by EncryptWriter.Close.
+var ErrTruncatedStream = errors.New("encrypted log stream is truncated")
+
+// EncryptWriter is an output wrapper that encrypts entries with AES-GCM before
+// writing them. Each writer starts a stream with a random ID and seals every
+// write into one or more length-prefixed chunks of the form
+// [4-byte length][1-byte type][12-byte nonce][ciphertext]. The stream ID, the
+// chunk's sequence number, and its type are authenticated with each chunk, so
+// reordered, dropped, or spliced chunks fail to decrypt. Close writes a final
+// chunk that marks the end of the stream. Streams can be appended to one file
+// across restarts and decrypted with NewDecryptReader.
+type EncryptWriter struct {
+	mu       sync.Mutex
+	output   io.Writer
+	aead     cipher.AEAD
+	streamID []byte
+	sequence uint64
+	closed   bool
+}
+
+// NewEncryptWriter returns a new *EncryptWriter. The key must be 16, 24, or 32
+// bytes long to select AES-128, AES-192, or AES-256.
+func NewEncryptWriter(output io.Writer, key []byte) (*EncryptWriter, error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	return &EncryptWriter{output: output, aead: aead}, nil
+}
+
+// MustEncryptWriter is like NewEncryptWriter but panics if the key is invalid.
+func MustEncryptWriter(output io.Writer, key []byte) *EncryptWriter {
+	e, err := NewEncryptWriter(output, key)
+	if err != nil {
+		panic(err)
+	}
+	return e
+}
+
+// Write encrypts p and writes the resulting chunks to the output, starting the
+// stream on the first write.
+func (e *EncryptWriter) Write(p []byte) (int, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.closed {
+		return 0, errors.New("encrypt: writer is closed")
+	}
+
+	out, err := e.start()
+	if err != nil {
+		return 0, err
+	}
+	for rest := p; len(rest) > 0; {
+		n := len(rest)
+		if n > maxEncryptChunk {
+			n = maxEncryptChunk
+		}
+		if out, err = e.seal(out, encryptChunk, rest[:n]); err != nil {
+			return 0, err
+		}
+		rest = rest[n:]
+	}
+
+	if _, err := e.output.Write(out); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Close writes the final chunk that ends the stream; later writes fail. The
+// output is not closed.
+func (e *EncryptWriter) Close() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.closed {
+		return nil
+	}
+	e.closed = true
+
+	out, err := e.start()
+	if err != nil {
+		return err
+	}
+	if out, err = e.seal(out, encryptFinal, nil); err != nil {
+		return err
+	}
+	_, err = e.output.Write(out)
+	return err
+}
+
+// start returns the header record if the stream has not started yet. The
+// caller must hold e.mu.
+func (e *EncryptWriter) start() ([]byte, error) {
+	if e.streamID != nil {
+		return nil, nil
+	}
+	streamID := make([]byte, encryptStreamIDSize)
+	if _, err := rand.Read(streamID); err != nil {
+		return nil, fmt.Errorf("encrypt: generate stream ID: %w", err)
+	}
+	e.streamID = streamID
+	return appendEncryptRecord(nil, encryptHeader, streamID), nil
+}
+
+// seal appends plain to out as a chunk of the given type and advances the
+// sequence number. The caller must hold e.mu.
+func (e *EncryptWriter) seal(out []byte, kind byte, plain []byte) ([]byte, error) {
+	nonce := make([]byte, e.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("encrypt: generate nonce: %w", err)
+	}
+	sealed := e.aead.Seal(nonce, nonce, plain, encryptAAD(e.streamID, e.sequence, kind))
+	e.sequence++
+	return appendEncryptRecord(out, kind, sealed), nil
+}
+
+// appendEncryptRecord appends a record of the given type and body to out.
+func appendEncryptRecord(out []byte, kind byte, body []byte) []byte {
+	var length [4]byte
+	binary.BigEndian.PutUint32(length[:], uint32(1+len(body)))
+	out = append(out, length[:]...)
+	out = append(out, kind)
+	return append(out, body...)
+}
+
+// encryptAAD returns the data authenticated with a chunk: the stream ID, the
+// chunk's sequence number, and its type.
+func encryptAAD(streamID []byte, sequence uint64, kind byte) []byte {
+	aad := make([]byte, 0, len(streamID)+9)
+	aad = append(aad, streamID...)
+	var seq [8]byte
+	binary.BigEndian.PutUint64(seq[:], sequence)
+	aad = append(aad, seq[:]...)
+	return append(aad, kind)
+}
+
+// Sync flushes the underlying output if it supports it.
+func (e *EncryptWriter) Sync() error {
+	if s, ok := e.output.(syncer); ok {
+		return s.Sync()
+	}
+	return nil
+}
+
+// decryptReader reads chunks written by an EncryptWriter and returns the plaintext.
+type decryptReader struct {
+	input    *bufio.Reader
+	aead     cipher.AEAD
+	plain    []byte
+	streamID []byte
+	sequence uint64
+}
+
+// NewDecryptReader returns a reader yielding the plaintext of the streams
+// written by EncryptWriters using the same key. Read fails with
+// ErrCorruptChunk if a chunk was modified, reordered, or dropped, and with
+// ErrTruncatedStream if a stream is not ended by its final chunk, which
+// includes a stream whose writer was not closed.
+func NewDecryptReader(input io.Reader, key []byte) (io.Reader, error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	return &decryptReader{input: bufio.NewReader(input), aead: aead}, nil
+}
+
+// Read implements io.Reader.
+func (d *decryptReader) Read(p []byte) (int, error) {
+	for len(d.plain) == 0 {
+		if err := d.next(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, d.plain)
+	d.plain = d.plain[n:]
+	return n, nil
+}
+
+// next reads the following record, decrypting it if it is a chunk.
+func (d *decryptReader) next() error {
+	var length [4]byte
+	if _, err := io.ReadFull(d.input, length[:]); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			return ErrCorruptChunk
+		}
+		if err == io.EOF && d.streamID != nil {
+			return ErrTruncatedStream
+		}
+		return err
+	}
+
+	size := binary.BigEndian.Uint32(length[:])
+	if size < 1 || size > 1+maxEncryptChunk+1024 {
+		return ErrCorruptChunk
+	}
+	record := make([]byte, size)
+	if _, err := io.ReadFull(d.input, record); err != nil {
+		return ErrCorruptChunk
+	}
+	kind, body := record[0], record[1:]
+
+	if kind == encryptHeader {
+		if d.streamID != nil {
+			return ErrTruncatedStream
+		}
+		if len(body) != encryptStreamIDSize {
+			return ErrCorruptChunk
+		}
+		d.streamID, d.sequence = body, 0
+		return nil
+	}
+	if (kind != encryptChunk && kind != encryptFinal) || d.streamID == nil || len(body) < d.aead.NonceSize()+d.aead.Overhead() {
+		return ErrCorruptChunk
+	}
+
+	nonce, ciphertext := body[:d.aead.NonceSize()], body[d.aead.NonceSize():]
+	plain, err := d.aead.Open(ciphertext[:0], nonce, ciphertext, encryptAAD(d.streamID, d.sequence, kind))
+	if err != nil {
+		return ErrCorruptChunk
+	}
+	d.sequence++
+	if kind == encryptFinal {
+		d.streamID = nil
+	}
+	d.plain = plain
+	return nil
+}
+
+// newGCM returns an AES-GCM AEAD for key.
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("encrypt: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
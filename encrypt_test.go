@@ -0,0 +1,179 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"testing"
+)
+
+// TestEncryptWriter tests that entries round-trip through the encrypt writer and decrypt reader.
+func TestEncryptWriter(t *testing.T) {
+	key := bytes.Repeat([]byte{7}, 32)
+	buffer := new(bytes.Buffer)
+	writer, err := NewEncryptWriter(buffer, key)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	zapLogger := NewZap(Config{
+		Level:    InfoLevel,
+		Output:   writer,
+		ExitFunc: func(int) {},
+	})
+	zapLogger.Info("Info message", Fields{"ssn": "123-45-6789"})
+	zapLogger.Info("Second message", nil)
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if bytes.Contains(buffer.Bytes(), []byte("123-45-6789")) {
+		t.Errorf("Expected output to be encrypted, got %s", buffer.String())
+	}
+
+	reader, err := NewDecryptReader(bytes.NewReader(buffer.Bytes()), key)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	plain, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, expected := range []string{"Info message", "123-45-6789", "Second message"} {
+		if !bytes.Contains(plain, []byte(expected)) {
+			t.Errorf("Expected %s to contain %s", plain, expected)
+		}
+	}
+}
+
+// TestEncryptWriter_LargeEntry tests that writes larger than a chunk are split and reassembled.
+func TestEncryptWriter_LargeEntry(t *testing.T) {
+	key := bytes.Repeat([]byte{1}, 16)
+	buffer := new(bytes.Buffer)
+	writer, _ := NewEncryptWriter(buffer, key)
+
+	entry := bytes.Repeat([]byte("x"), 3*maxEncryptChunk+10)
+	if _, err := writer.Write(entry); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	writer.Close()
+
+	reader, _ := NewDecryptReader(buffer, key)
+	plain, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !bytes.Equal(plain, entry) {
+		t.Errorf("Expected %d bytes, got %d", len(entry), len(plain))
+	}
+}
+
+// TestDecryptReader_Tampered tests that modified ciphertext is rejected.
+func TestDecryptReader_Tampered(t *testing.T) {
+	key := bytes.Repeat([]byte{2}, 32)
+	buffer := new(bytes.Buffer)
+	writer, _ := NewEncryptWriter(buffer, key)
+	_, _ = writer.Write([]byte(`{"msg":"secret"}`))
+
+	data := buffer.Bytes()
+	data[len(data)-1] ^= 0xff
+
+	reader, _ := NewDecryptReader(bytes.NewReader(data), key)
+	if _, err := io.ReadAll(reader); !errors.Is(err, ErrCorruptChunk) {
+		t.Errorf("Expected ErrCorruptChunk, got %v", err)
+	}
+}
+
+// TestDecryptReader_Reordered tests that swapped chunks are rejected.
+func TestDecryptReader_Reordered(t *testing.T) {
+	key := bytes.Repeat([]byte{3}, 32)
+	buffer := new(bytes.Buffer)
+	writer, _ := NewEncryptWriter(buffer, key)
+	_, _ = writer.Write([]byte("first\n"))
+	_, _ = writer.Write([]byte("other\n"))
+	writer.Close()
+
+	records := splitEncryptRecords(t, buffer.Bytes())
+	records[1], records[2] = records[2], records[1]
+
+	reader, _ := NewDecryptReader(bytes.NewReader(bytes.Join(records, nil)), key)
+	if _, err := io.ReadAll(reader); !errors.Is(err, ErrCorruptChunk) {
+		t.Errorf("Expected ErrCorruptChunk, got %v", err)
+	}
+}
+
+// TestDecryptReader_Truncated tests that a stream missing its last chunks is rejected.
+func TestDecryptReader_Truncated(t *testing.T) {
+	key := bytes.Repeat([]byte{4}, 32)
+	buffer := new(bytes.Buffer)
+	writer, _ := NewEncryptWriter(buffer, key)
+	_, _ = writer.Write([]byte("first\n"))
+	_, _ = writer.Write([]byte("second\n"))
+	writer.Close()
+	records := splitEncryptRecords(t, buffer.Bytes())
+
+	for _, keep := range []int{len(records) - 1, len(records) - 2} {
+		reader, _ := NewDecryptReader(bytes.NewReader(bytes.Join(records[:keep], nil)), key)
+		plain, err := io.ReadAll(reader)
+		if !errors.Is(err, ErrTruncatedStream) {
+			t.Errorf("Expected ErrTruncatedStream keeping %d records, got %v", keep, err)
+		}
+		if !bytes.HasPrefix([]byte("first\nsecond\n"), plain) {
+			t.Errorf("Expected the plaintext before the truncation, got %q", plain)
+		}
+	}
+
+	// Dropping the final chunk of a stream followed by another is also detected.
+	next := new(bytes.Buffer)
+	second, _ := NewEncryptWriter(next, key)
+	_, _ = second.Write([]byte("third\n"))
+	second.Close()
+	spliced := append(bytes.Join(records[:len(records)-1], nil), next.Bytes()...)
+	reader, _ := NewDecryptReader(bytes.NewReader(spliced), key)
+	if _, err := io.ReadAll(reader); !errors.Is(err, ErrTruncatedStream) {
+		t.Errorf("Expected ErrTruncatedStream, got %v", err)
+	}
+}
+
+// TestDecryptReader_AppendedStreams tests that streams appended across restarts are read in order.
+func TestDecryptReader_AppendedStreams(t *testing.T) {
+	key := bytes.Repeat([]byte{5}, 32)
+	buffer := new(bytes.Buffer)
+	for _, line := range []string{"first\n", "second\n"} {
+		writer, _ := NewEncryptWriter(buffer, key)
+		_, _ = writer.Write([]byte(line))
+		writer.Close()
+	}
+
+	reader, _ := NewDecryptReader(buffer, key)
+	plain, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if string(plain) != "first\nsecond\n" {
+		t.Errorf("Unexpected plaintext %q", plain)
+	}
+}
+
+// splitEncryptRecords splits an encrypted stream into its length-prefixed records.
+func splitEncryptRecords(t *testing.T, data []byte) [][]byte {
+	t.Helper()
+	var records [][]byte
+	for len(data) > 0 {
+		if len(data) < 4 {
+			t.Fatalf("Unexpected trailing bytes")
+		}
+		n := 4 + int(binary.BigEndian.Uint32(data))
+		records = append(records, data[:n])
+		data = data[n:]
+	}
+	return records
+}
+
+// TestNewEncryptWriter_InvalidKey tests that invalid key sizes are rejected.
+func TestNewEncryptWriter_InvalidKey(t *testing.T) {
+	if _, err := NewEncryptWriter(new(bytes.Buffer), []byte("short")); err == nil {
+		t.Errorf("Expected an error for an invalid key, got nil")
+	}
+}
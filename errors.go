@@ -0,0 +1,52 @@
+package logger
+
+import (
+	"fmt"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// reportError writes err to the error output and passes it to Config.OnError.
+func (s *state) reportError(err error) {
+	fmt.Fprintf(s.errors, "logger: %v\n", err)
+	if s.onError != nil {
+		s.onError(err)
+	}
+}
+
+// reportingCore wraps core so that failed writes reach Config.OnError. zap
+// itself reports them to the error output.
+func (s *state) reportingCore(core zapcore.Core) zapcore.Core {
+	if s.onError == nil {
+		return core
+	}
+	return errorCore{Core: core, onError: s.onError}
+}
+
+// errorCore passes the errors of a core's writes to onError.
+type errorCore struct {
+	zapcore.Core
+	onError func(error)
+}
+
+// With returns a child core that also reports its errors.
+func (c errorCore) With(fields []zapcore.Field) zapcore.Core {
+	return errorCore{Core: c.Core.With(fields), onError: c.onError}
+}
+
+// Check adds c to the checked entry if the level is enabled.
+func (c errorCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+// Write writes the entry and reports a failure to onError.
+func (c errorCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	err := c.Core.Write(entry, fields)
+	if err != nil {
+		c.onError(err)
+	}
+	return err
+}
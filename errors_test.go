@@ -0,0 +1,46 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// TestZap_OnError tests that sink write and hook failures are passed to OnError.
+func TestZap_OnError(t *testing.T) {
+	var mu sync.Mutex
+	var reported []string
+	errOut := new(bytes.Buffer)
+	zapLogger := NewZap(Config{
+		Level:        InfoLevel,
+		Output:       new(bytes.Buffer),
+		Destinations: []Destination{{Output: failingWriter{}, Level: ErrorLevel}},
+		ErrorOutput:  errOut,
+		OnError: func(err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			reported = append(reported, err.Error())
+		},
+	})
+	zapLogger.AddHook(func(entry *Entry) error {
+		if entry.Message == "hooked" {
+			return errors.New("broken hook")
+		}
+		return nil
+	})
+
+	zapLogger.Info("Info message", nil)
+	zapLogger.Error("Error message", nil)
+	zapLogger.Info("hooked", nil)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(reported) != 2 || !strings.Contains(reported[0], "disk full") || reported[1] != "hook failed: broken hook" {
+		t.Errorf("Expected the write and hook failures, got %q", reported)
+	}
+	if !bytes.Contains(errOut.Bytes(), []byte("disk full")) {
+		t.Errorf("Expected the failures on the error output too, got %s", errOut.String())
+	}
+}
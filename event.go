@@ -0,0 +1,48 @@
+package logger
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// eventKey is the key holding the event name.
+const eventKey = "event"
+
+// newEventCore returns the core that writes events to config.EventOutput, or to
+// config.Output if it is not set. Events are always JSON and carry no level or
+// caller.
+func newEventCore(config Config) zapcore.Core {
+	output := config.EventOutput
+	if output == nil {
+		output = config.Output
+	}
+	if output == nil {
+		return nil
+	}
+	encoderConfig := newEncoderConfig(config)
+	encoderConfig.MessageKey = eventKey
+	encoderConfig.LevelKey = zapcore.OmitKey
+	encoderConfig.CallerKey = zapcore.OmitKey
+	encoderConfig.StacktraceKey = zapcore.OmitKey
+	return zapcore.NewCore(zapcore.NewJSONEncoder(encoderConfig), zapcore.AddSync(output), zapcore.DebugLevel)
+}
+
+// Event writes a business or analytics event, e.g. z.Event("signup", Fields{"plan": "pro"}).
+// Unlike diagnostic entries, events are always written as JSON to EventOutput,
+// regardless of the level, and are never sampled or passed to hooks. Fields
+// added with With are not included.
+func (z *Zap) Event(name string, fields Fields) {
+	if z.state.events == nil || atomic.LoadUint32(&z.state.closed) != 0 {
+		return
+	}
+	buf := getFieldBuffer()
+	zapFields := z.conv.finish(z.conv.appendFields(*buf, fields), false)
+	err := z.state.events.Write(zapcore.Entry{Time: z.now(), Message: name}, zapFields)
+	*buf = zapFields
+	putFieldBuffer(buf)
+	if err != nil {
+		z.state.reportError(fmt.Errorf("event write failed: %w", err))
+	}
+}
@@ -0,0 +1,47 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+// TestZap_Event tests that events are written as JSON to the event output regardless of level and format.
+func TestZap_Event(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	events := new(bytes.Buffer)
+	zapLogger := NewZap(Config{Level: ErrorLevel, Output: buffer, Format: FormatConsole, EventOutput: events})
+	zapLogger.AddHook(func(entry *Entry) error {
+		t.Errorf("Expected hooks not to run for events")
+		return nil
+	})
+
+	zapLogger.Event("signup", Fields{"plan": "pro"})
+
+	if buffer.Len() != 0 {
+		t.Errorf("Expected no diagnostic output, got %s", buffer.String())
+	}
+	var entry map[string]interface{}
+	if err := json.Unmarshal(events.Bytes(), &entry); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if entry["event"] != "signup" || entry["plan"] != "pro" {
+		t.Errorf("Expected the event name and fields, got %v", entry)
+	}
+	for _, key := range []string{"level", "caller"} {
+		if _, ok := entry[key]; ok {
+			t.Errorf("Expected no %s key, got %v", key, entry)
+		}
+	}
+}
+
+// TestZap_EventDefaultOutput tests that events default to the main output.
+func TestZap_EventDefaultOutput(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	zapLogger := NewZap(Config{Level: InfoLevel, Output: buffer})
+
+	zapLogger.Event("checkout", nil)
+	if !bytes.Contains(buffer.Bytes(), []byte(`"event":"checkout"`)) {
+		t.Errorf("Expected the event in the main output, got %s", buffer.String())
+	}
+}
@@ -0,0 +1,83 @@
+package logger
+
+// Field is a single key-value pair for structured logging. Passing fields to the
+// *Fields logging methods avoids allocating a Fields map and keeps their order.
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// FieldLogger is implemented by loggers that accept typed fields in addition to
+// the Fields map accepted by Logger.
+type FieldLogger interface {
+	DebugFields(msg string, fields ...Field)
+	InfoFields(msg string, fields ...Field)
+	WarnFields(msg string, fields ...Field)
+	ErrorFields(msg string, fields ...Field)
+	FatalFields(msg string, fields ...Field)
+}
+
+// RawJSON is a field value holding an already encoded JSON document, which is
+// embedded into the output as-is instead of being encoded as a string.
+// json.RawMessage values are treated the same way.
+type RawJSON []byte
+
+// BinaryEncoding controls how []byte field values are written.
+type BinaryEncoding int
+
+const (
+	// BinaryBase64 writes the full value as standard base64.
+	BinaryBase64 BinaryEncoding = iota
+	// BinaryHex writes the full value as lowercase hex.
+	BinaryHex
+	// BinaryPreview writes the first 32 bytes as hex followed by the total
+	// length, so large payloads stay readable and bounded.
+	BinaryPreview
+)
+
+// String returns a Field with a string value.
+func String(key, value string) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Int returns a Field with an int value.
+func Int(key string, value int) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Int64 returns a Field with an int64 value.
+func Int64(key string, value int64) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Float64 returns a Field with a float64 value.
+func Float64(key string, value float64) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Bool returns a Field with a bool value.
+func Bool(key string, value bool) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Err returns a Field holding err under the "error" key.
+func Err(err error) Field {
+	return Field{Key: "error", Value: err}
+}
+
+// Any returns a Field with an arbitrary value.
+func Any(key string, value interface{}) Field {
+	return Field{Key: key, Value: value}
+}
+
+// fieldsToMap merges typed fields into a copy of fields.
+func fieldsToMap(fields Fields, typed []Field) Fields {
+	merged := make(Fields, len(fields)+len(typed))
+	for k, v := range fields {
+		merged[k] = v
+	}
+	for _, f := range typed {
+		merged[f.Key] = f.Value
+	}
+	return merged
+}
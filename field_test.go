@@ -0,0 +1,54 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+)
+
+// TestZap_InfoFields tests that typed fields are written in order.
+func TestZap_InfoFields(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	var zapLogger FieldLogger = NewZap(Config{Level: InfoLevel, Output: buffer})
+
+	zapLogger.InfoFields("Info message",
+		String("b", "value"),
+		Int("a", 42),
+		Int64("c", 64),
+		Float64("d", 3.14),
+		Bool("e", true),
+		Err(errors.New("boom")),
+		Any("f", []int{1, 2}),
+	)
+
+	expected := `"b":"value","a":42,"c":64,"d":3.14,"e":true,"error":"boom","f":[1,2]`
+	if !bytes.Contains(buffer.Bytes(), []byte(expected)) {
+		t.Errorf("Expected %s to contain %s", buffer.String(), expected)
+	}
+}
+
+// TestZap_FatalFields tests that FatalFields applies the fatal behavior.
+func TestZap_FatalFields(t *testing.T) {
+	exitCode := -1
+	zapLogger := NewZap(Config{
+		Output:       io.Discard,
+		ExitFunc:     func(code int) { exitCode = code },
+		ExitCodeFunc: func(f Fields) int { return f["code"].(int) },
+	})
+
+	zapLogger.FatalFields("Fatal message", Int("code", 5))
+	if exitCode != 5 {
+		t.Errorf("Expected exit code 5, got %d", exitCode)
+	}
+}
+
+// TestObserve_Fields tests that the observer records typed fields.
+func TestObserve_Fields(t *testing.T) {
+	log, logs := Observe(Config{})
+
+	log.(FieldLogger).WarnFields("Warn message", String("key", "value"))
+	if logs.FilterField("key", "value").Len() != 1 {
+		t.Errorf("Expected the typed field to be recorded, got %+v", logs.All())
+	}
+}
@@ -0,0 +1,343 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// fileTimeFormat stamps rotated files; it sorts in time order.
+const fileTimeFormat = "20060102T150405.000000000Z"
+
+// FileConfig holds the configuration for a rotating file writer.
+type FileConfig struct {
+	// Path is the log file, e.g. /var/log/app/app.log. Rotated files are
+	// written beside it with a UTC timestamp before the extension.
+	Path string
+	// MaxBytes rotates the file once a write would grow it past this size.
+	// Zero means the file is never rotated.
+	MaxBytes int64
+	// MaxBackups is the number of rotated files kept; older ones are removed.
+	// Zero keeps them all.
+	MaxBackups int
+	// Shared is for several processes, possibly on several hosts, logging to
+	// the same directory. The hostname and pid are added to the file names,
+	// e.g. app-web1-4242.log, so each process writes its own file, and
+	// rotations hold an advisory lock on Path + ".lock", so no process
+	// overwrites or prunes a file another is rotating.
+	Shared bool
+	// Hostname overrides the hostname used in shared file names; defaults to
+	// os.Hostname.
+	Hostname string
+	// Clock stamps rotated files; defaults to the system clock.
+	Clock Clock
+}
+
+// FileWriter is an output that appends entries to a file and rotates it by
+// size.
+type FileWriter struct {
+	config FileConfig
+	// base is the file name without its extension, including the hostname and
+	// pid in shared mode; ext is the extension, e.g. ".log".
+	dir, base, ext string
+	mu             sync.Mutex
+	file           *os.File
+	size           int64
+}
+
+// NewFileWriterE opens the file of config for appending, creating it and its
+// directory if needed, and returns a new *FileWriter.
+func NewFileWriterE(config FileConfig) (*FileWriter, error) {
+	if config.Path == "" {
+		return nil, errors.New("file: path is not configured")
+	}
+	if config.MaxBytes < 0 || config.MaxBackups < 0 {
+		return nil, errors.New("file: negative MaxBytes or MaxBackups")
+	}
+	dir, name := filepath.Split(config.Path)
+	ext := filepath.Ext(name)
+	base := strings.TrimSuffix(name, ext)
+	if config.Shared {
+		if config.Hostname == "" {
+			host, err := os.Hostname()
+			if err != nil {
+				return nil, fmt.Errorf("file: hostname: %w", err)
+			}
+			config.Hostname = host
+		}
+		base = fmt.Sprintf("%s-%s-%d", base, fileNameSafe(config.Hostname), os.Getpid())
+	}
+	if dir == "" {
+		dir = "."
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("file: %w", err)
+	}
+
+	w := &FileWriter{config: config, dir: dir, base: base, ext: ext}
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// MustFileWriter is like NewFileWriterE but panics on error.
+func MustFileWriter(config FileConfig) *FileWriter {
+	w, err := NewFileWriterE(config)
+	if err != nil {
+		panic(err)
+	}
+	return w
+}
+
+// Name returns the path of the file currently written.
+func (w *FileWriter) Name() string {
+	return filepath.Join(w.dir, w.base+w.ext)
+}
+
+// Write appends p to the file, rotating it first if p would grow it past
+// MaxBytes. If the rotation fails, p is still appended and the rotation error
+// is returned.
+func (w *FileWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file == nil {
+		return 0, errors.New("file: writer is closed")
+	}
+	var rotateErr error
+	if w.config.MaxBytes > 0 && w.size > 0 && w.size+int64(len(p)) > w.config.MaxBytes {
+		// A failed rotation is reported, but the entry is still written to
+		// the current file when it is open.
+		rotateErr = w.rotate()
+		if w.file == nil {
+			return 0, rotateErr
+		}
+	}
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+	if err == nil {
+		err = rotateErr
+	}
+	return n, err
+}
+
+// Sync flushes the file to disk.
+func (w *FileWriter) Sync() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.file == nil {
+		return nil
+	}
+	return w.file.Sync()
+}
+
+// Rotate rotates the file now, whatever its size.
+func (w *FileWriter) Rotate() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.file == nil {
+		return errors.New("file: writer is closed")
+	}
+	return w.rotate()
+}
+
+// Close closes the file.
+func (w *FileWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.file == nil {
+		return nil
+	}
+	err := w.file.Close()
+	w.file = nil
+	return err
+}
+
+// open opens the current file for appending. The caller must hold w.mu.
+func (w *FileWriter) open() error {
+	file, err := os.OpenFile(w.Name(), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("file: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("file: %w", err)
+	}
+	w.file, w.size = file, info.Size()
+	return nil
+}
+
+// rotate renames the current file to a timestamped backup, opens a new one,
+// and prunes old backups. In shared mode it holds the advisory lock
+// throughout. If the rename fails, the current file is reopened, so a failed
+// rotation only delays rotation. The caller must hold w.mu.
+func (w *FileWriter) rotate() error {
+	if w.config.Shared {
+		unlock, err := lockFile(w.config.Path + ".lock")
+		if err != nil {
+			return fmt.Errorf("file: lock: %w", err)
+		}
+		defer unlock()
+	}
+
+	backup, err := w.backupName()
+	if err != nil {
+		return err
+	}
+	closeErr := w.file.Close()
+	w.file = nil
+	if err := os.Rename(w.Name(), backup); err != nil {
+		if openErr := w.open(); openErr != nil {
+			return openErr
+		}
+		return fmt.Errorf("file: rotate: %w", err)
+	}
+	if err := w.open(); err != nil {
+		return err
+	}
+	if closeErr != nil {
+		return fmt.Errorf("file: %w", closeErr)
+	}
+	return w.prune()
+}
+
+// backupName returns a name for the next backup that no file has yet, adding
+// a counter when two rotations share a timestamp.
+func (w *FileWriter) backupName() (string, error) {
+	stamp := w.now().UTC().Format(fileTimeFormat)
+	for i := 0; i < 1000; i++ {
+		name := w.base + "-" + stamp
+		if i > 0 {
+			name += "." + strconv.Itoa(i)
+		}
+		path := filepath.Join(w.dir, name+w.ext)
+		if _, err := os.Lstat(path); os.IsNotExist(err) {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("file: no free backup name for %s", w.Name())
+}
+
+// Backups returns the paths of the rotated files, oldest first. In shared mode
+// these are the backups of every process on this host.
+func (w *FileWriter) Backups() ([]string, error) {
+	entries, err := os.ReadDir(w.dir)
+	if err != nil {
+		return nil, fmt.Errorf("file: %w", err)
+	}
+	prefix := w.backupPrefix()
+	type backup struct {
+		path, stamp string
+		n           int
+	}
+	var backups []backup
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, w.ext) {
+			continue
+		}
+		rest := strings.TrimSuffix(strings.TrimPrefix(name, prefix), w.ext)
+		if w.config.Shared {
+			i := strings.IndexByte(rest, '-')
+			if i <= 0 {
+				continue
+			}
+			if _, err := strconv.Atoi(rest[:i]); err != nil {
+				continue
+			}
+			rest = rest[i+1:]
+		}
+		stamp, n, ok := parseBackupStamp(rest)
+		if !ok {
+			continue
+		}
+		backups = append(backups, backup{filepath.Join(w.dir, name), stamp, n})
+	}
+	sort.Slice(backups, func(i, j int) bool {
+		if backups[i].stamp != backups[j].stamp {
+			return backups[i].stamp < backups[j].stamp
+		}
+		return backups[i].n < backups[j].n
+	})
+	paths := make([]string, len(backups))
+	for i, b := range backups {
+		paths[i] = b.path
+	}
+	return paths, nil
+}
+
+// parseBackupStamp parses the timestamp and optional counter ending a backup
+// name, as written by backupName.
+func parseBackupStamp(s string) (string, int, bool) {
+	if len(s) < len(fileTimeFormat) {
+		return "", 0, false
+	}
+	stamp, counter := s[:len(fileTimeFormat)], s[len(fileTimeFormat):]
+	if _, err := time.Parse(fileTimeFormat, stamp); err != nil {
+		return "", 0, false
+	}
+	if counter == "" {
+		return stamp, 0, true
+	}
+	n, err := strconv.Atoi(strings.TrimPrefix(counter, "."))
+	if err != nil || counter[0] != '.' || n <= 0 {
+		return "", 0, false
+	}
+	return stamp, n, true
+}
+
+// backupPrefix returns the prefix shared by the names of the backups pruned by
+// w: the file name, and the hostname in shared mode, so a restarted process
+// also prunes the backups of its predecessors.
+func (w *FileWriter) backupPrefix() string {
+	if !w.config.Shared {
+		return w.base + "-"
+	}
+	name := strings.TrimSuffix(filepath.Base(w.config.Path), w.ext)
+	return name + "-" + fileNameSafe(w.config.Hostname) + "-"
+}
+
+// prune removes the oldest backups beyond MaxBackups.
+func (w *FileWriter) prune() error {
+	if w.config.MaxBackups == 0 {
+		return nil
+	}
+	backups, err := w.Backups()
+	if err != nil {
+		return err
+	}
+	for len(backups) > w.config.MaxBackups {
+		if err := os.Remove(backups[0]); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("file: prune: %w", err)
+		}
+		backups = backups[1:]
+	}
+	return nil
+}
+
+// now returns the current time from the configured clock.
+func (w *FileWriter) now() time.Time {
+	if w.config.Clock != nil {
+		return w.config.Clock.Now()
+	}
+	return time.Now()
+}
+
+// fileNameSafe replaces characters that are not safe in file names.
+func fileNameSafe(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '_':
+			return r
+		}
+		return '_'
+	}, s)
+}
@@ -0,0 +1,38 @@
+//go:build !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd
+
+package logger
+
+import (
+	"errors"
+	"os"
+	"time"
+)
+
+// lockTimeout bounds the wait for a lock file left behind by a crashed process.
+const lockTimeout = 10 * time.Second
+
+// lockFile takes an exclusive lock by creating the file at path, which must
+// not exist, and returns the function releasing it by removing the file. It
+// waits while another process holds the lock and takes over a lock older than
+// lockTimeout.
+func lockFile(path string) (func(), error) {
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+		if err == nil {
+			file.Close()
+			return func() { os.Remove(path) }, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, err
+		}
+		if info, err := os.Stat(path); err == nil && time.Since(info.ModTime()) > lockTimeout {
+			os.Remove(path)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, errors.New("timed out waiting for " + path)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
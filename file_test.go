@@ -0,0 +1,148 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestFileWriter_Rotate tests that the file is rotated by size and old backups are pruned.
+func TestFileWriter_Rotate(t *testing.T) {
+	dir := t.TempDir()
+	clock := &manualClock{now: time.Date(2024, 5, 1, 13, 0, 0, 0, time.UTC)}
+	w := MustFileWriter(FileConfig{Path: filepath.Join(dir, "app.log"), MaxBytes: 10, MaxBackups: 2, Clock: clock})
+	defer w.Close()
+
+	for i := 0; i < 4; i++ {
+		if _, err := w.Write([]byte("entry 000\n")); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		clock.now = clock.now.Add(time.Second)
+	}
+
+	backups, err := w.Backups()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	expected := []string{
+		filepath.Join(dir, "app-20240501T130002.000000000Z.log"),
+		filepath.Join(dir, "app-20240501T130003.000000000Z.log"),
+	}
+	if fmt.Sprint(backups) != fmt.Sprint(expected) {
+		t.Errorf("Expected backups %v, got %v", expected, backups)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "app.log"))
+	if err != nil || string(data) != "entry 000\n" {
+		t.Errorf("Expected one entry in the current file, got %q (%v)", data, err)
+	}
+}
+
+// TestFileWriter_Shared tests that shared files are named after the host and
+// pid, that rotations never overwrite an existing backup, and that the lock
+// file is used.
+func TestFileWriter_Shared(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	clock := &manualClock{now: time.Date(2024, 5, 1, 13, 0, 0, 0, time.UTC)}
+	w := MustFileWriter(FileConfig{Path: path, Shared: true, Hostname: "web-1.local", Clock: clock})
+	defer w.Close()
+
+	expected := filepath.Join(dir, fmt.Sprintf("app-web_1.local-%d.log", os.Getpid()))
+	if w.Name() != expected {
+		t.Errorf("Expected file %q, got %q", expected, w.Name())
+	}
+
+	for i := 0; i < 3; i++ {
+		w.Write([]byte(fmt.Sprintf("entry %d\n", i)))
+		if err := w.Rotate(); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+
+	backups, err := w.Backups()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(backups) != 3 {
+		t.Fatalf("Expected 3 backups with the same timestamp, got %v", backups)
+	}
+	for i, backup := range backups {
+		data, _ := os.ReadFile(backup)
+		if string(data) != fmt.Sprintf("entry %d\n", i) {
+			t.Errorf("Expected backup %s to hold entry %d, got %q", backup, i, data)
+		}
+	}
+	if !strings.HasSuffix(backups[2], ".2.log") {
+		t.Errorf("Expected a counter on colliding backups, got %v", backups)
+	}
+	if _, err := os.Stat(path); err == nil {
+		t.Errorf("Expected nothing written to the unshared path %s", path)
+	}
+}
+
+// TestFileWriter_SharedPrune tests that pruning in shared mode keeps the
+// backups of other hosts.
+func TestFileWriter_SharedPrune(t *testing.T) {
+	dir := t.TempDir()
+	other := filepath.Join(dir, "app-db1-77-20240101T000000.000000000Z.log")
+	previous := filepath.Join(dir, "app-web1-76-20240101T000000.000000000Z.log")
+	for _, name := range []string{other, previous} {
+		if err := os.WriteFile(name, []byte("old\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	clock := &manualClock{now: time.Date(2024, 5, 1, 13, 0, 0, 0, time.UTC)}
+	w := MustFileWriter(FileConfig{Path: filepath.Join(dir, "app.log"), Shared: true, Hostname: "web1", MaxBackups: 1, Clock: clock})
+	defer w.Close()
+	w.Write([]byte("entry\n"))
+	if err := w.Rotate(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if _, err := os.Stat(previous); !os.IsNotExist(err) {
+		t.Errorf("Expected the backup of a previous process on this host to be pruned")
+	}
+	if _, err := os.Stat(other); err != nil {
+		t.Errorf("Expected the backup of another host to be kept, got %v", err)
+	}
+}
+
+// TestNewFileWriterE tests that a path is required.
+func TestNewFileWriterE(t *testing.T) {
+	if _, err := NewFileWriterE(FileConfig{}); err == nil {
+		t.Errorf("Expected an error without a path")
+	}
+}
+
+// TestFileWriter_RotateFailure tests that logging continues after a failed rotation.
+func TestFileWriter_RotateFailure(t *testing.T) {
+	dir := t.TempDir()
+	w := MustFileWriter(FileConfig{Path: filepath.Join(dir, "app.log"), MaxBytes: 10})
+	defer w.Close()
+	w.Write([]byte("entry 000\n"))
+
+	// Removing the current file makes the rename of the rotation fail.
+	if err := os.Remove(w.Name()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write([]byte("entry 001\n")); err == nil || !strings.Contains(err.Error(), "rotate") {
+		t.Errorf("Expected the rotation error, got %v", err)
+	}
+	if _, err := w.Write([]byte("entry 002\n")); err != nil {
+		t.Errorf("Expected later writes to succeed, got %v", err)
+	}
+
+	backups, err := w.Backups()
+	if err != nil || len(backups) != 1 {
+		t.Fatalf("Expected the next rotation to succeed, got %v (%v)", backups, err)
+	}
+	for path, expected := range map[string]string{backups[0]: "entry 001\n", w.Name(): "entry 002\n"} {
+		if data, err := os.ReadFile(path); err != nil || string(data) != expected {
+			t.Errorf("Expected %s to hold %q, got %q (%v)", path, expected, data, err)
+		}
+	}
+}
@@ -0,0 +1,220 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Format selects how entries are encoded for an output.
+type Format int
+
+const (
+	// FormatJSON writes one JSON object per line.
+	FormatJSON Format = iota
+	// FormatConsole writes human-readable, tab-separated lines for terminals.
+	FormatConsole
+	// FormatGELF writes GELF 1.1 JSON objects for Graylog: the message is
+	// short_message, the level is the syslog severity, and fields are prefixed
+	// with an underscore.
+	FormatGELF
+	// FormatAuto selects FormatConsole when the output is a terminal and no
+	// managed environment such as Kubernetes, CI, or systemd is detected, and
+	// FormatJSON otherwise. The FormatEnv environment variable overrides it.
+	FormatAuto
+	// FormatCEF writes ArcSight Common Event Format lines for SIEMs, with the
+	// header configured by Config.CEF.
+	FormatCEF
+	// FormatCSV writes one CSV row per entry with the columns declared by
+	// Config.CSV, for bulk loading into data warehouses.
+	FormatCSV
+	// FormatMsgPack writes each entry as a MessagePack map, a compact binary
+	// encoding for shipping high volumes to collectors that accept it.
+	FormatMsgPack
+	// FormatProtobuf writes each entry as a length-prefixed OpenTelemetry
+	// LogRecord protobuf message, for streaming to collectors over gRPC.
+	FormatProtobuf
+)
+
+// formatNames holds the names of the formats, indexed by Format.
+var formatNames = [...]string{"json", "console", "gelf", "auto", "cef", "csv", "msgpack", "protobuf"}
+
+// String returns the name of the format as accepted by ParseFormat.
+func (f Format) String() string {
+	if f.valid() {
+		return formatNames[f]
+	}
+	return fmt.Sprintf("Format(%d)", int(f))
+}
+
+// FormatEnv is the environment variable that overrides FormatAuto with the
+// name of another format, such as json or console.
+const FormatEnv = "LOG_FORMAT"
+
+// managedEnv lists environment variables set by platforms whose logs are
+// collected by machines rather than read on a terminal.
+var managedEnv = []string{
+	"KUBERNETES_SERVICE_HOST",       // Kubernetes
+	"CI",                            // most CI services
+	"INVOCATION_ID",                 // systemd services
+	"JOURNAL_STREAM",                // systemd with journald
+	"AWS_LAMBDA_FUNCTION_NAME",      // AWS Lambda
+	"ECS_CONTAINER_METADATA_URI_V4", // Amazon ECS
+	"K_SERVICE",                     // Cloud Run and Knative
+}
+
+// ParseFormat returns the format named by s: json, console, gelf, cef, csv,
+// msgpack, protobuf, or auto, in any case.
+func ParseFormat(s string) (Format, error) {
+	switch strings.ToLower(s) {
+	case "json":
+		return FormatJSON, nil
+	case "console":
+		return FormatConsole, nil
+	case "gelf":
+		return FormatGELF, nil
+	case "cef":
+		return FormatCEF, nil
+	case "csv":
+		return FormatCSV, nil
+	case "msgpack":
+		return FormatMsgPack, nil
+	case "protobuf":
+		return FormatProtobuf, nil
+	case "auto":
+		return FormatAuto, nil
+	}
+	return 0, fmt.Errorf("unknown format %q", s)
+}
+
+// resolveFormat returns the format to use for output, resolving FormatAuto.
+func resolveFormat(format Format, output io.Writer) Format {
+	if format != FormatAuto {
+		return format
+	}
+	if f, err := ParseFormat(os.Getenv(FormatEnv)); err == nil && f != FormatAuto {
+		return f
+	}
+	for _, key := range managedEnv {
+		if os.Getenv(key) != "" {
+			return FormatJSON
+		}
+	}
+	if isTerminal(output) {
+		return FormatConsole
+	}
+	return FormatJSON
+}
+
+// isTerminal reports whether w is a character device such as a terminal.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// gelfVersion is the GELF specification version written in every entry.
+const gelfVersion = "1.1"
+
+// valid reports whether f is a known format.
+func (f Format) valid() bool {
+	return f >= FormatJSON && f <= FormatProtobuf
+}
+
+// usesFormat reports whether the output or a destination of config is
+// explicitly encoded in format.
+func usesFormat(config Config, format Format) bool {
+	if config.Format == format {
+		return true
+	}
+	for _, dest := range config.Destinations {
+		if dest.Format == format {
+			return true
+		}
+	}
+	return false
+}
+
+// newFormatCore returns a core writing entries encoded in format to ws.
+func newFormatCore(config Config, format Format, encoderConfig zapcore.EncoderConfig, ws zapcore.WriteSyncer, enabler zapcore.LevelEnabler) zapcore.Core {
+	switch format {
+	case FormatConsole:
+		return zapcore.NewCore(zapcore.NewConsoleEncoder(encoderConfig), ws, enabler)
+	case FormatGELF:
+		return newGELFCore(encoderConfig, ws, enabler)
+	case FormatCEF:
+		return newCEFCore(config.CEF, encoderConfig, ws, enabler)
+	case FormatCSV:
+		return newCSVCore(config.CSV, encoderConfig, ws, enabler)
+	case FormatMsgPack:
+		return newMsgpackCore(encoderConfig, ws, enabler)
+	case FormatProtobuf:
+		return newProtobufCore(encoderConfig, ws, enabler)
+	}
+	return zapcore.NewCore(zapcore.NewJSONEncoder(encoderConfig), ws, enabler)
+}
+
+// newGELFCore returns a core writing GELF entries.
+func newGELFCore(encoderConfig zapcore.EncoderConfig, ws zapcore.WriteSyncer, enabler zapcore.LevelEnabler) zapcore.Core {
+	encoderConfig.MessageKey = "short_message"
+	encoderConfig.TimeKey = "timestamp"
+	encoderConfig.EncodeTime = zapcore.EpochTimeEncoder
+	encoderConfig.LevelKey = "level"
+	encoderConfig.EncodeLevel = func(l zapcore.Level, enc zapcore.PrimitiveArrayEncoder) {
+		enc.AppendInt(SyslogSeverity(fromZapLevel(l)))
+	}
+	if encoderConfig.CallerKey != "" {
+		encoderConfig.CallerKey = "_" + encoderConfig.CallerKey
+	}
+	if encoderConfig.FunctionKey != "" {
+		encoderConfig.FunctionKey = "_" + encoderConfig.FunctionKey
+	}
+	encoderConfig.StacktraceKey = "_stacktrace"
+	encoderConfig.NameKey = "_logger"
+
+	host, _ := os.Hostname()
+	core := zapcore.NewCore(zapcore.NewJSONEncoder(encoderConfig), ws, enabler).
+		With([]zapcore.Field{zap.String("version", gelfVersion), zap.String("host", host)})
+	return gelfCore{core}
+}
+
+// gelfCore prefixes field keys with an underscore, as GELF requires for
+// additional fields.
+type gelfCore struct {
+	zapcore.Core
+}
+
+// With adds prefixed fields to a child core.
+func (c gelfCore) With(fields []zapcore.Field) zapcore.Core {
+	return gelfCore{c.Core.With(gelfFields(fields))}
+}
+
+// Check adds c to the checked entry if the level is enabled.
+func (c gelfCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+// Write writes the entry with prefixed fields.
+func (c gelfCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	return c.Core.Write(entry, gelfFields(fields))
+}
+
+// gelfFields returns a copy of fields with underscore-prefixed keys.
+func gelfFields(fields []zapcore.Field) []zapcore.Field {
+	prefixed := make([]zapcore.Field, len(fields))
+	for i, f := range fields {
+		f.Key = "_" + f.Key
+		prefixed[i] = f
+	}
+	return prefixed
+}
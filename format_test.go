@@ -0,0 +1,86 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+// TestFormat tests that each destination uses its own encoding for the same entry.
+func TestFormat(t *testing.T) {
+	console := new(bytes.Buffer)
+	jsonOut := new(bytes.Buffer)
+	gelf := new(bytes.Buffer)
+	zapLogger := NewZap(Config{
+		Level:  InfoLevel,
+		Output: console,
+		Format: FormatConsole,
+		Destinations: []Destination{
+			{Output: jsonOut},
+			{Output: gelf, Format: FormatGELF},
+		},
+	})
+
+	zapLogger.With(Fields{"service": "orders"}).Warn("Warn message", Fields{"key": "value"})
+
+	if !bytes.Contains(console.Bytes(), []byte("\twarn\t")) || !bytes.Contains(console.Bytes(), []byte(`{"service": "orders", "key": "value"}`)) {
+		t.Errorf("Expected a console line, got %s", console.String())
+	}
+	if !bytes.Contains(jsonOut.Bytes(), []byte(`"level":"warn"`)) {
+		t.Errorf("Expected a JSON line, got %s", jsonOut.String())
+	}
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(gelf.Bytes(), &entry); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := map[string]interface{}{
+		"version":       "1.1",
+		"short_message": "Warn message",
+		"level":         float64(4),
+		"_service":      "orders",
+		"_key":          "value",
+	}
+	for k, v := range expected {
+		if entry[k] != v {
+			t.Errorf("Expected GELF %s=%v, got %v", k, v, entry[k])
+		}
+	}
+	for _, k := range []string{"host", "timestamp", "_caller"} {
+		if _, ok := entry[k]; !ok {
+			t.Errorf("Expected GELF entry to have %s, got %v", k, entry)
+		}
+	}
+
+	if _, err := NewZapE(Config{Output: console, Destinations: []Destination{{Output: gelf, Format: FormatProtobuf + 1}}}); err == nil {
+		t.Errorf("Expected an error for an invalid destination format")
+	}
+}
+
+// TestFormat_Auto tests that FormatAuto picks JSON for non-terminals and honours the environment override.
+func TestFormat_Auto(t *testing.T) {
+	t.Setenv(FormatEnv, "")
+	buffer := new(bytes.Buffer)
+	zapLogger := NewZap(Config{Level: InfoLevel, Output: buffer, Format: FormatAuto})
+	zapLogger.Info("Info message", nil)
+	if !bytes.HasPrefix(buffer.Bytes(), []byte("{")) || zapLogger.Config.Format != FormatJSON {
+		t.Errorf("Expected JSON output for a non-terminal, got %s", buffer.String())
+	}
+
+	t.Setenv(FormatEnv, "console")
+	buffer.Reset()
+	NewZap(Config{Level: InfoLevel, Output: buffer, Format: FormatAuto}).Info("Info message", nil)
+	if !bytes.Contains(buffer.Bytes(), []byte("\tinfo\t")) {
+		t.Errorf("Expected console output from %s, got %s", FormatEnv, buffer.String())
+	}
+}
+
+// TestParseFormat tests that format names are parsed case-insensitively.
+func TestParseFormat(t *testing.T) {
+	if f, err := ParseFormat("GELF"); err != nil || f != FormatGELF {
+		t.Errorf("Expected FormatGELF, got %v, %v", f, err)
+	}
+	if _, err := ParseFormat("xml"); err == nil {
+		t.Errorf("Expected an error for an unknown format")
+	}
+}
@@ -0,0 +1,35 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"runtime"
+	"strconv"
+)
+
+// goroutineID returns the ID of the calling goroutine, parsed from the header
+// of its stack trace, or 0 if it cannot be read.
+func goroutineID() uint64 {
+	var buf [64]byte
+	stack := buf[:runtime.Stack(buf[:], false)]
+	stack = bytes.TrimPrefix(stack, []byte("goroutine "))
+	if i := bytes.IndexByte(stack, ' '); i > 0 {
+		stack = stack[:i]
+	}
+	id, _ := strconv.ParseUint(string(stack), 10, 64)
+	return id
+}
+
+// WithWorker returns a copy of ctx carrying label as the worker label and a
+// child of its logger, or of Default, with a worker field, so entries logged
+// through FromContext identify the worker that produced them.
+func WithWorker(ctx context.Context, label string) context.Context {
+	ctx = context.WithValue(ctx, workerKey, label)
+	return NewContext(ctx, With(contextLogger(ctx, Default()), Fields{"worker": label}))
+}
+
+// WorkerFromContext returns the worker label carried by ctx, or "" if there is none.
+func WorkerFromContext(ctx context.Context) string {
+	label, _ := ctx.Value(workerKey).(string)
+	return label
+}
@@ -0,0 +1,52 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+// TestZap_GoroutineID tests that entries from different goroutines carry different IDs.
+func TestZap_GoroutineID(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	zapLogger := NewZap(Config{Level: InfoLevel, Output: buffer, GoroutineID: true})
+
+	zapLogger.Info("main", nil)
+	done := make(chan struct{})
+	go func() {
+		zapLogger.Info("worker", nil)
+		close(done)
+	}()
+	<-done
+
+	lines := bytes.Split(bytes.TrimSpace(buffer.Bytes()), []byte("\n"))
+	ids := make([]float64, len(lines))
+	for i, line := range lines {
+		var entry map[string]interface{}
+		if err := json.Unmarshal(line, &entry); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		ids[i], _ = entry["goroutine"].(float64)
+		if ids[i] == 0 {
+			t.Errorf("Expected a goroutine ID, got %s", line)
+		}
+	}
+	if len(ids) != 2 || ids[0] == ids[1] {
+		t.Errorf("Expected different goroutine IDs, got %v", ids)
+	}
+}
+
+// TestWithWorker tests that the worker label is bound to the context logger.
+func TestWithWorker(t *testing.T) {
+	observed, logs := Observe(Config{Level: InfoLevel})
+	ctx := WithWorker(NewContext(context.Background(), observed), "consumer-3")
+
+	FromContext(ctx).Info("Info message", nil)
+	if WorkerFromContext(ctx) != "consumer-3" {
+		t.Errorf("Expected worker label consumer-3, got %q", WorkerFromContext(ctx))
+	}
+	if logs.FilterField("worker", "consumer-3").Len() != 1 {
+		t.Errorf("Expected an entry with the worker label, got %v", logs.All())
+	}
+}
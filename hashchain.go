@@ -0,0 +1,124 @@
+package logger
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"sync"
+)
+
+// hashChainKey is the field holding the hash of the previous entry.
+const hashChainKey = "prev_hash"
+
+// ErrHashChainBroken is returned by VerifyHashChain when an entry was modified,
+// removed, or reordered.
+var ErrHashChainBroken = errors.New("hash chain broken")
+
+// genesisHash is the previous hash recorded on the first entry of a chain.
+var genesisHash = hex.EncodeToString(make([]byte, sha256.Size))
+
+// HashChainWriter is an output wrapper that appends the hash of the previous entry
+// to every JSON entry, producing an append-only, verifiable trail. When a key is
+// given, hashes are HMAC-SHA256 signatures so the chain cannot be recomputed by
+// someone who does not hold the key.
+type HashChainWriter struct {
+	mu     sync.Mutex
+	output io.Writer
+	key    []byte
+	prev   string
+}
+
+// NewHashChainWriter returns a new *HashChainWriter writing to output.
+func NewHashChainWriter(output io.Writer, key []byte) *HashChainWriter {
+	return &HashChainWriter{
+		output: output,
+		key:    key,
+		prev:   genesisHash,
+	}
+}
+
+// Resume continues an existing chain whose last hash is prev, as returned by
+// VerifyHashChain, so a reopened file keeps a single verifiable chain.
+func (h *HashChainWriter) Resume(prev string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.prev = prev
+}
+
+// Write appends the previous hash to the entry and writes it to the output.
+func (h *HashChainWriter) Write(p []byte) (int, error) {
+	line := bytes.TrimRight(p, "\n")
+	if len(line) == 0 || line[len(line)-1] != '}' {
+		return 0, errors.New("hash chain: entry is not a JSON object")
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	// Build the chained entry in a fresh buffer: line shares p, which belongs
+	// to the caller.
+	chained := make([]byte, 0, len(line)+len(hashChainKey)+len(h.prev)+8)
+	chained = appendHashField(append(chained, line[:len(line)-1]...), h.prev)
+	if _, err := h.output.Write(append(chained, '\n')); err != nil {
+		return 0, err
+	}
+	h.prev = chainHash(h.key, chained)
+	return len(p), nil
+}
+
+// Sync flushes the underlying output if it supports it.
+func (h *HashChainWriter) Sync() error {
+	if s, ok := h.output.(syncer); ok {
+		return s.Sync()
+	}
+	return nil
+}
+
+// VerifyHashChain reads entries written by a HashChainWriter and checks that each
+// records the hash of its predecessor. It returns the hash of the last entry.
+func VerifyHashChain(r io.Reader, key []byte) (string, error) {
+	prev := genesisHash
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	for n := 1; scanner.Scan(); n++ {
+		line := scanner.Bytes()
+		suffix := appendHashField(nil, prev)
+		if !bytes.HasSuffix(line, suffix) {
+			return "", fmt.Errorf("entry %d: %w", n, ErrHashChainBroken)
+		}
+		prev = chainHash(key, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	return prev, nil
+}
+
+// appendHashField appends the prev_hash member and the closing brace to dst.
+func appendHashField(dst []byte, prev string) []byte {
+	if len(dst) > 0 && dst[len(dst)-1] != '{' {
+		dst = append(dst, ',')
+	}
+	dst = append(dst, `"`+hashChainKey+`":"`...)
+	dst = append(dst, prev...)
+	return append(dst, `"}`...)
+}
+
+// chainHash returns the hex hash of an entry, keyed when key is non-empty.
+func chainHash(key, entry []byte) string {
+	var hasher hash.Hash
+	if len(key) > 0 {
+		hasher = hmac.New(sha256.New, key)
+	} else {
+		hasher = sha256.New()
+	}
+	hasher.Write(entry)
+	return hex.EncodeToString(hasher.Sum(nil))
+}
@@ -0,0 +1,91 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+// TestHashChainWriter tests that a chain written by HashChainWriter verifies.
+func TestHashChainWriter(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	key := []byte("secret")
+	zapLogger := NewZap(Config{
+		Level:    InfoLevel,
+		Output:   NewHashChainWriter(buffer, key),
+		ExitFunc: func(int) {},
+	})
+
+	zapLogger.Info("first", Fields{"n": 1})
+	zapLogger.Info("second", Fields{"n": 2})
+	zapLogger.Info("third", Fields{"n": 3})
+
+	if !bytes.Contains(buffer.Bytes(), []byte(`"prev_hash":"`+genesisHash+`"`)) {
+		t.Errorf("Expected first entry to reference the genesis hash, got %s", buffer.String())
+	}
+	if _, err := VerifyHashChain(bytes.NewReader(buffer.Bytes()), key); err != nil {
+		t.Errorf("Expected chain to verify, got %v", err)
+	}
+}
+
+// TestVerifyHashChain_Tampered tests that modified or removed entries are detected.
+func TestVerifyHashChain_Tampered(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	writer := NewHashChainWriter(buffer, nil)
+	for _, entry := range []string{`{"msg":"a"}`, `{"msg":"b"}`, `{"msg":"c"}`} {
+		if _, err := writer.Write([]byte(entry + "\n")); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	modified := bytes.Replace(buffer.Bytes(), []byte(`"msg":"b"`), []byte(`"msg":"x"`), 1)
+	if _, err := VerifyHashChain(bytes.NewReader(modified), nil); !errors.Is(err, ErrHashChainBroken) {
+		t.Errorf("Expected ErrHashChainBroken for a modified entry, got %v", err)
+	}
+
+	lines := bytes.SplitAfter(buffer.Bytes(), []byte("\n"))
+	removed := append(append([]byte{}, lines[0]...), lines[2]...)
+	if _, err := VerifyHashChain(bytes.NewReader(removed), nil); !errors.Is(err, ErrHashChainBroken) {
+		t.Errorf("Expected ErrHashChainBroken for a removed entry, got %v", err)
+	}
+
+	if _, err := VerifyHashChain(bytes.NewReader(buffer.Bytes()), []byte("key")); !errors.Is(err, ErrHashChainBroken) {
+		t.Errorf("Expected ErrHashChainBroken for the wrong key, got %v", err)
+	}
+}
+
+// TestHashChainWriter_Resume tests that a chain can be continued after reopening.
+func TestHashChainWriter_Resume(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	_, _ = NewHashChainWriter(buffer, nil).Write([]byte(`{"msg":"a"}` + "\n"))
+
+	last, err := VerifyHashChain(bytes.NewReader(buffer.Bytes()), nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	writer := NewHashChainWriter(buffer, nil)
+	writer.Resume(last)
+	_, _ = writer.Write([]byte(`{"msg":"b"}` + "\n"))
+
+	if _, err := VerifyHashChain(bytes.NewReader(buffer.Bytes()), nil); err != nil {
+		t.Errorf("Expected resumed chain to verify, got %v", err)
+	}
+}
+
+// TestHashChainWriter_KeepsInput tests that Write leaves the caller's buffer unchanged.
+func TestHashChainWriter_KeepsInput(t *testing.T) {
+	h := NewHashChainWriter(new(bytes.Buffer), nil)
+	p := make([]byte, 0, 64)
+	p = append(p, "{\"a\":1}\n"...)
+
+	if _, err := h.Write(p); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if string(p) != "{\"a\":1}\n" {
+		t.Errorf("Expected the input to be unchanged, got %q", p)
+	}
+	if tail := p[len(p):cap(p)]; bytes.ContainsAny(tail, "\",") {
+		t.Errorf("Expected the spare capacity of the input to be untouched, got %q", tail)
+	}
+}
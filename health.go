@@ -0,0 +1,80 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// HealthChecker is implemented by outputs that can report whether their
+// backend is reachable, such as the exporters and network writers of this
+// package.
+type HealthChecker interface {
+	Health(ctx context.Context) error
+}
+
+// OutputHealth is the health of a single output.
+type OutputHealth struct {
+	// Name identifies the output: "output" or "destination[i]", followed by its type.
+	Name string
+	// Err is nil if the output is healthy.
+	Err error
+}
+
+// HealthReport is the combined health of a logger's outputs. Outputs that do
+// not implement HealthChecker are not included.
+type HealthReport struct {
+	Outputs []OutputHealth
+}
+
+// Healthy reports whether every checked output is healthy.
+func (r HealthReport) Healthy() bool {
+	return r.Err() == nil
+}
+
+// Err returns an error describing the unhealthy outputs, or nil if all are healthy.
+func (r HealthReport) Err() error {
+	var failures []string
+	for _, output := range r.Outputs {
+		if output.Err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", output.Name, output.Err))
+		}
+	}
+	if len(failures) == 0 {
+		return nil
+	}
+	return fmt.Errorf("logging backend unreachable: %s", strings.Join(failures, "; "))
+}
+
+// Health checks the output and destinations of z that implement HealthChecker.
+func (z *Zap) Health(ctx context.Context) HealthReport {
+	var report HealthReport
+	check := func(name string, w io.Writer) {
+		if checker, ok := w.(HealthChecker); ok {
+			report.Outputs = append(report.Outputs, OutputHealth{
+				Name: fmt.Sprintf("%s (%T)", name, w),
+				Err:  checker.Health(ctx),
+			})
+		}
+	}
+	check("output", z.Config.Output)
+	for i, dest := range z.Config.Destinations {
+		check(fmt.Sprintf("destination[%d]", i), dest.Output)
+	}
+	return report
+}
+
+// HealthHandler returns an http.Handler for readiness probes that responds
+// 200 OK when the outputs of z are healthy and 503 Service Unavailable with
+// the failures otherwise.
+func HealthHandler(z *Zap) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := z.Health(r.Context()).Err(); err != nil {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+}
@@ -0,0 +1,66 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestZap_Health tests that the health report covers outputs that can be checked.
+func TestZap_Health(t *testing.T) {
+	online := true
+	writer := MustNATSWriter(NATSConfig{
+		Publisher: NATSPublishFunc(func(string, []byte) error {
+			if !online {
+				return errors.New("no servers available")
+			}
+			return nil
+		}),
+		Subject: "logs",
+	})
+	zapLogger := NewZap(Config{
+		Level:        InfoLevel,
+		Output:       new(bytes.Buffer),
+		Destinations: []Destination{{Output: writer}},
+	})
+
+	report := zapLogger.Health(context.Background())
+	if len(report.Outputs) != 1 || !report.Healthy() {
+		t.Errorf("Expected one healthy output, got %+v", report)
+	}
+
+	online = false
+	zapLogger.Info("Info message", nil)
+	err := zapLogger.Health(context.Background()).Err()
+	if err == nil || !strings.Contains(err.Error(), "destination[0] (*logger.NATSWriter): nats: publish: no servers available") {
+		t.Errorf("Expected the destination failure, got %v", err)
+	}
+}
+
+// TestHealthHandler tests the readiness probe responses.
+func TestHealthHandler(t *testing.T) {
+	uploader := &memoryUploader{}
+	archive := MustArchiveWriter(ArchiveConfig{Uploader: uploader})
+	zapLogger := NewZap(Config{Level: InfoLevel, Output: archive})
+	handler := HealthHandler(zapLogger)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ready", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", rec.Code)
+	}
+
+	uploader.err = errors.New("access denied")
+	zapLogger.Info("Info message", nil)
+	zapLogger.Sync()
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ready", nil))
+	if rec.Code != http.StatusServiceUnavailable || !strings.Contains(rec.Body.String(), "access denied") {
+		t.Errorf("Expected status 503 with the failure, got %d %s", rec.Code, rec.Body.String())
+	}
+}
@@ -0,0 +1,22 @@
+package logger
+
+import (
+	"fmt"
+)
+
+// Hook is called with every entry before it is written. A hook may modify the
+// entry's level, message, or fields. Hooks must be safe for concurrent use and
+// must not modify the Fields map in place, since it belongs to the caller;
+// assign a new map instead. The entry is reused once the hooks return, so hooks
+// must not retain the pointer. A returned error is reported but does not stop the
+// entry from being written.
+type Hook func(entry *Entry) error
+
+// runHooks runs hooks in registration order, passing their errors to report.
+func runHooks(hooks []Hook, entry *Entry, report func(error)) {
+	for _, hook := range hooks {
+		if err := hook(entry); err != nil {
+			report(fmt.Errorf("hook failed: %w", err))
+		}
+	}
+}
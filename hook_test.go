@@ -0,0 +1,64 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+// TestZap_AddHook tests that hooks can rewrite entries before they are written.
+func TestZap_AddHook(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	zapLogger := NewZap(Config{Level: InfoLevel, Output: buffer})
+
+	zapLogger.AddHook(func(entry *Entry) error {
+		fields := Fields{"hooked": true}
+		for k, v := range entry.Fields {
+			fields[k] = v
+		}
+		entry.Fields = fields
+		entry.Message = "[svc] " + entry.Message
+		return nil
+	})
+	zapLogger.AddHook(func(*Entry) error {
+		return errors.New("ignored")
+	})
+
+	zapLogger.Info("Info message", Fields{"key": "value"})
+	for _, expected := range []string{`"msg":"[svc] Info message"`, `"hooked":true`, `"key":"value"`} {
+		if !bytes.Contains(buffer.Bytes(), []byte(expected)) {
+			t.Errorf("Expected %s to contain %s", buffer.String(), expected)
+		}
+	}
+}
+
+// TestZap_HookDemotesLevel tests that entries demoted below the level are dropped.
+func TestZap_HookDemotesLevel(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	zapLogger := NewZap(Config{Level: InfoLevel, Output: buffer})
+	zapLogger.AddHook(func(entry *Entry) error {
+		entry.Level = DebugLevel
+		return nil
+	})
+
+	zapLogger.Info("Info message", nil)
+	if buffer.Len() != 0 {
+		t.Errorf("Expected demoted entry to be dropped, got %s", buffer.String())
+	}
+}
+
+// TestZap_ErrorOutput tests that hook and write failures are reported to ErrorOutput.
+func TestZap_ErrorOutput(t *testing.T) {
+	errOut := new(bytes.Buffer)
+	zapLogger := NewZap(Config{Level: InfoLevel, Output: failingWriter{}, ErrorOutput: errOut})
+	zapLogger.AddHook(func(*Entry) error {
+		return errors.New("broken hook")
+	})
+
+	zapLogger.Info("Info message", nil)
+	for _, expected := range []string{"logger: hook failed: broken hook", "write error"} {
+		if !bytes.Contains(errOut.Bytes(), []byte(expected)) {
+			t.Errorf("Expected %s to contain %s", errOut.String(), expected)
+		}
+	}
+}
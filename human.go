@@ -0,0 +1,53 @@
+package logger
+
+import (
+	"fmt"
+	"time"
+)
+
+// HumanFieldMode controls how fields built with DurationHuman and Bytes are written.
+type HumanFieldMode int
+
+const (
+	// HumanBoth writes the machine value under the key and the human-readable
+	// rendering under key + "_human".
+	HumanBoth HumanFieldMode = iota
+	// HumanMachineOnly writes only the machine value.
+	HumanMachineOnly
+	// HumanTextOnly writes only the human-readable rendering under the key.
+	HumanTextOnly
+)
+
+// humanDuration marks a duration to be written with a human-readable rendering.
+type humanDuration time.Duration
+
+// humanBytes marks a byte count to be written with a human-readable rendering.
+type humanBytes int64
+
+// DurationHuman returns a Field for a duration that is written both as a
+// machine value and as a human-readable string such as "1.5s".
+func DurationHuman(key string, d time.Duration) Field {
+	return Field{Key: key, Value: humanDuration(d)}
+}
+
+// Bytes returns a Field for a size in bytes that is written both as a number
+// and as a human-readable string such as "1.5 MiB".
+func Bytes(key string, n int64) Field {
+	return Field{Key: key, Value: humanBytes(n)}
+}
+
+// formatBytes renders n using binary units.
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit && n > -unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	value := float64(n)
+	units := []string{"KiB", "MiB", "GiB", "TiB", "PiB", "EiB"}
+	i := -1
+	for (value >= unit || value <= -unit) && i < len(units)-1 {
+		value /= unit
+		i++
+	}
+	return fmt.Sprintf("%.1f %s", value, units[i])
+}
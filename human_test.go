@@ -0,0 +1,57 @@
+package logger
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+// TestHumanFields tests that humanized fields are written in each mode.
+func TestHumanFields(t *testing.T) {
+	tests := []struct {
+		mode     HumanFieldMode
+		expected []string
+		absent   []string
+	}{
+		{HumanBoth, []string{`"latency":1.5`, `"latency_human":"1.5s"`, `"size":1572864`, `"size_human":"1.5 MiB"`}, nil},
+		{HumanMachineOnly, []string{`"latency":1.5`, `"size":1572864`}, []string{"_human"}},
+		{HumanTextOnly, []string{`"latency":"1.5s"`, `"size":"1.5 MiB"`}, []string{"_human"}},
+	}
+
+	for _, test := range tests {
+		buffer := new(bytes.Buffer)
+		zapLogger := NewZap(Config{Level: InfoLevel, Output: buffer, HumanFields: test.mode})
+		zapLogger.InfoFields("request served",
+			DurationHuman("latency", 1500*time.Millisecond),
+			Bytes("size", 1572864),
+		)
+
+		for _, expected := range test.expected {
+			if !bytes.Contains(buffer.Bytes(), []byte(expected)) {
+				t.Errorf("Mode %d: expected %s to contain %s", test.mode, buffer.String(), expected)
+			}
+		}
+		for _, absent := range test.absent {
+			if bytes.Contains(buffer.Bytes(), []byte(absent)) {
+				t.Errorf("Mode %d: expected %s not to contain %s", test.mode, buffer.String(), absent)
+			}
+		}
+	}
+}
+
+// TestFormatBytes tests the binary unit rendering.
+func TestFormatBytes(t *testing.T) {
+	tests := map[int64]string{
+		0:               "0 B",
+		1023:            "1023 B",
+		1024:            "1.0 KiB",
+		1536:            "1.5 KiB",
+		5 * 1024 * 1024: "5.0 MiB",
+		3 << 40:         "3.0 TiB",
+	}
+	for n, expected := range tests {
+		if actual := formatBytes(n); actual != expected {
+			t.Errorf("formatBytes(%d) = %s, expected %s", n, actual, expected)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package logger
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+)
+
+// KeyCase controls how field keys are normalized at emit time.
+type KeyCase int
+
+const (
+	// KeyAsIs writes keys unchanged.
+	KeyAsIs KeyCase = iota
+	// KeySnakeCase writes keys as lowercase snake_case, e.g. userID becomes user_id.
+	KeySnakeCase
+	// KeyCamelCase writes keys as camelCase, e.g. user_id becomes userId.
+	KeyCamelCase
+	// KeyLowerCase writes keys in lowercase without changing separators.
+	KeyLowerCase
+)
+
+// normalizeKeys rewrites the keys of zapFields in place according to the key case.
+func (c *fieldConverter) normalizeKeys(zapFields []zap.Field) []zap.Field {
+	if c.keyCase == KeyAsIs {
+		return zapFields
+	}
+	for i := range zapFields {
+		zapFields[i].Key = normalizeKey(zapFields[i].Key, c.keyCase)
+	}
+	return zapFields
+}
+
+// fixedKeyPrefix is the prefix of the Cloud Logging special keys, such as
+// those added by CloudTraceFields, which Cloud Logging only recognizes as is.
+const fixedKeyPrefix = "logging.googleapis.com/"
+
+// fixedKeys are the keys added by BuildInfo, named after the go command's
+// build settings.
+var fixedKeys = map[string]bool{"vcs.revision": true, "vcs.time": true, "vcs.modified": true}
+
+// normalizeKey returns key in the given case. Keys defined by external
+// systems are returned unchanged.
+func normalizeKey(key string, keyCase KeyCase) string {
+	if fixedKeys[key] || strings.HasPrefix(key, fixedKeyPrefix) {
+		return key
+	}
+	switch keyCase {
+	case KeySnakeCase:
+		return strings.Join(keyWords(key), "_")
+	case KeyCamelCase:
+		words := keyWords(key)
+		for i := 1; i < len(words); i++ {
+			r, size := utf8.DecodeRuneInString(words[i])
+			words[i] = string(unicode.ToUpper(r)) + words[i][size:]
+		}
+		return strings.Join(words, "")
+	case KeyLowerCase:
+		return strings.ToLower(key)
+	}
+	return key
+}
+
+// keyWords splits key into lowercase words at separators and case boundaries,
+// keeping acronyms together, so HTTPStatusCode yields http, status, code.
+func keyWords(key string) []string {
+	var words []string
+	var word []rune
+	runes := []rune(key)
+	flush := func() {
+		if len(word) > 0 {
+			words = append(words, strings.ToLower(string(word)))
+			word = word[:0]
+		}
+	}
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			flush()
+			continue
+		}
+		if unicode.IsUpper(r) && i > 0 {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				flush()
+			}
+		}
+		word = append(word, r)
+	}
+	flush()
+	if len(words) == 0 {
+		return []string{key}
+	}
+	return words
+}
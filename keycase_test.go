@@ -0,0 +1,71 @@
+package logger
+
+import (
+	"bytes"
+	"testing"
+)
+
+// TestNormalizeKey tests key conversion for each key case.
+func TestNormalizeKey(t *testing.T) {
+	tests := []struct {
+		key   string
+		snake string
+		camel string
+	}{
+		{"user_id", "user_id", "userId"},
+		{"userID", "user_id", "userId"},
+		{"UserName", "user_name", "userName"},
+		{"HTTPStatusCode", "http_status_code", "httpStatusCode"},
+		{"request-id", "request_id", "requestId"},
+		{"retry2Count", "retry2_count", "retry2Count"},
+		{"__", "__", "__"},
+		{"logging.googleapis.com/spanId", "logging.googleapis.com/spanId", "logging.googleapis.com/spanId"},
+		{"vcs.revision", "vcs.revision", "vcs.revision"},
+	}
+	for _, test := range tests {
+		if got := normalizeKey(test.key, KeySnakeCase); got != test.snake {
+			t.Errorf("Expected %q in snake case to be %q, got %q", test.key, test.snake, got)
+		}
+		if got := normalizeKey(test.key, KeyCamelCase); got != test.camel {
+			t.Errorf("Expected %q in camel case to be %q, got %q", test.key, test.camel, got)
+		}
+	}
+	if got := normalizeKey("User_ID", KeyLowerCase); got != "user_id" {
+		t.Errorf("Expected lowercase key user_id, got %q", got)
+	}
+}
+
+// TestKeyCase tests that keys are normalized when entries are written.
+func TestKeyCase(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	zapLogger := NewZap(Config{Level: InfoLevel, Output: buffer, KeyCase: KeySnakeCase})
+
+	zapLogger.InfoFields("Info message", String("userID", "42"), Int("RetryCount", 3))
+	for _, expected := range []string{`"user_id":"42"`, `"retry_count":3`} {
+		if !bytes.Contains(buffer.Bytes(), []byte(expected)) {
+			t.Errorf("Expected %s to contain %s", buffer.String(), expected)
+		}
+	}
+
+	if _, err := NewZapE(Config{Output: buffer, KeyCase: KeyLowerCase + 1}); err == nil {
+		t.Errorf("Expected an error for an invalid key case")
+	}
+}
+
+// TestKeyCase_CloudTrace tests that the Cloud Logging trace keys are kept as is.
+func TestKeyCase_CloudTrace(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	zapLogger := NewZap(Config{Level: InfoLevel, Output: buffer, KeyCase: KeySnakeCase})
+
+	zapLogger.With(CloudTraceFields("my-project", "105445aa7843bc8bf206b12000100000/1;o=1")).Info("Info message", Fields{"userID": "42"})
+	for _, expected := range []string{
+		`"logging.googleapis.com/trace":"projects/my-project/traces/105445aa7843bc8bf206b12000100000"`,
+		`"logging.googleapis.com/spanId":"1"`,
+		`"logging.googleapis.com/trace_sampled":true`,
+		`"user_id":"42"`,
+	} {
+		if !bytes.Contains(buffer.Bytes(), []byte(expected)) {
+			t.Errorf("Expected %s to contain %s", buffer.String(), expected)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package logger
+
+import (
+	"fmt"
+
+	"go.uber.org/zap"
+)
+
+// KeyNames overrides the keys the encoder uses for the core entry fields.
+// Empty names keep the defaults: ts, level, msg, caller, and function.
+type KeyNames struct {
+	Time     string
+	Level    string
+	Message  string
+	Caller   string
+	Function string
+}
+
+// withDefaults returns the key names with empty names set to their defaults.
+func (k KeyNames) withDefaults() KeyNames {
+	if k.Time == "" {
+		k.Time = "ts"
+	}
+	if k.Level == "" {
+		k.Level = "level"
+	}
+	if k.Message == "" {
+		k.Message = "msg"
+	}
+	if k.Caller == "" {
+		k.Caller = "caller"
+	}
+	if k.Function == "" {
+		k.Function = "function"
+	}
+	return k
+}
+
+// ReservedKeyPolicy controls what happens when a field key collides with one
+// of the core entry keys.
+type ReservedKeyPolicy int
+
+const (
+	// ReservedAllow writes colliding fields unchanged, producing duplicate keys.
+	ReservedAllow ReservedKeyPolicy = iota
+	// ReservedRename prefixes colliding keys with Config.ReservedPrefix.
+	ReservedRename
+	// ReservedDrop drops colliding fields.
+	ReservedDrop
+	// ReservedPanic panics on a colliding field; meant for development and tests.
+	ReservedPanic
+)
+
+// defaultReservedPrefix is the prefix used by ReservedRename when none is configured.
+const defaultReservedPrefix = "fields."
+
+// reservedKeys returns the set of core keys the encoder writes for config.
+func reservedKeys(config Config) map[string]struct{} {
+	names := config.KeyNames.withDefaults()
+	reserved := map[string]struct{}{
+		names.Time:    {},
+		names.Level:   {},
+		names.Message: {},
+	}
+	if !config.DisableCaller {
+		reserved[names.Caller] = struct{}{}
+		if config.CallerFunction {
+			reserved[names.Function] = struct{}{}
+		}
+	}
+	return reserved
+}
+
+// resolveReserved applies the reserved key policy to zapFields in place.
+func (c *fieldConverter) resolveReserved(zapFields []zap.Field) []zap.Field {
+	if c.reservedPolicy == ReservedAllow {
+		return zapFields
+	}
+
+	kept := zapFields[:0]
+	for _, f := range zapFields {
+		if _, ok := c.reserved[f.Key]; ok {
+			switch c.reservedPolicy {
+			case ReservedRename:
+				f.Key = c.reservedPrefix + f.Key
+			case ReservedDrop:
+				continue
+			case ReservedPanic:
+				panic(fmt.Sprintf("logger: field key %q collides with a reserved key", f.Key))
+			}
+		}
+		kept = append(kept, f)
+	}
+	for i := len(kept); i < len(zapFields); i++ {
+		zapFields[i] = zap.Field{}
+	}
+	return kept
+}
@@ -0,0 +1,62 @@
+package logger
+
+import (
+	"bytes"
+	"testing"
+)
+
+// TestKeyNames tests that the core entry keys can be renamed.
+func TestKeyNames(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	zapLogger := NewZap(Config{
+		Level:    InfoLevel,
+		Output:   buffer,
+		KeyNames: KeyNames{Time: "timestamp", Level: "severity", Message: "message"},
+	})
+
+	zapLogger.Info("Info message", nil)
+	for _, expected := range []string{`"timestamp":`, `"severity":"info"`, `"message":"Info message"`, `"caller":`} {
+		if !bytes.Contains(buffer.Bytes(), []byte(expected)) {
+			t.Errorf("Expected %s to contain %s", buffer.String(), expected)
+		}
+	}
+}
+
+// TestReservedKeys tests each reserved key policy.
+func TestReservedKeys(t *testing.T) {
+	tests := []struct {
+		policy   ReservedKeyPolicy
+		prefix   string
+		expected string
+		absent   string
+	}{
+		{ReservedAllow, "", `"msg":"Info message","msg":"user"`, ""},
+		{ReservedRename, "", `"fields.msg":"user"`, `"msg":"user"`},
+		{ReservedRename, "user_", `"user_level":"user"`, `"level":"user"`},
+		{ReservedDrop, "", `"other":"kept"`, `"user"`},
+	}
+	for _, test := range tests {
+		buffer := new(bytes.Buffer)
+		zapLogger := NewZap(Config{Level: InfoLevel, Output: buffer, ReservedKeys: test.policy, ReservedPrefix: test.prefix})
+
+		zapLogger.InfoFields("Info message", String("msg", "user"), String("level", "user"), String("other", "kept"))
+		if !bytes.Contains(buffer.Bytes(), []byte(test.expected)) {
+			t.Errorf("Expected %s to contain %s", buffer.String(), test.expected)
+		}
+		if test.absent != "" && bytes.Contains(buffer.Bytes(), []byte(test.absent)) {
+			t.Errorf("Expected %s not to contain %s", buffer.String(), test.absent)
+		}
+	}
+}
+
+// TestReservedKeys_Panic tests that ReservedPanic panics on a collision.
+func TestReservedKeys_Panic(t *testing.T) {
+	zapLogger := NewZap(Config{Level: InfoLevel, Output: new(bytes.Buffer), ReservedKeys: ReservedPanic})
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected a panic for a reserved key")
+		}
+	}()
+	zapLogger.Info("Info message", Fields{"ts": 1})
+}
@@ -0,0 +1,44 @@
+package logger
+
+import (
+	"context"
+	"os"
+	"sync/atomic"
+)
+
+// lambdaInvoked is set once the first Lambda invocation has started, so later
+// invocations in the same execution environment are not cold starts.
+var lambdaInvoked uint32
+
+// InLambda reports whether the process is running in AWS Lambda.
+func InLambda() bool {
+	return os.Getenv("AWS_LAMBDA_FUNCTION_NAME") != ""
+}
+
+// InvokeLambda runs fn for one Lambda invocation with a context and child of l
+// carrying aws_request_id, function_name, function_version, and cold_start
+// fields, and syncs l before returning so buffered entries are flushed before
+// the execution environment is frozen. awsRequestID is typically taken from
+// lambdacontext:
+//
+//	lc, _ := lambdacontext.FromContext(ctx)
+//	return logger.InvokeLambda(ctx, log, lc.AwsRequestID, func(ctx context.Context, log logger.Logger) error {
+//		...
+//	})
+func InvokeLambda(ctx context.Context, l Logger, awsRequestID string, fn func(ctx context.Context, l Logger) error) error {
+	child := With(l, Fields{
+		"aws_request_id":   awsRequestID,
+		"function_name":    os.Getenv("AWS_LAMBDA_FUNCTION_NAME"),
+		"function_version": os.Getenv("AWS_LAMBDA_FUNCTION_VERSION"),
+		"cold_start":       atomic.CompareAndSwapUint32(&lambdaInvoked, 0, 1),
+	})
+	defer syncLogger(l)
+	return fn(NewContext(ctx, child), child)
+}
+
+// syncLogger flushes l if it supports syncing.
+func syncLogger(l Logger) {
+	if s, ok := l.(interface{ Sync() error }); ok {
+		_ = s.Sync()
+	}
+}
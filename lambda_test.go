@@ -0,0 +1,44 @@
+package logger
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+)
+
+// TestInvokeLambda tests that invocations are logged with Lambda fields and a cold start flag.
+func TestInvokeLambda(t *testing.T) {
+	t.Setenv("AWS_LAMBDA_FUNCTION_NAME", "orders")
+	t.Setenv("AWS_LAMBDA_FUNCTION_VERSION", "7")
+	atomic.StoreUint32(&lambdaInvoked, 0)
+
+	if !InLambda() {
+		t.Errorf("Expected Lambda to be detected")
+	}
+
+	observed, logs := Observe(Config{Level: InfoLevel})
+	handler := func(ctx context.Context, l Logger) error {
+		FromContext(ctx).Info("handled", nil)
+		return nil
+	}
+	for _, id := range []string{"req-1", "req-2"} {
+		if err := InvokeLambda(context.Background(), observed, id, handler); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	entries := logs.All()
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(entries))
+	}
+	for i, expected := range []Fields{
+		{"aws_request_id": "req-1", "function_name": "orders", "function_version": "7", "cold_start": true},
+		{"aws_request_id": "req-2", "function_name": "orders", "function_version": "7", "cold_start": false},
+	} {
+		for k, v := range expected {
+			if entries[i].Fields[k] != v {
+				t.Errorf("Expected %s=%v in entry %d, got %v", k, v, i, entries[i].Fields[k])
+			}
+		}
+	}
+}
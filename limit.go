@@ -0,0 +1,100 @@
+package logger
+
+import (
+	"runtime"
+	"sync"
+	"time"
+)
+
+// callSite identifies a logging call by source location. Program counters are
+// not used because inlining can give one source line several of them.
+type callSite struct {
+	file string
+	line int
+}
+
+// callSites remembers when each call site last logged.
+type callSites struct {
+	mu   sync.Mutex
+	last map[callSite]time.Time
+}
+
+// allow reports whether site may log at now, given that it may log at most
+// once per interval; a zero interval allows it only once ever.
+func (c *callSites) allow(site callSite, now time.Time, interval time.Duration) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.last == nil {
+		c.last = make(map[callSite]time.Time)
+	}
+	last, seen := c.last[site]
+	if seen && (interval == 0 || now.Sub(last) < interval) {
+		return false
+	}
+	c.last[site] = now
+	return true
+}
+
+// limitedZap is a Zap that logs each call site only once, or at most once per interval.
+type limitedZap struct {
+	z        *Zap
+	sites    *callSites
+	interval time.Duration
+}
+
+// Once returns a Logger that writes the entry from any given call site only the
+// first time it is reached, e.g. l.Once().Warn("deprecated option", nil).
+func (z *Zap) Once() Logger {
+	return &limitedZap{z: z, sites: &z.state.once}
+}
+
+// Every returns a Logger that writes the entry from any given call site at most
+// once per interval d, for warnings inside tight loops.
+func (z *Zap) Every(d time.Duration) Logger {
+	return &limitedZap{z: z, sites: &z.state.every, interval: d}
+}
+
+// Info logs an info message with structured fields if the call site is allowed.
+func (l *limitedZap) Info(msg string, fields Fields) {
+	if l.allow(InfoLevel) {
+		l.z.log(InfoLevel, msg, fields, nil)
+	}
+}
+
+// Warn logs a warning message with structured fields if the call site is allowed.
+func (l *limitedZap) Warn(msg string, fields Fields) {
+	if l.allow(WarnLevel) {
+		l.z.log(WarnLevel, msg, fields, nil)
+	}
+}
+
+// Error logs an error message with structured fields if the call site is allowed.
+func (l *limitedZap) Error(msg string, fields Fields) {
+	if l.allow(ErrorLevel) {
+		l.z.log(ErrorLevel, msg, fields, nil)
+	}
+}
+
+// Fatal logs a fatal message with structured fields if the call site is allowed,
+// then applies Config.OnFatal.
+func (l *limitedZap) Fatal(msg string, fields Fields) {
+	if l.allow(FatalLevel) && l.z.log(FatalLevel, msg, fields, nil) {
+		l.z.afterFatal(msg, fields)
+	}
+}
+
+// allow reports whether the caller of the logging method may log. Disabled
+// levels, including those disabled by PackageLevels, are rejected first so
+// they do not consume the call site's allowance. It must be called directly
+// from the logging method, at the same depth as log, for callerLevel.
+func (l *limitedZap) allow(level Level) bool {
+	override, overridden := l.z.callerLevel()
+	if !l.z.levelEnabled(level, override, overridden) {
+		return false
+	}
+	_, file, line, ok := runtime.Caller(2)
+	if !ok {
+		return true
+	}
+	return l.sites.allow(callSite{file: file, line: line}, l.z.now(), l.interval)
+}
@@ -0,0 +1,54 @@
+package logger
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+// TestZap_Once tests that a call site logs only once.
+func TestZap_Once(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	zapLogger := NewZap(Config{Level: InfoLevel, Output: buffer})
+
+	for i := 0; i < 3; i++ {
+		zapLogger.Once().Warn("deprecated", nil)
+		zapLogger.Once().Debug("disabled", nil)
+	}
+	zapLogger.Once().Warn("deprecated", nil)
+
+	if n := bytes.Count(buffer.Bytes(), []byte("deprecated")); n != 2 {
+		t.Errorf("Expected 2 entries (one per call site), got %d", n)
+	}
+}
+
+// TestZap_Every tests that a call site logs at most once per interval.
+func TestZap_Every(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	clock := &manualClock{now: time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)}
+	zapLogger := NewZap(Config{Level: InfoLevel, Output: buffer, Clock: clock})
+
+	logInLoop := func() {
+		for i := 0; i < 5; i++ {
+			zapLogger.Every(time.Minute).Warn("queue full", nil)
+		}
+	}
+
+	logInLoop()
+	clock.now = clock.now.Add(30 * time.Second)
+	logInLoop()
+	clock.now = clock.now.Add(31 * time.Second)
+	logInLoop()
+
+	if n := bytes.Count(buffer.Bytes(), []byte("queue full")); n != 2 {
+		t.Errorf("Expected 2 entries, got %d: %s", n, buffer.String())
+	}
+}
+
+type manualClock struct {
+	now time.Time
+}
+
+func (c *manualClock) Now() time.Time {
+	return c.now
+}
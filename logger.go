@@ -1,17 +1,230 @@
 package logger
 
 import (
+	"context"
+	"fmt"
 	"io"
+	"os"
+	"strings"
+	"time"
 )
 
 // Config holds the configuration for the logger.
 type Config struct {
-	Level      Level
-	Output     io.Writer
-	ExitFunc   func(int)
+	Level Level
+	// PackageLevels overrides Level for entries logged from packages whose
+	// import path is or starts with a key followed by "/", e.g.
+	// "github.com/org/app/internal/db"; the longest matching key wins.
+	PackageLevels map[string]Level
+	// Output receives encoded entries; defaults to OutputPath, then os.Stderr,
+	// or os.Stdout when Stdout is set.
+	Output io.Writer
+	// Format selects the encoding of Output; defaults to FormatJSON.
+	Format Format
+	// CEF configures the header and keys of FormatCEF outputs.
+	CEF CEFConfig
+	// CSV declares the columns of FormatCSV outputs.
+	CSV CSVConfig
+	// Color colorizes the levels of FormatConsole output with ANSI escapes. On
+	// Windows, virtual terminal processing is enabled on the console, falling
+	// back to the console API on older versions; color is turned off when
+	// Output is a file or pipe that is not a console.
+	Color bool
+	// OutputPath is used when Output is nil: "stdout", "stderr", a Unix domain
+	// socket as unix:///path or unixgram:///path, or the path of a file opened
+	// for appending.
+	OutputPath string
+	// Stdout selects os.Stdout instead of os.Stderr when Output and OutputPath are unset.
+	Stdout bool
+	// Destinations are additional outputs, each with its own minimum level.
+	// When set, Output only defaults to stderr if OutputPath or Stdout asks for it.
+	Destinations []Destination
+	// EventOutput receives entries written with Event; defaults to Output.
+	EventOutput io.Writer
+	// ErrorOutput receives the logger's own operational errors, such as failed
+	// writes, encodes, and hooks, kept apart from the entries; defaults to
+	// os.Stderr.
+	ErrorOutput io.Writer
+	// OnError, if set, is called when a sink write or encode fails or a hook
+	// returns an error, in addition to the report on ErrorOutput, e.g. to count
+	// failures or fall back to another output. It must be safe for concurrent use and
+	// must not log through the same logger.
+	OnError func(error)
+	// Context, if set, owns the lifecycle of the logger: when it is canceled,
+	// the logger shuts down as with Shutdown, so outputs such as *AsyncWriter
+	// drain their queues and stop their goroutines, and a level window started
+	// with SetLevelFor ends without restoring the level.
+	Context  context.Context
+	ExitFunc func(int)
+	// OnFatal selects what happens after a fatal entry is written; defaults to FatalExit.
+	OnFatal FatalBehavior
+	// ExitCode is passed to ExitFunc after a fatal entry; defaults to 1.
+	ExitCode int
+	// ExitCodeFunc, when set, derives the exit code from the fatal entry's fields
+	// and takes precedence over ExitCode.
+	ExitCodeFunc func(Fields) int
+	// CrashReport writes a self-contained crash report file, with the most
+	// recent entries, the stack, build information, and a configuration
+	// snapshot, before a fatal entry exits; see also RecoverConfig.CrashReport.
+	CrashReport CrashReportConfig
+	// DisableCaller turns off capturing the calling file and line.
+	DisableCaller bool
+	// CallerSkip is the number of additional stack frames to skip when reporting
+	// the caller, for use when the logger is wrapped in helper functions.
+	CallerSkip int
+	// CallerEncoding selects how the caller path is rendered; defaults to CallerFull.
+	CallerEncoding CallerEncoding
+	// CallerRoot is the path prefix stripped by CallerTrimmed, e.g. the repository
+	// checkout directory. When empty, the main module path is used.
+	CallerRoot string
+	// CallerFunction records the calling function (e.g. github.com/org/app/db.(*Store).Get)
+	// under the "function" key.
+	CallerFunction bool
+	// TimeFormat selects the timestamp encoding: TimeFormatRFC3339 (default),
+	// TimeFormatRFC3339Nano, TimeFormatUnixMillis, or any time.Format layout.
+	TimeFormat string
+	// TimeZone converts timestamps to the given location; defaults to local time.
+	// It applies to time field values as well.
+	TimeZone *time.Location
+	// Sequence stamps each entry with a monotonically increasing "seq" number so
+	// consumers can detect lost entries.
+	Sequence bool
+	// EntryID stamps each entry with a unique, time-ordered ULID under "id".
+	EntryID bool
+	// BuildInfo adds the fields returned by BuildInfo, such as vcs.revision, to
+	// every entry, so each one is attributable to an exact build.
+	BuildInfo bool
+	// GoroutineID tags each entry with the ID of the logging goroutine under
+	// "goroutine", to tell interleaved concurrent logs apart. Reading the ID
+	// costs a stack trace per entry, so it is meant for debugging.
+	GoroutineID bool
+	// NearDeadline is how close to its deadline a context must be for the *Ctx
+	// methods to record the remaining time; defaults to one second.
+	NearDeadline time.Duration
+	// Clock supplies entry timestamps; defaults to the system clock. Tests and
+	// replay tooling can set it to produce deterministic output.
+	Clock Clock
+	// FlightRecorder keeps the last N debug entries that were suppressed by the
+	// level and writes them just before the next Error or Fatal entry, giving
+	// context for failures without always-on debug logging. Zero disables it.
+	FlightRecorder int
+	// HumanFields controls how DurationHuman and Bytes fields are written;
+	// defaults to HumanBoth.
+	HumanFields HumanFieldMode
+	// DurationFormat controls how time.Duration field values are written;
+	// defaults to DurationSeconds.
+	DurationFormat DurationFormat
+	// FieldTimeFormat is the layout of time.Time field values, accepting the
+	// same values as TimeFormat; defaults to TimeFormat.
+	FieldTimeFormat string
+	// BinaryEncoding controls how []byte field values are written; defaults to BinaryBase64.
+	BinaryEncoding BinaryEncoding
+	// MaxFieldBytes truncates string and byte field values longer than this many
+	// bytes and marks the entry with truncated=true. Zero disables truncation.
+	MaxFieldBytes int
+	// MaxMessageBytes truncates messages longer than this many bytes and marks
+	// the entry with truncated=true. Zero disables the limit.
+	MaxMessageBytes int
+	// MaxFields caps the number of fields per entry. Fields beyond the cap are
+	// dropped and their keys listed in a dropped_fields field; typed fields keep
+	// their order, map fields have none. Zero disables the limit.
+	MaxFields int
+	// SanitizeUTF8 replaces invalid UTF-8 in messages, keys, and string values
+	// with U+FFFD and escapes control characters, so no entry can break strict
+	// JSON consumers or terminal output.
+	SanitizeUTF8 bool
+	// Schema declares the expected type of fields, and which are required,
+	// and what happens to entries that violate it.
+	Schema Schema
+	// MessageTemplates renders placeholders such as {user_id} in messages from
+	// the entry's fields of the same key, which are still written as fields.
+	MessageTemplates bool
+	// MessageTemplateKey, if set, is the key under which the unrendered
+	// template of templated messages is written, for grouping similar entries.
+	MessageTemplateKey string
+	// KeyCase normalizes field keys at emit time; defaults to KeyAsIs.
+	KeyCase KeyCase
+	// KeyNames overrides the keys used for the time, level, message, caller,
+	// and function entries.
+	KeyNames KeyNames
+	// ReservedKeys controls fields whose keys collide with the core entry keys;
+	// defaults to ReservedAllow.
+	ReservedKeys ReservedKeyPolicy
+	// ReservedPrefix is prepended to colliding keys under ReservedRename;
+	// defaults to "fields.".
+	ReservedPrefix string
+	// LevelLabels overrides the level strings written to the output, e.g.
+	// WarnLevel: "WARNING". Levels without a label use the defaults.
+	LevelLabels map[Level]string
+	// UppercaseLevels writes the default level strings in uppercase.
+	UppercaseLevels bool
+	// Severity adds an RFC 5424 numeric severity to each entry, or writes it in
+	// place of the level string; defaults to SeverityOff.
+	Severity SeverityMode
+	// Pipeline, if set, runs custom stages such as enrichment, filtering, and
+	// routing on every entry after the hooks and before it is sampled, encoded,
+	// and written.
+	Pipeline *Pipeline
+	// Zap holds options specific to the zap backend, such as sampling.
+	Zap ZapOptions
+	// Deprecated: MoreConfig is ignored; use the typed options in Zap instead.
 	MoreConfig map[string]interface{}
 }
 
+// Destination is an output that only receives entries at or above Level. The
+// logger's own level still applies, so an entry is written to a destination
+// when it passes both.
+type Destination struct {
+	// Name identifies the destination for entries sent to it with Route.
+	Name   string
+	Output io.Writer
+	Level  Level
+	// LevelLabels overrides Config.LevelLabels for this destination.
+	LevelLabels map[Level]string
+	// Severities writes the level as the destination system's numeric severity,
+	// e.g. for syslog or Splunk conventions; it takes precedence over LevelLabels.
+	Severities map[Level]int
+	// Format selects the encoding for this destination; defaults to FormatJSON
+	// whatever Config.Format is.
+	Format Format
+}
+
+// FatalBehavior controls what the logger does after writing a fatal entry.
+type FatalBehavior int
+
+const (
+	// FatalExit calls Config.ExitFunc (os.Exit by default).
+	FatalExit FatalBehavior = iota
+	// FatalPanic panics with the log message.
+	FatalPanic
+	// FatalNone returns to the caller, leaving the decision to the application.
+	FatalNone
+)
+
+// Clock is a source of the current time.
+type Clock interface {
+	Now() time.Time
+}
+
+// Timestamp formats accepted by Config.TimeFormat in addition to custom layouts.
+const (
+	TimeFormatRFC3339     = time.RFC3339
+	TimeFormatRFC3339Nano = time.RFC3339Nano
+	TimeFormatUnixMillis  = "unixmillis"
+)
+
+// CallerEncoding controls how the caller's file path is written.
+type CallerEncoding int
+
+const (
+	// CallerFull writes the absolute path, e.g. /home/me/app/internal/db/db.go:42.
+	CallerFull CallerEncoding = iota
+	// CallerShort writes the package directory and file, e.g. db/db.go:42.
+	CallerShort
+	// CallerTrimmed writes the path relative to the module root, e.g. internal/db/db.go:42.
+	CallerTrimmed
+)
+
 // Level represents the severity of the log message.
 type Level int
 
@@ -26,6 +239,32 @@ const (
 	FatalLevel
 )
 
+// ParseLevel returns the level named by s: debug, info, warn (or warning),
+// error, or fatal, in any case.
+func ParseLevel(s string) (Level, error) {
+	switch strings.ToLower(s) {
+	case "debug":
+		return DebugLevel, nil
+	case "info":
+		return InfoLevel, nil
+	case "warn", "warning":
+		return WarnLevel, nil
+	case "error":
+		return ErrorLevel, nil
+	case "fatal":
+		return FatalLevel, nil
+	}
+	return 0, fmt.Errorf("unknown level %q", s)
+}
+
+// Entry is a single log entry as seen by observers.
+type Entry struct {
+	Level   Level
+	Time    time.Time
+	Message string
+	Fields  Fields
+}
+
 // Logger implements the behaviour of the logging methods
 type Logger interface {
 	Debug(msg string, fields Fields)
@@ -34,3 +273,145 @@ type Logger interface {
 	Error(msg string, fields Fields)
 	Fatal(msg string, fields Fields)
 }
+
+// LevelEnabler is implemented by loggers that can report whether a level is
+// enabled, so callers can skip building expensive fields for entries that
+// would be discarded.
+type LevelEnabler interface {
+	Enabled(level Level) bool
+	DebugEnabled() bool
+}
+
+// Enabled reports whether l logs entries at level. Loggers that do not
+// implement LevelEnabler are assumed to log every level.
+func Enabled(l Logger, level Level) bool {
+	if e, ok := l.(LevelEnabler); ok {
+		return e.Enabled(level)
+	}
+	return true
+}
+
+// errorOutput returns the writer for the logger's operational errors.
+func errorOutput(config Config) io.Writer {
+	if config.ErrorOutput != nil {
+		return config.ErrorOutput
+	}
+	return os.Stderr
+}
+
+// exitCode returns the exit code used after a fatal entry with the given fields.
+func exitCode(config Config, fields Fields) int {
+	if config.ExitCodeFunc != nil {
+		return config.ExitCodeFunc(fields)
+	}
+	if config.ExitCode != 0 {
+		return config.ExitCode
+	}
+	return 1
+}
+
+// openOutput returns the writer described by config.
+func openOutput(config Config) (io.Writer, error) {
+	if config.Output != nil {
+		return config.Output, nil
+	}
+	switch config.OutputPath {
+	case "":
+		if config.Stdout {
+			return os.Stdout, nil
+		}
+		if len(config.Destinations) > 0 {
+			return nil, nil
+		}
+		return os.Stderr, nil
+	case "stdout":
+		return os.Stdout, nil
+	case "stderr":
+		return os.Stderr, nil
+	}
+	if isUnixAddress(config.OutputPath) {
+		return NewUnixWriter(config.OutputPath)
+	}
+
+	file, err := os.OpenFile(config.OutputPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("open output %q: %w", config.OutputPath, err)
+	}
+	return file, nil
+}
+
+// validateConfig reports settings that are out of range.
+func validateConfig(config Config) error {
+	if config.Level < DebugLevel || config.Level > FatalLevel {
+		return fmt.Errorf("invalid level %d", config.Level)
+	}
+	if err := config.Zap.validate(); err != nil {
+		return err
+	}
+	for prefix, level := range config.PackageLevels {
+		if level < DebugLevel || level > FatalLevel {
+			return fmt.Errorf("invalid level %d for package %q", level, prefix)
+		}
+	}
+	if config.OnFatal < FatalExit || config.OnFatal > FatalNone {
+		return fmt.Errorf("invalid fatal behavior %d", config.OnFatal)
+	}
+	if config.CallerEncoding < CallerFull || config.CallerEncoding > CallerTrimmed {
+		return fmt.Errorf("invalid caller encoding %d", config.CallerEncoding)
+	}
+	if config.KeyCase < KeyAsIs || config.KeyCase > KeyLowerCase {
+		return fmt.Errorf("invalid key case %d", config.KeyCase)
+	}
+	if config.ReservedKeys < ReservedAllow || config.ReservedKeys > ReservedPanic {
+		return fmt.Errorf("invalid reserved key policy %d", config.ReservedKeys)
+	}
+	if config.DurationFormat < DurationSeconds || config.DurationFormat > DurationString {
+		return fmt.Errorf("invalid duration format %d", config.DurationFormat)
+	}
+	names := make(map[string]bool, len(config.Destinations))
+	for _, dest := range config.Destinations {
+		if dest.Name != "" && names[dest.Name] {
+			return fmt.Errorf("duplicate destination name %q", dest.Name)
+		}
+		names[dest.Name] = true
+	}
+	if err := config.Schema.validate(); err != nil {
+		return err
+	}
+	if !config.Format.valid() {
+		return fmt.Errorf("invalid format %d", config.Format)
+	}
+	if config.Severity < SeverityOff || config.Severity > SeverityOnly {
+		return fmt.Errorf("invalid severity mode %d", config.Severity)
+	}
+	if config.CallerSkip < 0 {
+		return fmt.Errorf("invalid caller skip %d", config.CallerSkip)
+	}
+	for i, dest := range config.Destinations {
+		if dest.Output == nil {
+			return fmt.Errorf("destination %d has no output", i)
+		}
+		if !dest.Format.valid() {
+			return fmt.Errorf("destination %d has invalid format %d", i, dest.Format)
+		}
+	}
+	if usesFormat(config, FormatCEF) && (config.CEF.Vendor == "" || config.CEF.Product == "") {
+		return fmt.Errorf("cef: vendor and product are required")
+	}
+	if usesFormat(config, FormatCSV) && len(config.CSV.Columns) == 0 {
+		return fmt.Errorf("csv: columns are required")
+	}
+	if config.MaxFieldBytes < 0 {
+		return fmt.Errorf("invalid max field bytes %d", config.MaxFieldBytes)
+	}
+	if config.MaxMessageBytes < 0 {
+		return fmt.Errorf("invalid max message bytes %d", config.MaxMessageBytes)
+	}
+	if config.MaxFields < 0 {
+		return fmt.Errorf("invalid max fields %d", config.MaxFields)
+	}
+	if config.FlightRecorder < 0 {
+		return fmt.Errorf("invalid flight recorder size %d", config.FlightRecorder)
+	}
+	return nil
+}
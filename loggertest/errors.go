@@ -0,0 +1,73 @@
+package loggertest
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ralonr/logger"
+)
+
+// Allow reports whether an Error or Fatal entry is expected by the test.
+type Allow func(entry logger.Entry) bool
+
+// AllowMessage allows entries whose message contains snippet.
+func AllowMessage(snippet string) Allow {
+	return func(entry logger.Entry) bool {
+		return strings.Contains(entry.Message, snippet)
+	}
+}
+
+// AllowField allows entries that have a field named key equal to value.
+func AllowField(key string, value interface{}) Allow {
+	return func(entry logger.Entry) bool {
+		v, ok := entry.Fields[key]
+		return ok && reflect.DeepEqual(v, value)
+	}
+}
+
+// FailOnErrors registers a hook on l and a cleanup on t that fails the test if
+// l logged entries at Error or above that no allow function matches, catching
+// errors that the code under test logs and swallows:
+//
+//	log := logger.NewZap(logger.Config{Level: logger.InfoLevel, Output: io.Discard})
+//	loggertest.FailOnErrors(t, log, loggertest.AllowMessage("retrying"))
+//
+// The hook sees entries after the hooks registered before it.
+func FailOnErrors(t testing.TB, l *logger.Zap, allow ...Allow) {
+	t.Helper()
+
+	var mu sync.Mutex
+	var unexpected []logger.Entry
+	l.AddHook(func(entry *logger.Entry) error {
+		if entry.Level < logger.ErrorLevel {
+			return nil
+		}
+		for _, a := range allow {
+			if a(*entry) {
+				return nil
+			}
+		}
+		mu.Lock()
+		unexpected = append(unexpected, *entry)
+		mu.Unlock()
+		return nil
+	})
+
+	t.Cleanup(func() {
+		mu.Lock()
+		defer mu.Unlock()
+		for _, entry := range unexpected {
+			t.Errorf("loggertest: unexpected %s entry %q with fields %v", levelName(entry.Level), entry.Message, entry.Fields)
+		}
+	})
+}
+
+// levelName returns the lowercase name of an Error or Fatal level.
+func levelName(level logger.Level) string {
+	if level == logger.FatalLevel {
+		return "fatal"
+	}
+	return "error"
+}
@@ -0,0 +1,64 @@
+package loggertest
+
+import (
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/ralonr/logger"
+)
+
+// recordingT is a testing.TB that records failures and cleanups instead of running them.
+type recordingT struct {
+	testing.TB
+	cleanups []func()
+	errors   []string
+}
+
+func (t *recordingT) Helper() {}
+
+func (t *recordingT) Cleanup(f func()) {
+	t.cleanups = append(t.cleanups, f)
+}
+
+func (t *recordingT) Errorf(format string, args ...interface{}) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+// TestFailOnErrors tests that unexpected error entries fail the test at cleanup.
+func TestFailOnErrors(t *testing.T) {
+	rt := &recordingT{TB: t}
+	log := logger.NewZap(logger.Config{Level: logger.InfoLevel, Output: io.Discard, OnFatal: logger.FatalNone})
+	FailOnErrors(rt, log, AllowMessage("retrying"), AllowField("expected", true))
+
+	log.Warn("slow query", nil)
+	log.Error("retrying request", nil)
+	log.Error("cache miss", logger.Fields{"expected": true})
+	log.Error("payment failed", logger.Fields{"order_id": 42})
+	log.Fatal("shutting down", nil)
+
+	if len(rt.errors) != 0 {
+		t.Fatalf("Expected no failures before cleanup, got %v", rt.errors)
+	}
+	for _, cleanup := range rt.cleanups {
+		cleanup()
+	}
+	expected := []string{
+		`loggertest: unexpected error entry "payment failed" with fields map[order_id:42]`,
+		`loggertest: unexpected fatal entry "shutting down" with fields map[]`,
+	}
+	if fmt.Sprint(rt.errors) != fmt.Sprint(expected) {
+		t.Errorf("Expected %v, got %v", expected, rt.errors)
+	}
+}
+
+// TestAllowField_Uncomparable tests that fields holding slices or maps are compared by value.
+func TestAllowField_Uncomparable(t *testing.T) {
+	allow := AllowField("codes", []int{1, 2})
+	if !allow(logger.Entry{Fields: logger.Fields{"codes": []int{1, 2}}}) {
+		t.Errorf("Expected equal slices to match")
+	}
+	if allow(logger.Entry{Fields: logger.Fields{"codes": []int{3}}}) {
+		t.Errorf("Expected different slices not to match")
+	}
+}
@@ -0,0 +1,89 @@
+// Package loggertest provides helpers for testing code that logs with the logger package.
+package loggertest
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// updateFlag is the name of the flag that rewrites golden files.
+const updateFlag = "update"
+
+// volatileKeys are replaced by placeholders before comparing entries because
+// they change from run to run or machine to machine.
+var volatileKeys = []string{"ts", "caller", "function", "id"}
+
+func init() {
+	if flag.Lookup(updateFlag) == nil {
+		flag.Bool(updateFlag, false, "update golden files")
+	}
+}
+
+// AssertGolden normalizes the JSON entries in got and compares them against
+// testdata/<name>.golden. Running the tests with -update rewrites the file.
+func AssertGolden(t testing.TB, name string, got []byte) {
+	t.Helper()
+
+	normalized, err := Normalize(got)
+	if err != nil {
+		t.Fatalf("loggertest: normalize entries: %v", err)
+	}
+
+	path := filepath.Join("testdata", name+".golden")
+	if f := flag.Lookup(updateFlag); f != nil && f.Value.String() == "true" {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("loggertest: create testdata: %v", err)
+		}
+		if err := os.WriteFile(path, normalized, 0o644); err != nil {
+			t.Fatalf("loggertest: update golden file: %v", err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("loggertest: read golden file (run with -update to create it): %v", err)
+	}
+	if !bytes.Equal(normalized, want) {
+		t.Errorf("loggertest: entries do not match %s\ngot:\n%s\nwant:\n%s", path, normalized, want)
+	}
+}
+
+// Normalize rewrites newline-delimited JSON entries so they can be compared
+// across runs: volatile values such as timestamps and callers are replaced by
+// placeholders and keys are sorted.
+func Normalize(entries []byte) ([]byte, error) {
+	var out bytes.Buffer
+	encoder := json.NewEncoder(&out)
+	encoder.SetEscapeHTML(false)
+
+	scanner := bufio.NewScanner(bytes.NewReader(entries))
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		var entry map[string]interface{}
+		if err := json.Unmarshal(line, &entry); err != nil {
+			return nil, err
+		}
+		for _, key := range volatileKeys {
+			if _, ok := entry[key]; ok {
+				entry[key] = "<" + key + ">"
+			}
+		}
+
+		if err := encoder.Encode(entry); err != nil {
+			return nil, err
+		}
+	}
+	return out.Bytes(), scanner.Err()
+}
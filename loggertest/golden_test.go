@@ -0,0 +1,38 @@
+package loggertest
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ralonr/logger"
+)
+
+// TestAssertGolden tests that emitted entries match the golden file.
+func TestAssertGolden(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	log := logger.NewZap(logger.Config{
+		Level:    logger.InfoLevel,
+		Output:   buffer,
+		Sequence: true,
+		EntryID:  true,
+	})
+
+	log.Info("user created", logger.Fields{"user": "alice", "admin": false})
+	log.Warn("quota nearly exhausted", logger.Fields{"used": 95})
+
+	AssertGolden(t, "basic", buffer.Bytes())
+}
+
+// TestNormalize tests that volatile values are replaced and keys are sorted.
+func TestNormalize(t *testing.T) {
+	input := []byte(`{"level":"info","ts":"2024-03-01T12:00:00Z","caller":"a.go:1","msg":"m","b":1,"a":2}` + "\n")
+	expected := `{"a":2,"b":1,"caller":"<caller>","level":"info","msg":"m","ts":"<ts>"}` + "\n"
+
+	normalized, err := Normalize(input)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if string(normalized) != expected {
+		t.Errorf("Expected %s, got %s", expected, normalized)
+	}
+}
@@ -0,0 +1,116 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+// encoderBuffers pools the buffers of the encoders built on mapCore.
+var encoderBuffers = buffer.NewPool()
+
+// renderFunc writes an entry and its fields, collected into a map, to buf.
+type renderFunc func(buf *buffer.Buffer, entry zapcore.Entry, fields map[string]interface{}) error
+
+// mapCore is a zapcore.Core for encodings that are simpler to produce from a
+// map of fields than through zapcore.Encoder, such as CEF. Context fields are
+// kept as zap fields and replayed, so namespaces nest as they do in JSON.
+type mapCore struct {
+	zapcore.LevelEnabler
+	render  renderFunc
+	out     zapcore.WriteSyncer
+	context []zapcore.Field
+}
+
+// newMapCore returns a core that writes entries rendered by render to out.
+func newMapCore(render renderFunc, out zapcore.WriteSyncer, enabler zapcore.LevelEnabler) zapcore.Core {
+	return &mapCore{LevelEnabler: enabler, render: render, out: out}
+}
+
+// With returns a child core that adds fields to every entry.
+func (c *mapCore) With(fields []zapcore.Field) zapcore.Core {
+	context := make([]zapcore.Field, 0, len(c.context)+len(fields))
+	context = append(context, c.context...)
+	context = append(context, fields...)
+	return &mapCore{LevelEnabler: c.LevelEnabler, render: c.render, out: c.out, context: context}
+}
+
+// Check adds c to the checked entry if the level is enabled.
+func (c *mapCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+// Write renders the entry and writes it, syncing after entries above error.
+func (c *mapCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	enc := zapcore.NewMapObjectEncoder()
+	for _, f := range c.context {
+		f.AddTo(enc)
+	}
+	for _, f := range fields {
+		f.AddTo(enc)
+	}
+
+	buf := encoderBuffers.Get()
+	defer buf.Free()
+	if err := c.render(buf, entry, enc.Fields); err != nil {
+		return err
+	}
+	if _, err := c.out.Write(buf.Bytes()); err != nil {
+		return err
+	}
+	if entry.Level > zapcore.ErrorLevel {
+		return c.out.Sync()
+	}
+	return nil
+}
+
+// Sync flushes the output.
+func (c *mapCore) Sync() error {
+	return c.out.Sync()
+}
+
+// sortedKeys returns the keys of fields in order.
+func sortedKeys(fields map[string]interface{}) []string {
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// formatValue renders a collected field value as text: strings as they are,
+// maps and slices as JSON, and anything else with fmt.
+func formatValue(v interface{}) string {
+	switch val := v.(type) {
+	case string:
+		return val
+	case map[string]interface{}, []interface{}:
+		b, err := json.Marshal(val)
+		if err != nil {
+			return fmt.Sprint(val)
+		}
+		return string(b)
+	}
+	return fmt.Sprint(v)
+}
+
+// encodePrimitive returns the value that encode appends, letting encoders built
+// on mapCore reuse the configured time and level encoders.
+func encodePrimitive(encode func(zapcore.PrimitiveArrayEncoder)) interface{} {
+	enc := zapcore.NewMapObjectEncoder()
+	enc.AddArray("v", zapcore.ArrayMarshalerFunc(func(arr zapcore.ArrayEncoder) error {
+		encode(arr)
+		return nil
+	}))
+	if values, ok := enc.Fields["v"].([]interface{}); ok && len(values) > 0 {
+		return values[0]
+	}
+	return nil
+}
@@ -0,0 +1,241 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// metricName matches valid Prometheus metric names.
+var metricName = regexp.MustCompile(`^[a-zA-Z_:][a-zA-Z0-9_:]*$`)
+
+// DefaultBuckets are the histogram buckets used when none are given, matching
+// the Prometheus client defaults.
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// Metrics derives counters and histograms from log entries and exposes them in
+// the Prometheus text format, so that events already logged need not be
+// instrumented twice. Register metrics, add Hook to a logger, and serve the
+// Metrics as an http.Handler on the scrape endpoint.
+type Metrics struct {
+	mu         sync.Mutex
+	names      map[string]bool
+	counters   []*logCounter
+	histograms []*logHistogram
+}
+
+// logCounter counts entries matching its fields.
+type logCounter struct {
+	name  string
+	help  string
+	match Fields
+	count uint64
+}
+
+// logHistogram observes a numeric field of entries matching its fields.
+type logHistogram struct {
+	name    string
+	help    string
+	field   string
+	match   Fields
+	buckets []float64
+	counts  []uint64
+	sum     float64
+	count   uint64
+}
+
+// NewMetrics returns a new *Metrics with no registered metrics.
+func NewMetrics() *Metrics {
+	return &Metrics{names: make(map[string]bool)}
+}
+
+// Counter registers a counter of the entries whose fields include match, e.g.
+// Fields{"event": "cache_miss"}; a nil match counts every entry.
+func (m *Metrics) Counter(name, help string, match Fields) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.register(name); err != nil {
+		return err
+	}
+	m.counters = append(m.counters, &logCounter{name: name, help: help, match: match})
+	return nil
+}
+
+// Histogram registers a histogram of the numeric field of entries whose fields
+// include match, e.g. the latency_ms field. Entries without a numeric field are
+// not observed. Nil buckets default to DefaultBuckets.
+func (m *Metrics) Histogram(name, help, field string, buckets []float64, match Fields) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.register(name); err != nil {
+		return err
+	}
+	if buckets == nil {
+		buckets = DefaultBuckets
+	}
+	buckets = append([]float64(nil), buckets...)
+	sort.Float64s(buckets)
+	m.histograms = append(m.histograms, &logHistogram{
+		name:    name,
+		help:    help,
+		field:   field,
+		match:   match,
+		buckets: buckets,
+		counts:  make([]uint64, len(buckets)),
+	})
+	return nil
+}
+
+// register reserves name, or returns an error if it is invalid or taken.
+func (m *Metrics) register(name string) error {
+	if !metricName.MatchString(name) {
+		return fmt.Errorf("metrics: invalid metric name %q", name)
+	}
+	if m.names[name] {
+		return fmt.Errorf("metrics: metric %q already registered", name)
+	}
+	m.names[name] = true
+	return nil
+}
+
+// Hook returns a Hook that updates the registered metrics from each entry.
+func (m *Metrics) Hook() Hook {
+	return func(entry *Entry) error {
+		m.observe(entry)
+		return nil
+	}
+}
+
+// observe updates the metrics matching entry.
+func (m *Metrics) observe(entry *Entry) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, c := range m.counters {
+		if fieldsMatch(entry.Fields, c.match) {
+			c.count++
+		}
+	}
+	for _, h := range m.histograms {
+		if !fieldsMatch(entry.Fields, h.match) {
+			continue
+		}
+		v, ok := metricValue(entry.Fields[h.field])
+		if !ok {
+			continue
+		}
+		for i, bound := range h.buckets {
+			if v <= bound {
+				h.counts[i]++
+			}
+		}
+		h.sum += v
+		h.count++
+	}
+}
+
+// metricValue converts a field value to a float64.
+func metricValue(v interface{}) (float64, bool) {
+	switch val := v.(type) {
+	case int:
+		return float64(val), true
+	case int8:
+		return float64(val), true
+	case int16:
+		return float64(val), true
+	case int32:
+		return float64(val), true
+	case int64:
+		return float64(val), true
+	case uint:
+		return float64(val), true
+	case uint8:
+		return float64(val), true
+	case uint16:
+		return float64(val), true
+	case uint32:
+		return float64(val), true
+	case uint64:
+		return float64(val), true
+	case float32:
+		return float64(val), true
+	case float64:
+		return val, true
+	case json.Number:
+		f, err := val.Float64()
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
+
+// WriteTo writes the metrics to w in the Prometheus text format.
+func (m *Metrics) WriteTo(w io.Writer) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	cw := &countingWriter{w: w}
+	for _, c := range m.counters {
+		writeMetricHeader(cw, c.name, c.help, "counter")
+		fmt.Fprintf(cw, "%s %d\n", c.name, c.count)
+	}
+	for _, h := range m.histograms {
+		writeMetricHeader(cw, h.name, h.help, "histogram")
+		for i, bound := range h.buckets {
+			fmt.Fprintf(cw, "%s_bucket{le=%q} %d\n", h.name, formatMetricFloat(bound), h.counts[i])
+		}
+		fmt.Fprintf(cw, "%s_bucket{le=\"+Inf\"} %d\n", h.name, h.count)
+		fmt.Fprintf(cw, "%s_sum %s\n", h.name, formatMetricFloat(h.sum))
+		fmt.Fprintf(cw, "%s_count %d\n", h.name, h.count)
+	}
+	return cw.n, cw.err
+}
+
+// ServeHTTP serves the metrics in the Prometheus text format.
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	m.WriteTo(w)
+}
+
+// helpEscaper escapes HELP text as required by the text format.
+var helpEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+
+// writeMetricHeader writes the HELP and TYPE lines of a metric.
+func writeMetricHeader(w io.Writer, name, help, typ string) {
+	if help != "" {
+		fmt.Fprintf(w, "# HELP %s %s\n", name, helpEscaper.Replace(help))
+	}
+	fmt.Fprintf(w, "# TYPE %s %s\n", name, typ)
+}
+
+// formatMetricFloat formats v as a Prometheus sample value.
+func formatMetricFloat(v float64) string {
+	if math.IsInf(v, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// countingWriter counts the bytes written and keeps the first error.
+type countingWriter struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+// Write writes p unless an earlier write failed.
+func (c *countingWriter) Write(p []byte) (int, error) {
+	if c.err != nil {
+		return 0, c.err
+	}
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	c.err = err
+	return n, err
+}
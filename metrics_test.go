@@ -0,0 +1,53 @@
+package logger
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestMetrics tests that counters and histograms are derived from logged entries.
+func TestMetrics(t *testing.T) {
+	metrics := NewMetrics()
+	if err := metrics.Counter("cache_misses_total", "Cache misses.", Fields{"event": "cache_miss"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := metrics.Histogram("request_latency_ms", "Request latency.", "latency_ms", []float64{10, 100}, nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := metrics.Counter("cache_misses_total", "", nil); err == nil {
+		t.Errorf("Expected an error for a duplicate metric")
+	}
+	if err := metrics.Counter("cache-misses", "", nil); err == nil {
+		t.Errorf("Expected an error for an invalid metric name")
+	}
+
+	zapLogger := NewZap(Config{Level: InfoLevel, Output: new(bytes.Buffer)})
+	zapLogger.AddHook(metrics.Hook())
+	zapLogger.Info("Cache miss", Fields{"event": "cache_miss"})
+	zapLogger.Info("Cache miss", Fields{"event": "cache_miss", "latency_ms": 5})
+	zapLogger.Info("Request", Fields{"latency_ms": 50.5})
+	zapLogger.Info("Request", Fields{"latency_ms": 500})
+	zapLogger.Info("Request", Fields{"latency_ms": "fast"})
+
+	recorder := httptest.NewRecorder()
+	metrics.ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+	expected := `# HELP cache_misses_total Cache misses.
+# TYPE cache_misses_total counter
+cache_misses_total 2
+# HELP request_latency_ms Request latency.
+# TYPE request_latency_ms histogram
+request_latency_ms_bucket{le="10"} 1
+request_latency_ms_bucket{le="100"} 2
+request_latency_ms_bucket{le="+Inf"} 3
+request_latency_ms_sum 555.5
+request_latency_ms_count 3
+`
+	if recorder.Body.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, recorder.Body.String())
+	}
+	if !strings.HasPrefix(recorder.Header().Get("Content-Type"), "text/plain") {
+		t.Errorf("Expected a text content type, got %q", recorder.Header().Get("Content-Type"))
+	}
+}
@@ -0,0 +1,219 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// MQTTPublisher publishes a message to an MQTT broker. Clients are adapted
+// with MQTTPublishFunc, e.g. for paho.mqtt.golang:
+//
+//	logger.MQTTPublishFunc(func(topic string, qos byte, retained bool, payload []byte) error {
+//		token := client.Publish(topic, qos, retained, payload)
+//		token.Wait()
+//		return token.Error()
+//	})
+type MQTTPublisher interface {
+	Publish(topic string, qos byte, retained bool, payload []byte) error
+}
+
+// MQTTPublishFunc adapts a function to an MQTTPublisher.
+type MQTTPublishFunc func(topic string, qos byte, retained bool, payload []byte) error
+
+// Publish calls f(topic, qos, retained, payload).
+func (f MQTTPublishFunc) Publish(topic string, qos byte, retained bool, payload []byte) error {
+	return f(topic, qos, retained, payload)
+}
+
+// MQTTConfig holds the configuration for the MQTT writer.
+type MQTTConfig struct {
+	// Publisher sends the entries; reconnecting is left to the client.
+	Publisher MQTTPublisher
+	// Topic is the topic entries are published to.
+	Topic string
+	// QoS is the MQTT quality of service level: 0, 1, or 2.
+	QoS byte
+	// Retained asks the broker to keep the last entry for new subscribers.
+	Retained bool
+	// BufferSize is the number of entries kept while the broker is unreachable;
+	// the oldest are dropped first. Defaults to 1000.
+	BufferSize int
+	// RetryInterval is how often the background goroutine retries publishing
+	// while the broker is unreachable; defaults to one second.
+	RetryInterval time.Duration
+}
+
+// MQTTWriter is an output that publishes each JSON entry written by Zap to an
+// MQTT topic. Write only buffers the entry; a background goroutine publishes
+// the buffer in order, retrying until publishing succeeds again, so logging
+// never waits on the broker and devices keep their logs across short
+// disconnections. Close stops the goroutine.
+type MQTTWriter struct {
+	config  MQTTConfig
+	mu      sync.Mutex
+	pending [][]byte
+	dropped int
+	// shifted counts the entries removed from the front of pending, so that a
+	// flush can tell whether an overflow dropped the entry it published.
+	shifted uint64
+	lastErr error
+	closed  bool
+	// publishing serializes flushes so that entries are published in order.
+	publishing sync.Mutex
+	wake       chan struct{}
+	stop       chan struct{}
+	stopped    chan struct{}
+}
+
+// NewMQTTWriterE returns a new *MQTTWriter, or an error if the publisher or
+// topic is missing or the QoS is out of range.
+func NewMQTTWriterE(config MQTTConfig) (*MQTTWriter, error) {
+	if config.Publisher == nil {
+		return nil, errors.New("mqtt: publisher is not configured")
+	}
+	if config.Topic == "" {
+		return nil, errors.New("mqtt: topic is not configured")
+	}
+	if config.QoS > 2 {
+		return nil, fmt.Errorf("mqtt: invalid qos %d", config.QoS)
+	}
+	if config.BufferSize <= 0 {
+		config.BufferSize = 1000
+	}
+	if config.RetryInterval <= 0 {
+		config.RetryInterval = time.Second
+	}
+	w := &MQTTWriter{
+		config:  config,
+		wake:    make(chan struct{}, 1),
+		stop:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	go w.run()
+	return w, nil
+}
+
+// MustMQTTWriter is like NewMQTTWriterE but panics on error.
+func MustMQTTWriter(config MQTTConfig) *MQTTWriter {
+	w, err := NewMQTTWriterE(config)
+	if err != nil {
+		panic(err)
+	}
+	return w
+}
+
+// Write buffers a single JSON entry for the background goroutine to publish.
+// It only fails when the buffer overflows or the writer is closed.
+func (w *MQTTWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return 0, errors.New("mqtt: writer is closed")
+	}
+	w.pending = append(w.pending, append([]byte(nil), p...))
+	if overflow := len(w.pending) - w.config.BufferSize; overflow > 0 {
+		w.pending = w.pending[overflow:]
+		w.dropped += overflow
+		w.shifted += uint64(overflow)
+	}
+	dropped := w.dropped
+	w.dropped = 0
+	w.mu.Unlock()
+
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+	if dropped > 0 {
+		return len(p), fmt.Errorf("mqtt: buffer full, dropped %d entries", dropped)
+	}
+	return len(p), nil
+}
+
+// Sync publishes any buffered entries.
+func (w *MQTTWriter) Sync() error {
+	return w.flush()
+}
+
+// Close stops the background goroutine and publishes the remaining entries.
+// Later writes fail.
+func (w *MQTTWriter) Close() error {
+	w.mu.Lock()
+	if !w.closed {
+		w.closed = true
+		close(w.stop)
+	}
+	w.mu.Unlock()
+	<-w.stopped
+	return w.flush()
+}
+
+// Buffered returns the number of entries waiting to be published.
+func (w *MQTTWriter) Buffered() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return len(w.pending)
+}
+
+// run publishes the buffer whenever an entry is written, and retries every
+// RetryInterval while entries are left, until Close is called.
+func (w *MQTTWriter) run() {
+	defer close(w.stopped)
+	ticker := time.NewTicker(w.config.RetryInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-w.wake:
+		case <-ticker.C:
+		case <-w.stop:
+			return
+		}
+		_ = w.flush()
+	}
+}
+
+// flush publishes pending entries in order, stopping at the first failure.
+// w.mu is not held while publishing, so writes are never blocked by the broker.
+func (w *MQTTWriter) flush() error {
+	w.publishing.Lock()
+	defer w.publishing.Unlock()
+
+	for {
+		w.mu.Lock()
+		if len(w.pending) == 0 {
+			w.lastErr = nil
+			w.mu.Unlock()
+			return nil
+		}
+		entry, shifted := w.pending[0], w.shifted
+		w.mu.Unlock()
+
+		err := w.config.Publisher.Publish(w.config.Topic, w.config.QoS, w.config.Retained, entry)
+
+		w.mu.Lock()
+		if err != nil {
+			w.lastErr = fmt.Errorf("mqtt: publish: %w", err)
+			err = w.lastErr
+			w.mu.Unlock()
+			return err
+		}
+		// An overflow while publishing has already dropped the entry.
+		if w.shifted == shifted {
+			w.pending[0] = nil
+			w.pending = w.pending[1:]
+			w.shifted++
+		}
+		w.mu.Unlock()
+	}
+}
+
+// Health returns the error of the most recent publish while entries are
+// waiting to be published, or nil once the buffer has drained.
+func (w *MQTTWriter) Health(ctx context.Context) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.lastErr
+}
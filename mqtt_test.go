@@ -0,0 +1,117 @@
+package logger
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestMQTTWriter tests that entries are published and buffered while offline.
+func TestMQTTWriter(t *testing.T) {
+	var mu sync.Mutex
+	online := true
+	var published []string
+	publisher := MQTTPublishFunc(func(topic string, qos byte, retained bool, payload []byte) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if !online {
+			return errors.New("not connected")
+		}
+		if topic != "devices/42/logs" || qos != 1 || !retained {
+			t.Errorf("Unexpected publish options %s %d %v", topic, qos, retained)
+		}
+		published = append(published, string(payload))
+		return nil
+	})
+	setOnline := func(value bool) {
+		mu.Lock()
+		online = value
+		mu.Unlock()
+	}
+	writer := MustMQTTWriter(MQTTConfig{Publisher: publisher, Topic: "devices/42/logs", QoS: 1, Retained: true, BufferSize: 2, RetryInterval: time.Hour})
+	defer writer.Close()
+
+	writer.Write([]byte("a"))
+	if err := writer.Sync(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	setOnline(false)
+	for _, entry := range []string{"b", "c"} {
+		if _, err := writer.Write([]byte(entry)); err != nil {
+			t.Errorf("Unexpected error while buffering: %v", err)
+		}
+	}
+	if _, err := writer.Write([]byte("d")); err == nil {
+		t.Errorf("Expected an error when the buffer overflows")
+	}
+	if n := writer.Buffered(); n != 2 {
+		t.Errorf("Expected 2 buffered entries, got %d", n)
+	}
+
+	setOnline(true)
+	if err := writer.Sync(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	expected := []string{"a", "c", "d"}
+	if len(published) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, published)
+	}
+	for i := range expected {
+		if published[i] != expected[i] {
+			t.Errorf("Expected %v, got %v", expected, published)
+		}
+	}
+}
+
+// TestMQTTWriter_Background tests that Write does not wait on the broker and
+// entries are published in the background.
+func TestMQTTWriter_Background(t *testing.T) {
+	release := make(chan struct{})
+	published := make(chan string, 3)
+	publisher := MQTTPublishFunc(func(_ string, _ byte, _ bool, payload []byte) error {
+		<-release
+		published <- string(payload)
+		return nil
+	})
+	writer := MustMQTTWriter(MQTTConfig{Publisher: publisher, Topic: "logs"})
+
+	for _, entry := range []string{"a", "b", "c"} {
+		if _, err := writer.Write([]byte(entry)); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	close(release)
+	for _, expected := range []string{"a", "b", "c"} {
+		select {
+		case got := <-published:
+			if got != expected {
+				t.Errorf("Expected %s, got %s", expected, got)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("Expected %s to be published in the background", expected)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := writer.Write([]byte("d")); err == nil {
+		t.Errorf("Expected an error writing after Close")
+	}
+}
+
+// TestNewMQTTWriterE tests configuration validation.
+func TestNewMQTTWriterE(t *testing.T) {
+	publisher := MQTTPublishFunc(func(string, byte, bool, []byte) error { return nil })
+	for _, config := range []MQTTConfig{
+		{Topic: "logs"},
+		{Publisher: publisher},
+		{Publisher: publisher, Topic: "logs", QoS: 3},
+	} {
+		if _, err := NewMQTTWriterE(config); err == nil {
+			t.Errorf("Expected an error for %+v", config)
+		}
+	}
+}
@@ -0,0 +1,259 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+// newMsgpackCore returns a core writing each entry as a MessagePack map of the
+// time, level, message, caller, and fields. Entries are self-delimiting, so
+// they are written back to back without separators.
+func newMsgpackCore(encoderConfig zapcore.EncoderConfig, ws zapcore.WriteSyncer, enabler zapcore.LevelEnabler) zapcore.Core {
+	render := func(buf *buffer.Buffer, entry zapcore.Entry, fields map[string]interface{}) error {
+		header := make([]string, 0, 4)
+		values := make(map[string]interface{}, 4)
+		add := func(key string, value interface{}) {
+			if key != "" && key != zapcore.OmitKey {
+				header = append(header, key)
+				values[key] = value
+				delete(fields, key)
+			}
+		}
+		add(encoderConfig.TimeKey, encodePrimitive(func(enc zapcore.PrimitiveArrayEncoder) { encoderConfig.EncodeTime(entry.Time, enc) }))
+		add(encoderConfig.LevelKey, encodePrimitive(func(enc zapcore.PrimitiveArrayEncoder) { encoderConfig.EncodeLevel(entry.Level, enc) }))
+		add(encoderConfig.MessageKey, entry.Message)
+		if entry.Caller.Defined {
+			add(encoderConfig.CallerKey, entry.Caller.TrimmedPath())
+		}
+		if entry.Stack != "" {
+			add(encoderConfig.StacktraceKey, entry.Stack)
+		}
+
+		keys := sortedKeys(fields)
+		appendMsgpackMapHeader(buf, len(header)+len(keys))
+		for _, k := range header {
+			appendMsgpackString(buf, k)
+			if err := appendMsgpack(buf, values[k]); err != nil {
+				return err
+			}
+		}
+		for _, k := range keys {
+			appendMsgpackString(buf, k)
+			if err := appendMsgpack(buf, fields[k]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return newMapCore(render, ws, enabler)
+}
+
+// appendMsgpack appends the MessagePack encoding of a collected field value.
+// Times are written as RFC 3339 strings and durations as nanoseconds; values
+// of other types are converted through their JSON encoding.
+func appendMsgpack(buf *buffer.Buffer, v interface{}) error {
+	switch val := v.(type) {
+	case nil:
+		buf.AppendByte(0xc0)
+	case bool:
+		if val {
+			buf.AppendByte(0xc3)
+		} else {
+			buf.AppendByte(0xc2)
+		}
+	case int:
+		appendMsgpackInt(buf, int64(val))
+	case int8:
+		appendMsgpackInt(buf, int64(val))
+	case int16:
+		appendMsgpackInt(buf, int64(val))
+	case int32:
+		appendMsgpackInt(buf, int64(val))
+	case int64:
+		appendMsgpackInt(buf, val)
+	case uint:
+		appendMsgpackUint(buf, uint64(val))
+	case uint8:
+		appendMsgpackUint(buf, uint64(val))
+	case uint16:
+		appendMsgpackUint(buf, uint64(val))
+	case uint32:
+		appendMsgpackUint(buf, uint64(val))
+	case uint64:
+		appendMsgpackUint(buf, val)
+	case uintptr:
+		appendMsgpackUint(buf, uint64(val))
+	case float32:
+		buf.AppendByte(0xca)
+		appendBigEndian(buf, uint64(math.Float32bits(val)), 4)
+	case float64:
+		buf.AppendByte(0xcb)
+		appendBigEndian(buf, math.Float64bits(val), 8)
+	case string:
+		appendMsgpackString(buf, val)
+	case []byte:
+		appendMsgpackBinary(buf, val)
+	case time.Time:
+		appendMsgpackString(buf, val.Format(time.RFC3339Nano))
+	case time.Duration:
+		appendMsgpackInt(buf, int64(val))
+	case complex64, complex128:
+		appendMsgpackString(buf, fmt.Sprint(val))
+	case json.Number:
+		if i, err := val.Int64(); err == nil {
+			appendMsgpackInt(buf, i)
+		} else {
+			f, _ := val.Float64()
+			return appendMsgpack(buf, f)
+		}
+	case []interface{}:
+		appendMsgpackArrayHeader(buf, len(val))
+		for _, item := range val {
+			if err := appendMsgpack(buf, item); err != nil {
+				return err
+			}
+		}
+	case map[string]interface{}:
+		appendMsgpackMapHeader(buf, len(val))
+		for _, k := range sortedKeys(val) {
+			appendMsgpackString(buf, k)
+			if err := appendMsgpack(buf, val[k]); err != nil {
+				return err
+			}
+		}
+	default:
+		generic, err := jsonGeneric(val)
+		if err != nil {
+			return fmt.Errorf("msgpack: encode %T: %w", val, err)
+		}
+		return appendMsgpack(buf, generic)
+	}
+	return nil
+}
+
+// jsonGeneric converts v into maps, slices, and scalars through its JSON encoding.
+func jsonGeneric(v interface{}) (interface{}, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return decodeJSONValue(b)
+}
+
+// appendMsgpackInt appends a signed integer in its smallest encoding.
+func appendMsgpackInt(buf *buffer.Buffer, i int64) {
+	switch {
+	case i >= 0:
+		appendMsgpackUint(buf, uint64(i))
+	case i >= -32:
+		buf.AppendByte(byte(i))
+	case i >= math.MinInt8:
+		buf.AppendByte(0xd0)
+		appendBigEndian(buf, uint64(i), 1)
+	case i >= math.MinInt16:
+		buf.AppendByte(0xd1)
+		appendBigEndian(buf, uint64(i), 2)
+	case i >= math.MinInt32:
+		buf.AppendByte(0xd2)
+		appendBigEndian(buf, uint64(i), 4)
+	default:
+		buf.AppendByte(0xd3)
+		appendBigEndian(buf, uint64(i), 8)
+	}
+}
+
+// appendMsgpackUint appends an unsigned integer in its smallest encoding.
+func appendMsgpackUint(buf *buffer.Buffer, u uint64) {
+	switch {
+	case u <= 0x7f:
+		buf.AppendByte(byte(u))
+	case u <= math.MaxUint8:
+		buf.AppendByte(0xcc)
+		appendBigEndian(buf, u, 1)
+	case u <= math.MaxUint16:
+		buf.AppendByte(0xcd)
+		appendBigEndian(buf, u, 2)
+	case u <= math.MaxUint32:
+		buf.AppendByte(0xce)
+		appendBigEndian(buf, u, 4)
+	default:
+		buf.AppendByte(0xcf)
+		appendBigEndian(buf, u, 8)
+	}
+}
+
+// appendMsgpackString appends a UTF-8 string.
+func appendMsgpackString(buf *buffer.Buffer, s string) {
+	n := len(s)
+	switch {
+	case n <= 31:
+		buf.AppendByte(0xa0 | byte(n))
+	case n <= math.MaxUint8:
+		buf.AppendByte(0xd9)
+		appendBigEndian(buf, uint64(n), 1)
+	case n <= math.MaxUint16:
+		buf.AppendByte(0xda)
+		appendBigEndian(buf, uint64(n), 2)
+	default:
+		buf.AppendByte(0xdb)
+		appendBigEndian(buf, uint64(n), 4)
+	}
+	buf.AppendString(s)
+}
+
+// appendMsgpackBinary appends a byte slice.
+func appendMsgpackBinary(buf *buffer.Buffer, b []byte) {
+	n := len(b)
+	switch {
+	case n <= math.MaxUint8:
+		buf.AppendByte(0xc4)
+		appendBigEndian(buf, uint64(n), 1)
+	case n <= math.MaxUint16:
+		buf.AppendByte(0xc5)
+		appendBigEndian(buf, uint64(n), 2)
+	default:
+		buf.AppendByte(0xc6)
+		appendBigEndian(buf, uint64(n), 4)
+	}
+	buf.Write(b)
+}
+
+// appendMsgpackArrayHeader appends the header of an array of n items.
+func appendMsgpackArrayHeader(buf *buffer.Buffer, n int) {
+	switch {
+	case n <= 15:
+		buf.AppendByte(0x90 | byte(n))
+	case n <= math.MaxUint16:
+		buf.AppendByte(0xdc)
+		appendBigEndian(buf, uint64(n), 2)
+	default:
+		buf.AppendByte(0xdd)
+		appendBigEndian(buf, uint64(n), 4)
+	}
+}
+
+// appendMsgpackMapHeader appends the header of a map of n pairs.
+func appendMsgpackMapHeader(buf *buffer.Buffer, n int) {
+	switch {
+	case n <= 15:
+		buf.AppendByte(0x80 | byte(n))
+	case n <= math.MaxUint16:
+		buf.AppendByte(0xde)
+		appendBigEndian(buf, uint64(n), 2)
+	default:
+		buf.AppendByte(0xdf)
+		appendBigEndian(buf, uint64(n), 4)
+	}
+}
+
+// appendBigEndian appends the low size bytes of v, most significant first.
+func appendBigEndian(buf *buffer.Buffer, v uint64, size int) {
+	for i := size - 1; i >= 0; i-- {
+		buf.AppendByte(byte(v >> (8 * uint(i))))
+	}
+}
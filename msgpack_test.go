@@ -0,0 +1,68 @@
+package logger
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/buffer"
+)
+
+// TestFormat_MsgPack tests that entries are written as MessagePack maps.
+func TestFormat_MsgPack(t *testing.T) {
+	output := new(bytes.Buffer)
+	zapLogger := NewZap(Config{
+		Level:         InfoLevel,
+		Output:        output,
+		Format:        FormatMsgPack,
+		DisableCaller: true,
+		Clock:         fixedClock(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)),
+	})
+
+	zapLogger.Info("hi", Fields{"n": 1})
+
+	expected := []byte{0x84}
+	expected = append(expected, 0xa2, 't', 's', 0xb4)
+	expected = append(expected, "2024-01-02T03:04:05Z"...)
+	expected = append(expected, 0xa5, 'l', 'e', 'v', 'e', 'l', 0xa4, 'i', 'n', 'f', 'o')
+	expected = append(expected, 0xa3, 'm', 's', 'g', 0xa2, 'h', 'i')
+	expected = append(expected, 0xa1, 'n', 0x01)
+	if !bytes.Equal(output.Bytes(), expected) {
+		t.Errorf("Expected % x, got % x", expected, output.Bytes())
+	}
+}
+
+// TestAppendMsgpack tests the MessagePack encoding of field values.
+func TestAppendMsgpack(t *testing.T) {
+	tests := []struct {
+		value    interface{}
+		expected []byte
+	}{
+		{nil, []byte{0xc0}},
+		{true, []byte{0xc3}},
+		{int64(-1), []byte{0xff}},
+		{int64(-100), []byte{0xd0, 0x9c}},
+		{200, []byte{0xcc, 0xc8}},
+		{int64(70000), []byte{0xce, 0x00, 0x01, 0x11, 0x70}},
+		{1.5, []byte{0xcb, 0x3f, 0xf8, 0, 0, 0, 0, 0, 0}},
+		{[]byte{1, 2}, []byte{0xc4, 0x02, 0x01, 0x02}},
+		{[]interface{}{"a", uint8(1)}, []byte{0x92, 0xa1, 'a', 0x01}},
+		{map[string]interface{}{"b": false}, []byte{0x81, 0xa1, 'b', 0xc2}},
+		{struct{ A int }{A: 3}, []byte{0x81, 0xa1, 'A', 0x03}},
+	}
+	for _, test := range tests {
+		buf := encoderBuffers.Get()
+		if err := appendMsgpack(buf, test.value); err != nil {
+			t.Errorf("Unexpected error for %v: %v", test.value, err)
+		}
+		if !bytes.Equal(buf.Bytes(), test.expected) {
+			t.Errorf("Expected % x for %v, got % x", test.expected, test.value, buf.Bytes())
+		}
+		buf.Free()
+	}
+
+	var buf buffer.Buffer
+	if err := appendMsgpack(&buf, func() {}); err == nil {
+		t.Errorf("Expected an error for an unencodable value")
+	}
+}
@@ -0,0 +1,168 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// NATSPublisher publishes a message on a subject. *nats.Conn satisfies it;
+// a JetStream context can be adapted with NATSPublishFunc for persistence.
+type NATSPublisher interface {
+	Publish(subject string, data []byte) error
+}
+
+// NATSPublishFunc adapts a function to a NATSPublisher, e.g. for JetStream:
+//
+//	logger.NATSPublishFunc(func(subject string, data []byte) error {
+//		_, err := js.Publish(subject, data)
+//		return err
+//	})
+type NATSPublishFunc func(subject string, data []byte) error
+
+// Publish calls f(subject, data).
+func (f NATSPublishFunc) Publish(subject string, data []byte) error {
+	return f(subject, data)
+}
+
+// NATSConfig holds the configuration for the NATS writer.
+type NATSConfig struct {
+	// Publisher sends the entries, typically a *nats.Conn.
+	Publisher NATSPublisher
+	// Subject is the subject entries are published on. It may reference entry
+	// fields as {key}, e.g. logs.{service}.{level}; missing fields become
+	// "unknown" and characters not allowed in a subject token become "_".
+	Subject string
+}
+
+// NATSWriter is an output that publishes each JSON entry written by Zap as a
+// NATS message.
+type NATSWriter struct {
+	publisher NATSPublisher
+	subject   string
+	template  []subjectSegment
+	mu        sync.Mutex
+	lastErr   error
+}
+
+// subjectSegment is a literal part of a subject template, or a field reference.
+type subjectSegment struct {
+	text  string
+	field bool
+}
+
+// NewNATSWriterE returns a new *NATSWriter, or an error if the publisher or
+// subject is missing or the subject template is malformed.
+func NewNATSWriterE(config NATSConfig) (*NATSWriter, error) {
+	if config.Publisher == nil {
+		return nil, errors.New("nats: publisher is not configured")
+	}
+	if config.Subject == "" {
+		return nil, errors.New("nats: subject is not configured")
+	}
+	template, err := parseSubjectTemplate(config.Subject)
+	if err != nil {
+		return nil, err
+	}
+	return &NATSWriter{publisher: config.Publisher, subject: config.Subject, template: template}, nil
+}
+
+// MustNATSWriter is like NewNATSWriterE but panics on error.
+func MustNATSWriter(config NATSConfig) *NATSWriter {
+	w, err := NewNATSWriterE(config)
+	if err != nil {
+		panic(err)
+	}
+	return w
+}
+
+// parseSubjectTemplate splits a subject into literal text and {key} references.
+func parseSubjectTemplate(subject string) ([]subjectSegment, error) {
+	var segments []subjectSegment
+	for subject != "" {
+		before, rest, found := strings.Cut(subject, "{")
+		if before != "" {
+			segments = append(segments, subjectSegment{text: before})
+		}
+		if !found {
+			break
+		}
+		key, after, closed := strings.Cut(rest, "}")
+		if !closed || key == "" {
+			return nil, fmt.Errorf("nats: malformed subject template %q", subject)
+		}
+		segments = append(segments, subjectSegment{text: key, field: true})
+		subject = after
+	}
+	return segments, nil
+}
+
+// Write publishes a single JSON entry on its subject.
+func (w *NATSWriter) Write(p []byte) (int, error) {
+	subject, err := w.subjectFor(p)
+	if err != nil {
+		return 0, err
+	}
+	data := make([]byte, len(p))
+	copy(data, p)
+	err = w.publisher.Publish(subject, data)
+	if err != nil {
+		err = fmt.Errorf("nats: publish: %w", err)
+	}
+	w.mu.Lock()
+	w.lastErr = err
+	w.mu.Unlock()
+	if err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Health returns the error of the most recent publish, or nil if it succeeded.
+func (w *NATSWriter) Health(ctx context.Context) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.lastErr
+}
+
+// subjectFor renders the subject template for an entry.
+func (w *NATSWriter) subjectFor(p []byte) (string, error) {
+	if len(w.template) <= 1 && (len(w.template) == 0 || !w.template[0].field) {
+		return w.subject, nil
+	}
+
+	entry, err := decodeEntry(p)
+	if err != nil {
+		return "", fmt.Errorf("nats: decode entry: %w", err)
+	}
+	var b strings.Builder
+	for _, segment := range w.template {
+		if !segment.field {
+			b.WriteString(segment.text)
+			continue
+		}
+		value, ok := entry[segment.text]
+		if !ok || value == nil {
+			b.WriteString("unknown")
+			continue
+		}
+		b.WriteString(subjectToken(fmt.Sprint(value)))
+	}
+	return b.String(), nil
+}
+
+// subjectToken replaces the characters NATS does not allow within a subject token.
+func subjectToken(s string) string {
+	if s == "" {
+		return "unknown"
+	}
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '.', '*', '>', ' ', '\t', '\r', '\n':
+			return '_'
+		}
+		return r
+	}, s)
+}
@@ -0,0 +1,49 @@
+package logger
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestNATSWriter tests that entries are published on subjects rendered from their fields.
+func TestNATSWriter(t *testing.T) {
+	var subjects []string
+	publisher := NATSPublishFunc(func(subject string, data []byte) error {
+		subjects = append(subjects, subject)
+		return nil
+	})
+	writer := MustNATSWriter(NATSConfig{Publisher: publisher, Subject: "logs.{service}.{level}"})
+	zapLogger := NewZap(Config{Level: InfoLevel, Output: writer})
+
+	zapLogger.Info("Info message", Fields{"service": "orders.api"})
+	zapLogger.Warn("Warn message", nil)
+
+	expected := []string{"logs.orders_api.info", "logs.unknown.warn"}
+	if len(subjects) != len(expected) {
+		t.Fatalf("Expected %d messages, got %d", len(expected), len(subjects))
+	}
+	for i := range expected {
+		if subjects[i] != expected[i] {
+			t.Errorf("Expected subject %s, got %s", expected[i], subjects[i])
+		}
+	}
+}
+
+// TestNewNATSWriterE tests configuration validation and publish errors.
+func TestNewNATSWriterE(t *testing.T) {
+	publisher := NATSPublishFunc(func(string, []byte) error { return errors.New("disconnected") })
+	for _, config := range []NATSConfig{
+		{Subject: "logs"},
+		{Publisher: publisher},
+		{Publisher: publisher, Subject: "logs.{level"},
+	} {
+		if _, err := NewNATSWriterE(config); err == nil {
+			t.Errorf("Expected an error for %+v", config)
+		}
+	}
+
+	writer := MustNATSWriter(NATSConfig{Publisher: publisher, Subject: "logs"})
+	if _, err := writer.Write([]byte(`{"msg":"m"}`)); err == nil {
+		t.Errorf("Expected the publish error to be returned")
+	}
+}
@@ -0,0 +1,204 @@
+package logger
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ObservedLogs is a concurrency-safe, ordered collection of observed entries.
+type ObservedLogs struct {
+	mu      sync.RWMutex
+	entries []Entry
+}
+
+// Observe returns a Logger that records entries in memory instead of writing
+// them, together with the collection of recorded entries. It honours
+// Config.Level and Config.Clock, and is independent of any backend so tests can
+// assert on log output the same way whichever implementation production uses.
+// Fatal records the entry and calls Config.ExitFunc when set; it never exits
+// the process on its own.
+func Observe(config Config) (Logger, *ObservedLogs) {
+	logs := &ObservedLogs{}
+	return &observer{config: config, logs: logs}, logs
+}
+
+// Len returns the number of entries observed so far.
+func (o *ObservedLogs) Len() int {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return len(o.entries)
+}
+
+// All returns a copy of all the observed entries.
+func (o *ObservedLogs) All() []Entry {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	entries := make([]Entry, len(o.entries))
+	copy(entries, o.entries)
+	return entries
+}
+
+// TakeAll returns all the observed entries and clears the collection.
+func (o *ObservedLogs) TakeAll() []Entry {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	entries := o.entries
+	o.entries = nil
+	return entries
+}
+
+// Filter returns a copy of the entries for which keep returns true.
+func (o *ObservedLogs) Filter(keep func(Entry) bool) *ObservedLogs {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	filtered := &ObservedLogs{}
+	for _, entry := range o.entries {
+		if keep(entry) {
+			filtered.entries = append(filtered.entries, entry)
+		}
+	}
+	return filtered
+}
+
+// FilterMessage returns the entries whose message equals msg.
+func (o *ObservedLogs) FilterMessage(msg string) *ObservedLogs {
+	return o.Filter(func(e Entry) bool {
+		return e.Message == msg
+	})
+}
+
+// FilterMessageSnippet returns the entries whose message contains snippet.
+func (o *ObservedLogs) FilterMessageSnippet(snippet string) *ObservedLogs {
+	return o.Filter(func(e Entry) bool {
+		return strings.Contains(e.Message, snippet)
+	})
+}
+
+// FilterFieldKey returns the entries that have a field named key.
+func (o *ObservedLogs) FilterFieldKey(key string) *ObservedLogs {
+	return o.Filter(func(e Entry) bool {
+		_, ok := e.Fields[key]
+		return ok
+	})
+}
+
+// FilterField returns the entries that have a field named key equal to value.
+func (o *ObservedLogs) FilterField(key string, value interface{}) *ObservedLogs {
+	return o.Filter(func(e Entry) bool {
+		v, ok := e.Fields[key]
+		return ok && reflect.DeepEqual(v, value)
+	})
+}
+
+// FilterLevelExact returns the entries logged at exactly level.
+func (o *ObservedLogs) FilterLevelExact(level Level) *ObservedLogs {
+	return o.Filter(func(e Entry) bool {
+		return e.Level == level
+	})
+}
+
+// FilterMinLevel returns the entries logged at level or above.
+func (o *ObservedLogs) FilterMinLevel(level Level) *ObservedLogs {
+	return o.Filter(func(e Entry) bool {
+		return e.Level >= level
+	})
+}
+
+// add appends an entry to the collection.
+func (o *ObservedLogs) add(entry Entry) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.entries = append(o.entries, entry)
+}
+
+// observer is the Logger returned by Observe.
+type observer struct {
+	config Config
+	logs   *ObservedLogs
+	fields Fields
+}
+
+// With returns a child observer that adds fields to every entry it records.
+func (o *observer) With(fields Fields) Logger {
+	return &observer{config: o.config, logs: o.logs, fields: mergeFields(o.fields, fields)}
+}
+
+// Debug records a debug message with structured fields.
+func (o *observer) Debug(msg string, fields Fields) {
+	o.log(DebugLevel, msg, fields)
+}
+
+// Info records an info message with structured fields.
+func (o *observer) Info(msg string, fields Fields) {
+	o.log(InfoLevel, msg, fields)
+}
+
+// Warn records a warning message with structured fields.
+func (o *observer) Warn(msg string, fields Fields) {
+	o.log(WarnLevel, msg, fields)
+}
+
+// Error records an error message with structured fields.
+func (o *observer) Error(msg string, fields Fields) {
+	o.log(ErrorLevel, msg, fields)
+}
+
+// Fatal records a fatal message with structured fields and calls Config.ExitFunc if set.
+func (o *observer) Fatal(msg string, fields Fields) {
+	if o.log(FatalLevel, msg, fields) && o.config.ExitFunc != nil {
+		o.config.ExitFunc(exitCode(o.config, fields))
+	}
+}
+
+// DebugFields records a debug message with typed fields.
+func (o *observer) DebugFields(msg string, fields ...Field) {
+	o.log(DebugLevel, msg, fieldsToMap(nil, fields))
+}
+
+// InfoFields records an info message with typed fields.
+func (o *observer) InfoFields(msg string, fields ...Field) {
+	o.log(InfoLevel, msg, fieldsToMap(nil, fields))
+}
+
+// WarnFields records a warning message with typed fields.
+func (o *observer) WarnFields(msg string, fields ...Field) {
+	o.log(WarnLevel, msg, fieldsToMap(nil, fields))
+}
+
+// ErrorFields records an error message with typed fields.
+func (o *observer) ErrorFields(msg string, fields ...Field) {
+	o.log(ErrorLevel, msg, fieldsToMap(nil, fields))
+}
+
+// FatalFields records a fatal message with typed fields and calls Config.ExitFunc if set.
+func (o *observer) FatalFields(msg string, fields ...Field) {
+	o.Fatal(msg, fieldsToMap(nil, fields))
+}
+
+// Enabled reports whether entries at level are recorded.
+func (o *observer) Enabled(level Level) bool {
+	return level >= o.config.Level
+}
+
+// DebugEnabled reports whether debug entries are recorded.
+func (o *observer) DebugEnabled() bool {
+	return o.Enabled(DebugLevel)
+}
+
+// log records an entry at the given level and reports whether it was recorded.
+func (o *observer) log(level Level, msg string, fields Fields) bool {
+	if !o.Enabled(level) {
+		return false
+	}
+
+	now := time.Now()
+	if o.config.Clock != nil {
+		now = o.config.Clock.Now()
+	}
+	copied := mergeFields(o.fields, fields)
+
+	o.logs.add(Entry{Level: level, Time: now, Message: msg, Fields: copied})
+	return true
+}
@@ -0,0 +1,59 @@
+package logger
+
+import (
+	"testing"
+)
+
+// TestObserve tests that entries are recorded and can be filtered.
+func TestObserve(t *testing.T) {
+	log, logs := Observe(Config{Level: InfoLevel})
+
+	log.Debug("Debug message", nil)
+	log.Info("Info message", Fields{"key": "value"})
+	log.Warn("Warn message", Fields{"other": 1})
+	log.Error("Error message", Fields{"key": "other"})
+
+	if logs.Len() != 3 {
+		t.Fatalf("Expected 3 entries, got %d", logs.Len())
+	}
+	if n := logs.FilterMessage("Info message").Len(); n != 1 {
+		t.Errorf("Expected 1 entry with message, got %d", n)
+	}
+	if n := logs.FilterMessageSnippet("message").Len(); n != 3 {
+		t.Errorf("Expected 3 entries with snippet, got %d", n)
+	}
+	if n := logs.FilterFieldKey("key").Len(); n != 2 {
+		t.Errorf("Expected 2 entries with field key, got %d", n)
+	}
+	if n := logs.FilterField("key", "value").Len(); n != 1 {
+		t.Errorf("Expected 1 entry with field value, got %d", n)
+	}
+	if n := logs.FilterLevelExact(WarnLevel).Len(); n != 1 {
+		t.Errorf("Expected 1 warn entry, got %d", n)
+	}
+	if n := logs.FilterMinLevel(WarnLevel).Len(); n != 2 {
+		t.Errorf("Expected 2 entries at warn or above, got %d", n)
+	}
+
+	entries := logs.TakeAll()
+	if len(entries) != 3 || entries[0].Message != "Info message" {
+		t.Errorf("Expected 3 entries in order, got %+v", entries)
+	}
+	if logs.Len() != 0 {
+		t.Errorf("Expected TakeAll to clear entries, got %d", logs.Len())
+	}
+}
+
+// TestObserve_Fatal tests that Fatal records the entry and calls ExitFunc.
+func TestObserve_Fatal(t *testing.T) {
+	exitCode := -1
+	log, logs := Observe(Config{ExitFunc: func(code int) { exitCode = code }})
+
+	log.Fatal("Fatal message", nil)
+	if logs.FilterLevelExact(FatalLevel).Len() != 1 {
+		t.Errorf("Expected the fatal entry to be recorded")
+	}
+	if exitCode != 1 {
+		t.Errorf("Expected ExitFunc to be called with 1, got %d", exitCode)
+	}
+}
@@ -0,0 +1,373 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// otlpScopeName is the instrumentation scope reported on exported records.
+const otlpScopeName = "github.com/ralonr/logger"
+
+// OTLPTransport delivers an encoded OTLP ExportLogsServiceRequest to a collector.
+// The built-in transport speaks OTLP/HTTP with JSON encoding; an OTLP/gRPC client
+// can be plugged in by implementing this interface.
+type OTLPTransport interface {
+	Export(ctx context.Context, request *OTLPRequest) error
+}
+
+// OTLPConfig holds the configuration for the OTLP exporter.
+type OTLPConfig struct {
+	// Endpoint is the OTLP/HTTP logs endpoint, e.g. http://localhost:4318/v1/logs.
+	Endpoint string
+	// Headers are added to every export request (e.g. authentication).
+	Headers map[string]string
+	// Resource attributes describing the emitting service (e.g. service.name).
+	Resource map[string]interface{}
+	// BatchSize is the number of records buffered before an export; defaults to 1.
+	BatchSize int
+	// MaxBuffered caps the records kept for the next export after failed
+	// exports; the oldest are dropped beyond it. Defaults to 100 times BatchSize.
+	MaxBuffered int
+	// Timeout bounds a single export; defaults to 10 seconds.
+	Timeout time.Duration
+	// Transport overrides the default OTLP/HTTP transport, e.g. with a gRPC client.
+	Transport OTLPTransport
+	// Severities overrides the OTLP severity numbers reported for levels.
+	Severities map[Level]int
+	// KeyNames are the keys of the core entry fields; set it to the
+	// Config.KeyNames of the logger writing to the exporter.
+	KeyNames KeyNames
+	// OnError is called when an export triggered by Write fails. The records
+	// stay buffered for the next export, and Health reports the failure.
+	OnError func(error)
+}
+
+// OTLPExporter is an output that maps log entries to OpenTelemetry LogRecords
+// and exports them to an OTLP collector. It expects the JSON entries written by Zap.
+type OTLPExporter struct {
+	config  OTLPConfig
+	mu      sync.Mutex
+	batch   []OTLPLogRecord
+	lastErr error
+}
+
+// NewOTLPExporterE returns a new *OTLPExporter, or an error if no transport is
+// given and the endpoint is not a valid http or https URL.
+func NewOTLPExporterE(config OTLPConfig) (*OTLPExporter, error) {
+	if config.Transport == nil {
+		u, err := url.Parse(config.Endpoint)
+		if err != nil {
+			return nil, fmt.Errorf("otlp: invalid endpoint: %w", err)
+		}
+		if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("otlp: invalid endpoint %q", config.Endpoint)
+		}
+	}
+	return NewOTLPExporter(config), nil
+}
+
+// MustOTLPExporter is like NewOTLPExporterE but panics on error.
+func MustOTLPExporter(config OTLPConfig) *OTLPExporter {
+	e, err := NewOTLPExporterE(config)
+	if err != nil {
+		panic(err)
+	}
+	return e
+}
+
+// NewOTLPExporter returns a new *OTLPExporter. An invalid endpoint is only
+// detected on the first export; use NewOTLPExporterE to check it up front.
+func NewOTLPExporter(config OTLPConfig) *OTLPExporter {
+	if config.BatchSize <= 0 {
+		config.BatchSize = 1
+	}
+	if config.MaxBuffered <= 0 {
+		config.MaxBuffered = 100 * config.BatchSize
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 10 * time.Second
+	}
+	if config.Transport == nil {
+		config.Transport = &otlpHTTPTransport{
+			endpoint: config.Endpoint,
+			headers:  config.Headers,
+			client:   &http.Client{},
+		}
+	}
+	return &OTLPExporter{config: config}
+}
+
+// Write decodes a single JSON entry and queues it for export. Once queued the
+// entry is accepted, even if the export it triggers fails, so that wrappers
+// such as RetryWriter do not queue it twice.
+func (e *OTLPExporter) Write(p []byte) (int, error) {
+	record, err := otlpRecordFromJSON(p, e.config.KeyNames, e.config.Severities)
+	if err != nil {
+		return 0, err
+	}
+
+	e.mu.Lock()
+	e.batch = append(e.batch, record)
+	full := len(e.batch) >= e.config.BatchSize
+	e.mu.Unlock()
+
+	if full {
+		if err := e.Sync(); err != nil && e.config.OnError != nil {
+			e.config.OnError(err)
+		}
+	}
+	return len(p), nil
+}
+
+// Sync exports any buffered records. If the export fails, the records are kept
+// for the next export, up to MaxBuffered.
+func (e *OTLPExporter) Sync() error {
+	e.mu.Lock()
+	batch := e.batch
+	e.batch = nil
+	e.mu.Unlock()
+
+	if len(batch) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), e.config.Timeout)
+	defer cancel()
+	err := e.config.Transport.Export(ctx, e.request(batch))
+	e.mu.Lock()
+	e.lastErr = err
+	if err != nil {
+		e.batch = append(batch, e.batch...)
+		if over := len(e.batch) - e.config.MaxBuffered; over > 0 {
+			e.batch = e.batch[over:]
+		}
+	}
+	e.mu.Unlock()
+	return err
+}
+
+// Health returns the error of the most recent export, or nil if it succeeded.
+func (e *OTLPExporter) Health(ctx context.Context) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.lastErr
+}
+
+// Ping verifies the collector without exporting: a custom transport is
+// pinged if it implements Pinger, and the HTTP endpoint is resolved and
+// connected to.
+func (e *OTLPExporter) Ping(ctx context.Context) error {
+	switch t := e.config.Transport.(type) {
+	case Pinger:
+		return t.Ping(ctx)
+	case *otlpHTTPTransport:
+		if err := pingURL(ctx, t.endpoint); err != nil {
+			return fmt.Errorf("otlp: ping: %w", err)
+		}
+	}
+	return nil
+}
+
+// request wraps records into an ExportLogsServiceRequest.
+func (e *OTLPExporter) request(records []OTLPLogRecord) *OTLPRequest {
+	return &OTLPRequest{
+		ResourceLogs: []OTLPResourceLogs{{
+			Resource: OTLPResource{Attributes: otlpAttributes(e.config.Resource)},
+			ScopeLogs: []OTLPScopeLogs{{
+				Scope:      OTLPScope{Name: otlpScopeName},
+				LogRecords: records,
+			}},
+		}},
+	}
+}
+
+// OTLPRequest is the JSON form of an OTLP ExportLogsServiceRequest.
+type OTLPRequest struct {
+	ResourceLogs []OTLPResourceLogs `json:"resourceLogs"`
+}
+
+// OTLPResourceLogs groups scope logs emitted by a single resource.
+type OTLPResourceLogs struct {
+	Resource  OTLPResource    `json:"resource"`
+	ScopeLogs []OTLPScopeLogs `json:"scopeLogs"`
+}
+
+// OTLPResource describes the entity producing the logs.
+type OTLPResource struct {
+	Attributes []OTLPKeyValue `json:"attributes,omitempty"`
+}
+
+// OTLPScopeLogs groups log records emitted by a single instrumentation scope.
+type OTLPScopeLogs struct {
+	Scope      OTLPScope       `json:"scope"`
+	LogRecords []OTLPLogRecord `json:"logRecords"`
+}
+
+// OTLPScope identifies the instrumentation scope.
+type OTLPScope struct {
+	Name string `json:"name"`
+}
+
+// OTLPLogRecord is the JSON form of an OTLP LogRecord.
+type OTLPLogRecord struct {
+	TimeUnixNano         string         `json:"timeUnixNano,omitempty"`
+	ObservedTimeUnixNano string         `json:"observedTimeUnixNano"`
+	SeverityNumber       int            `json:"severityNumber"`
+	SeverityText         string         `json:"severityText"`
+	Body                 OTLPAnyValue   `json:"body"`
+	Attributes           []OTLPKeyValue `json:"attributes,omitempty"`
+}
+
+// OTLPKeyValue is an OTLP attribute.
+type OTLPKeyValue struct {
+	Key   string       `json:"key"`
+	Value OTLPAnyValue `json:"value"`
+}
+
+// OTLPAnyValue is the JSON form of an OTLP AnyValue; exactly one member is set.
+type OTLPAnyValue struct {
+	StringValue *string         `json:"stringValue,omitempty"`
+	BoolValue   *bool           `json:"boolValue,omitempty"`
+	IntValue    *string         `json:"intValue,omitempty"`
+	DoubleValue *float64        `json:"doubleValue,omitempty"`
+	ArrayValue  *OTLPArrayValue `json:"arrayValue,omitempty"`
+	KvlistValue *OTLPKvlist     `json:"kvlistValue,omitempty"`
+}
+
+// OTLPArrayValue is a list of AnyValues.
+type OTLPArrayValue struct {
+	Values []OTLPAnyValue `json:"values"`
+}
+
+// OTLPKvlist is a list of key-value pairs.
+type OTLPKvlist struct {
+	Values []OTLPKeyValue `json:"values"`
+}
+
+// otlpSeverity maps the encoded level to an OTLP severity number.
+var otlpSeverity = map[string]int{
+	"debug":  5,
+	"info":   9,
+	"warn":   13,
+	"error":  17,
+	"dpanic": 21,
+	"panic":  21,
+	"fatal":  21,
+}
+
+// otlpRecordFromJSON converts a JSON entry with the core keys named by names
+// into an OTLP LogRecord, mapping levels to severity numbers through
+// severities where configured.
+func otlpRecordFromJSON(p []byte, names KeyNames, severities map[Level]int) (OTLPLogRecord, error) {
+	entry, err := decodeEntry(p)
+	if err != nil {
+		return OTLPLogRecord{}, fmt.Errorf("otlp: decode entry: %w", err)
+	}
+
+	record := OTLPLogRecord{
+		ObservedTimeUnixNano: strconv.FormatInt(time.Now().UnixNano(), 10),
+	}
+	core := takeEntryCore(entry, names)
+	if core.level != "" {
+		record.SeverityText = core.level
+		record.SeverityNumber = mapSeverity(core.level, severities, otlpSeverity)
+	}
+	if core.hasTime {
+		record.TimeUnixNano = strconv.FormatInt(core.time.UnixNano(), 10)
+	}
+	record.Body = otlpValue(core.message)
+
+	record.Attributes = otlpAttributes(entry)
+	return record, nil
+}
+
+// otlpAttributes converts a map into OTLP attributes.
+func otlpAttributes(m map[string]interface{}) []OTLPKeyValue {
+	attributes := make([]OTLPKeyValue, 0, len(m))
+	for k, v := range m {
+		attributes = append(attributes, OTLPKeyValue{Key: k, Value: otlpValue(v)})
+	}
+	return attributes
+}
+
+// otlpValue converts a decoded JSON value into an OTLP AnyValue.
+func otlpValue(v interface{}) OTLPAnyValue {
+	switch val := v.(type) {
+	case string:
+		return OTLPAnyValue{StringValue: &val}
+	case bool:
+		return OTLPAnyValue{BoolValue: &val}
+	case int:
+		s := strconv.Itoa(val)
+		return OTLPAnyValue{IntValue: &s}
+	case json.Number:
+		if _, err := val.Int64(); err == nil {
+			s := val.String()
+			return OTLPAnyValue{IntValue: &s}
+		}
+		f, _ := val.Float64()
+		return OTLPAnyValue{DoubleValue: &f}
+	case float64:
+		return OTLPAnyValue{DoubleValue: &val}
+	case []interface{}:
+		values := make([]OTLPAnyValue, 0, len(val))
+		for _, item := range val {
+			values = append(values, otlpValue(item))
+		}
+		return OTLPAnyValue{ArrayValue: &OTLPArrayValue{Values: values}}
+	case map[string]interface{}:
+		return OTLPAnyValue{KvlistValue: &OTLPKvlist{Values: otlpAttributes(val)}}
+	case nil:
+		return OTLPAnyValue{}
+	default:
+		s := fmt.Sprint(val)
+		return OTLPAnyValue{StringValue: &s}
+	}
+}
+
+// otlpHTTPTransport exports requests using OTLP/HTTP with JSON encoding.
+type otlpHTTPTransport struct {
+	endpoint string
+	headers  map[string]string
+	client   *http.Client
+}
+
+// Export posts the request to the collector.
+func (t *otlpHTTPTransport) Export(ctx context.Context, request *OTLPRequest) error {
+	if t.endpoint == "" {
+		return errors.New("otlp: endpoint is not configured")
+	}
+
+	body, err := json.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("otlp: encode request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("otlp: build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range t.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("otlp: export: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("otlp: export: unexpected status %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,208 @@
+package logger
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestOTLPExporter tests that entries are exported as OTLP LogRecords.
+func TestOTLPExporter(t *testing.T) {
+	var received OTLPRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+			t.Errorf("Expected content type application/json, got %s", ct)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("Failed to decode request: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	exporter := NewOTLPExporter(OTLPConfig{
+		Endpoint: server.URL,
+		Resource: map[string]interface{}{"service.name": "test"},
+	})
+	zapLogger := NewZap(Config{
+		Level:    InfoLevel,
+		Output:   exporter,
+		ExitFunc: func(int) {},
+	})
+
+	zapLogger.Warn("Warn message", Fields{"key": "value"})
+
+	if len(received.ResourceLogs) != 1 {
+		t.Fatalf("Expected 1 resource log, got %d", len(received.ResourceLogs))
+	}
+	records := received.ResourceLogs[0].ScopeLogs[0].LogRecords
+	if len(records) != 1 {
+		t.Fatalf("Expected 1 record, got %d", len(records))
+	}
+	record := records[0]
+	if record.SeverityNumber != 13 || record.SeverityText != "warn" {
+		t.Errorf("Expected severity 13/warn, got %d/%s", record.SeverityNumber, record.SeverityText)
+	}
+	if record.Body.StringValue == nil || *record.Body.StringValue != "Warn message" {
+		t.Errorf("Expected body %q, got %+v", "Warn message", record.Body)
+	}
+	if record.TimeUnixNano == "" {
+		t.Errorf("Expected timeUnixNano to be set")
+	}
+
+	var found bool
+	for _, attr := range record.Attributes {
+		if attr.Key == "key" && attr.Value.StringValue != nil && *attr.Value.StringValue == "value" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected attribute key=value, got %+v", record.Attributes)
+	}
+}
+
+// TestOTLPExporter_Batch tests that records are buffered until the batch is full or synced.
+func TestOTLPExporter_Batch(t *testing.T) {
+	transport := &recordingTransport{}
+	exporter := NewOTLPExporter(OTLPConfig{BatchSize: 3, Transport: transport})
+
+	for i := 0; i < 2; i++ {
+		if _, err := exporter.Write([]byte(`{"level":"info","msg":"m"}`)); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	if len(transport.requests) != 0 {
+		t.Errorf("Expected no exports before batch is full, got %d", len(transport.requests))
+	}
+
+	if err := exporter.Sync(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(transport.requests) != 1 {
+		t.Fatalf("Expected 1 export after Sync, got %d", len(transport.requests))
+	}
+	if n := len(transport.requests[0].ResourceLogs[0].ScopeLogs[0].LogRecords); n != 2 {
+		t.Errorf("Expected 2 records, got %d", n)
+	}
+}
+
+// TestOTLPExporter_Retry tests that records of a failed export are kept for
+// the next one, up to MaxBuffered.
+func TestOTLPExporter_Retry(t *testing.T) {
+	transport := &recordingTransport{err: errors.New("collector down")}
+	var reported []error
+	exporter := NewOTLPExporter(OTLPConfig{
+		BatchSize:   2,
+		MaxBuffered: 3,
+		Transport:   transport,
+		OnError:     func(err error) { reported = append(reported, err) },
+	})
+
+	for i := 0; i < 4; i++ {
+		if _, err := exporter.Write([]byte(fmt.Sprintf(`{"level":"info","msg":"m%d"}`, i))); err != nil {
+			t.Errorf("Expected the buffered record to be accepted, got %v", err)
+		}
+	}
+	if len(reported) != 3 || exporter.Health(context.Background()) == nil {
+		t.Errorf("Expected the failed exports to be reported, got %v", reported)
+	}
+	if err := exporter.Sync(); err == nil {
+		t.Fatalf("Expected the export to fail")
+	}
+
+	transport.err = nil
+	if err := exporter.Sync(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(transport.requests) != 1 {
+		t.Fatalf("Expected 1 export, got %d", len(transport.requests))
+	}
+	var messages []string
+	for _, record := range transport.requests[0].ResourceLogs[0].ScopeLogs[0].LogRecords {
+		messages = append(messages, *record.Body.StringValue)
+	}
+	if fmt.Sprint(messages) != "[m1 m2 m3]" {
+		t.Errorf("Expected the 3 most recent records to be exported, got %v", messages)
+	}
+}
+
+// TestOTLPExporter_Severities tests that severity numbers can be remapped per level.
+func TestOTLPExporter_Severities(t *testing.T) {
+	transport := &recordingTransport{}
+	exporter := NewOTLPExporter(OTLPConfig{Transport: transport, Severities: map[Level]int{WarnLevel: 14}})
+
+	exporter.Write([]byte(`{"level":"warn","msg":"m"}`))
+	exporter.Write([]byte(`{"level":"error","msg":"m"}`))
+
+	for i, expected := range []int{14, 17} {
+		if got := transport.requests[i].ResourceLogs[0].ScopeLogs[0].LogRecords[0].SeverityNumber; got != expected {
+			t.Errorf("Expected severity %d, got %d", expected, got)
+		}
+	}
+}
+
+// TestOTLPExporter_KeyNames tests that entries written with custom key names
+// and numeric severities are decoded.
+func TestOTLPExporter_KeyNames(t *testing.T) {
+	names := KeyNames{Time: "@timestamp", Level: "lvl", Message: "message"}
+	transport := &recordingTransport{}
+	exporter := NewOTLPExporter(OTLPConfig{Transport: transport, KeyNames: names})
+	zapLogger := NewZap(Config{
+		Level:         InfoLevel,
+		Output:        exporter,
+		KeyNames:      names,
+		Severity:      SeverityOnly,
+		DisableCaller: true,
+	})
+
+	zapLogger.Warn("Warn message", Fields{"key": "value"})
+
+	if len(transport.requests) != 1 {
+		t.Fatalf("Expected 1 export, got %d", len(transport.requests))
+	}
+	record := transport.requests[0].ResourceLogs[0].ScopeLogs[0].LogRecords[0]
+	if record.SeverityNumber != 13 || record.SeverityText != "warn" {
+		t.Errorf("Expected severity 13/warn, got %d/%s", record.SeverityNumber, record.SeverityText)
+	}
+	if record.Body.StringValue == nil || *record.Body.StringValue != "Warn message" {
+		t.Errorf("Expected body %q, got %+v", "Warn message", record.Body)
+	}
+	if record.TimeUnixNano == "" {
+		t.Errorf("Expected timeUnixNano to be set")
+	}
+	if len(record.Attributes) != 1 || record.Attributes[0].Key != "key" {
+		t.Errorf("Expected only the key attribute, got %+v", record.Attributes)
+	}
+}
+
+type recordingTransport struct {
+	requests []*OTLPRequest
+	// err, if set, fails the exports without recording them.
+	err error
+}
+
+func (r *recordingTransport) Export(_ context.Context, request *OTLPRequest) error {
+	if r.err != nil {
+		return r.err
+	}
+	r.requests = append(r.requests, request)
+	return nil
+}
+
+// TestNewOTLPExporterE tests that invalid endpoints are rejected up front.
+func TestNewOTLPExporterE(t *testing.T) {
+	for _, endpoint := range []string{"", "localhost:4318", "ftp://collector/v1/logs"} {
+		if _, err := NewOTLPExporterE(OTLPConfig{Endpoint: endpoint}); err == nil {
+			t.Errorf("Expected an error for endpoint %q, got nil", endpoint)
+		}
+	}
+	if _, err := NewOTLPExporterE(OTLPConfig{Endpoint: "http://localhost:4318/v1/logs"}); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if _, err := NewOTLPExporterE(OTLPConfig{Transport: &recordingTransport{}}); err != nil {
+		t.Errorf("Unexpected error with a custom transport: %v", err)
+	}
+}
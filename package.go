@@ -0,0 +1,140 @@
+package logger
+
+import (
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// packageLevel is a level override for an import path prefix.
+type packageLevel struct {
+	prefix string
+	level  Level
+}
+
+// packageLevels is an immutable set of overrides, longest prefix first.
+type packageLevels struct {
+	entries []packageLevel
+	// floor is the lowest overriding level.
+	floor Level
+}
+
+// newPackageLevels returns the overrides for levels.
+func newPackageLevels(levels map[string]Level) *packageLevels {
+	p := &packageLevels{floor: FatalLevel}
+	for prefix, level := range levels {
+		p.entries = append(p.entries, packageLevel{prefix: prefix, level: level})
+		if level < p.floor {
+			p.floor = level
+		}
+	}
+	sort.Slice(p.entries, func(i, j int) bool {
+		return len(p.entries[i].prefix) > len(p.entries[j].prefix)
+	})
+	return p
+}
+
+// lookup returns the level for pkg and whether it is overridden.
+func (p *packageLevels) lookup(pkg string) (Level, bool) {
+	for _, entry := range p.entries {
+		if pkg == entry.prefix || strings.HasPrefix(pkg, entry.prefix+"/") {
+			return entry.level, true
+		}
+	}
+	return 0, false
+}
+
+// levelsMap returns the overrides as a map.
+func (p *packageLevels) levelsMap() map[string]Level {
+	levels := make(map[string]Level, len(p.entries))
+	for _, entry := range p.entries {
+		levels[entry.prefix] = entry.level
+	}
+	return levels
+}
+
+// SetPackageLevel overrides the level of entries logged from the package with
+// import path prefix and the packages below it, e.g. to enable debug logging
+// for "github.com/org/app/internal/db" at runtime.
+func (z *Zap) SetPackageLevel(prefix string, level Level) {
+	z.state.mu.Lock()
+	defer z.state.mu.Unlock()
+	levels := z.state.packages.Load().(*packageLevels).levelsMap()
+	levels[prefix] = level
+	z.state.packages.Store(newPackageLevels(levels))
+}
+
+// RemovePackageLevel removes the level override for prefix.
+func (z *Zap) RemovePackageLevel(prefix string) {
+	z.state.mu.Lock()
+	defer z.state.mu.Unlock()
+	levels := z.state.packages.Load().(*packageLevels).levelsMap()
+	delete(levels, prefix)
+	z.state.packages.Store(newPackageLevels(levels))
+}
+
+// Enabled implements zapcore.LevelEnabler for the cores: it enables the
+// logger's level and, while overrides exist, the lowest overriding level, so
+// that the final decision is left to levelEnabled.
+func (s *state) Enabled(l zapcore.Level) bool {
+	if s.level.Enabled(l) {
+		return true
+	}
+	p := s.packages.Load().(*packageLevels)
+	return len(p.entries) > 0 && l >= p.floor.zapLevel()
+}
+
+// callerLevel returns the level override for the package of the caller of the
+// public logging method, and whether there is one. It must be called directly
+// from log.
+func (z *Zap) callerLevel() (Level, bool) {
+	p := z.state.packages.Load().(*packageLevels)
+	if len(p.entries) == 0 {
+		return 0, false
+	}
+	pc, _, _, ok := runtime.Caller(3 + z.Config.CallerSkip + z.skip)
+	if !ok {
+		return 0, false
+	}
+	return p.lookup(callerPackage(pc))
+}
+
+// levelEnabled reports whether an entry at level is logged, given the level
+// override for its caller's package, if any.
+func (z *Zap) levelEnabled(level Level, override Level, overridden bool) bool {
+	if !overridden {
+		return z.shouldLog(level)
+	}
+	return level >= override && atomic.LoadUint32(&z.state.closed) == 0
+}
+
+// callerPackages caches the import path of the function at each program counter.
+var callerPackages sync.Map
+
+// callerPackage returns the import path of the package of the function at pc.
+func callerPackage(pc uintptr) string {
+	if pkg, ok := callerPackages.Load(pc); ok {
+		return pkg.(string)
+	}
+	pkg := ""
+	if fn := runtime.FuncForPC(pc); fn != nil {
+		pkg = packageOf(fn.Name())
+	}
+	callerPackages.Store(pc, pkg)
+	return pkg
+}
+
+// packageOf returns the import path in a fully qualified function name such as
+// "github.com/org/app/internal/db.(*Store).Get". Dots in the last path
+// element are escaped as %2e by the runtime.
+func packageOf(funcName string) string {
+	slash := strings.LastIndex(funcName, "/")
+	if dot := strings.Index(funcName[slash+1:], "."); dot >= 0 {
+		funcName = funcName[:slash+1+dot]
+	}
+	return strings.Replace(funcName, "%2e", ".", -1)
+}
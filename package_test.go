@@ -0,0 +1,99 @@
+package logger
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+// TestZap_PackageLevels tests that levels are overridden by the caller's import path prefix.
+func TestZap_PackageLevels(t *testing.T) {
+	requireDebug(t)
+	buffer := new(bytes.Buffer)
+	zapLogger := NewZap(Config{
+		Level:         InfoLevel,
+		Output:        buffer,
+		PackageLevels: map[string]Level{"github.com/ralonr/log": DebugLevel},
+	})
+
+	zapLogger.Debug("Debug message", nil)
+	if buffer.Len() != 0 {
+		t.Errorf("Expected a partial path segment not to match, got %s", buffer.String())
+	}
+
+	zapLogger.SetPackageLevel("github.com/ralonr", DebugLevel)
+	zapLogger.Debug("Debug message", nil)
+	if !bytes.Contains(buffer.Bytes(), []byte(`"msg":"Debug message"`)) {
+		t.Errorf("Expected the debug entry to be logged, got %s", buffer.String())
+	}
+
+	buffer.Reset()
+	zapLogger.SetPackageLevel("github.com/ralonr/logger", ErrorLevel)
+	zapLogger.With(Fields{"key": "value"}).Warn("Warn message", nil)
+	if buffer.Len() != 0 {
+		t.Errorf("Expected the longest prefix to win, got %s", buffer.String())
+	}
+
+	zapLogger.RemovePackageLevel("github.com/ralonr/logger")
+	zapLogger.RemovePackageLevel("github.com/ralonr")
+	zapLogger.Debug("Debug message", nil)
+	zapLogger.Info("Info message", nil)
+	if bytes.Contains(buffer.Bytes(), []byte("Debug message")) || !bytes.Contains(buffer.Bytes(), []byte("Info message")) {
+		t.Errorf("Expected the logger's level once overrides are removed, got %s", buffer.String())
+	}
+}
+
+// TestZap_PackageLevelsDefault tests that overrides resolve the caller through the package-level functions.
+func TestZap_PackageLevelsDefault(t *testing.T) {
+	requireDebug(t)
+	defer SetDefault(Default())
+	buffer := new(bytes.Buffer)
+	SetDefault(NewZap(Config{Level: ErrorLevel, Output: buffer, PackageLevels: map[string]Level{"github.com/ralonr/logger": DebugLevel}}))
+
+	Debug("Debug message", nil)
+	if !bytes.Contains(buffer.Bytes(), []byte("package_test.go")) {
+		t.Errorf("Expected the debug entry from this package, got %s", buffer.String())
+	}
+}
+
+// TestPackageOf tests that import paths are extracted from function names.
+func TestPackageOf(t *testing.T) {
+	tests := map[string]string{
+		"github.com/org/app/internal/db.(*Store).Get": "github.com/org/app/internal/db",
+		"github.com/org/app.main.func1":               "github.com/org/app",
+		"main.main":                                   "main",
+		"gopkg.in/yaml%2ev3.Unmarshal":                "gopkg.in/yaml.v3",
+	}
+	for name, expected := range tests {
+		if got := packageOf(name); got != expected {
+			t.Errorf("Expected %s for %s, got %s", expected, name, got)
+		}
+	}
+}
+
+// TestZap_PackageLevelsLimited tests that overrides apply to Once and Every.
+func TestZap_PackageLevelsLimited(t *testing.T) {
+	requireDebug(t)
+	buffer := new(bytes.Buffer)
+	zapLogger := NewZap(Config{Level: InfoLevel, Output: buffer, PackageLevels: map[string]Level{"github.com/ralonr/logger": DebugLevel}})
+
+	for i := 0; i < 3; i++ {
+		zapLogger.Once().Debug("Debug once", nil)
+	}
+	if n := bytes.Count(buffer.Bytes(), []byte("Debug once")); n != 1 {
+		t.Errorf("Expected the override to enable one debug entry, got %d", n)
+	}
+
+	buffer.Reset()
+	zapLogger.SetPackageLevel("github.com/ralonr/logger", ErrorLevel)
+	limited := zapLogger.Every(time.Hour)
+	for i := 0; i < 2; i++ {
+		limited.Warn("Warn every", nil)
+		if i == 0 {
+			zapLogger.RemovePackageLevel("github.com/ralonr/logger")
+		}
+	}
+	if n := bytes.Count(buffer.Bytes(), []byte("Warn every")); n != 1 {
+		t.Errorf("Expected a suppressed call not to consume the allowance, got %d entries", n)
+	}
+}
@@ -0,0 +1,143 @@
+package logger
+
+import (
+	"sync/atomic"
+)
+
+// Stage is a step of a Pipeline. It receives an entry and next, the rest of the
+// pipeline, and may modify the entry, drop it by not calling next, or pass it
+// on, more than once or to another logger. Like hooks, stages must be safe for
+// concurrent use, must not modify the Fields map in place, and must not retain
+// the entry after they return.
+type Stage func(entry *Entry, next func(*Entry))
+
+// Pipeline is an ordered chain of stages run on every entry that passes the
+// level check and the hooks, before it is sampled, encoded, and written to the
+// sinks. The built-in stages are meant to be arranged enrichers, then hooks,
+// then filters, then sampling, e.g.
+//
+//	NewPipeline(
+//		EnrichStage(tenantFields),
+//		HookStage(redact),
+//		FilterStage(dropHealthChecks),
+//		SampleStage(10),
+//		RouteStage(tenantOf, tenantLoggers),
+//	)
+//
+// but custom stages can be placed anywhere.
+type Pipeline struct {
+	stages []Stage
+}
+
+// NewPipeline returns a pipeline running stages in order.
+func NewPipeline(stages ...Stage) *Pipeline {
+	return &Pipeline{stages: append([]Stage(nil), stages...)}
+}
+
+// Append returns a new pipeline running the stages of p followed by stages.
+func (p *Pipeline) Append(stages ...Stage) *Pipeline {
+	return NewPipeline(append(append([]Stage(nil), p.stages...), stages...)...)
+}
+
+// Run passes entry through the stages, calling final with every entry that
+// leaves the last one.
+func (p *Pipeline) Run(entry *Entry, final func(*Entry)) {
+	p.step(0, entry, final)
+}
+
+// step runs the stage at index i.
+func (p *Pipeline) step(i int, entry *Entry, final func(*Entry)) {
+	if i == len(p.stages) {
+		final(entry)
+		return
+	}
+	p.stages[i](entry, func(e *Entry) {
+		p.step(i+1, e, final)
+	})
+}
+
+// collect returns copies of the entries that leave the pipeline.
+func (p *Pipeline) collect(entry Entry) []Entry {
+	var out []Entry
+	p.Run(&entry, func(e *Entry) {
+		out = append(out, *e)
+	})
+	return out
+}
+
+// EnrichStage returns a stage adding the fields returned by fields to each
+// entry. Fields already on the entry take precedence.
+func EnrichStage(fields func(*Entry) Fields) Stage {
+	return func(entry *Entry, next func(*Entry)) {
+		if extra := fields(entry); len(extra) > 0 {
+			merged := make(Fields, len(entry.Fields)+len(extra))
+			for k, v := range extra {
+				merged[k] = v
+			}
+			for k, v := range entry.Fields {
+				merged[k] = v
+			}
+			entry.Fields = merged
+		}
+		next(entry)
+	}
+}
+
+// HookStage returns a stage running hooks in order. Hook errors do not stop the
+// entry and are not reported; register hooks with AddHook to have them reported.
+func HookStage(hooks ...Hook) Stage {
+	return func(entry *Entry, next func(*Entry)) {
+		runHooks(hooks, entry, func(error) {})
+		next(entry)
+	}
+}
+
+// FilterStage returns a stage dropping the entries for which keep returns false.
+func FilterStage(keep func(*Entry) bool) Stage {
+	return func(entry *Entry, next func(*Entry)) {
+		if keep(entry) {
+			next(entry)
+		}
+	}
+}
+
+// SampleStage returns a stage keeping the first of every n entries. Entries at
+// ErrorLevel and above are always kept. An n below 2 keeps every entry.
+func SampleStage(n uint64) Stage {
+	var count uint64
+	return func(entry *Entry, next func(*Entry)) {
+		if n < 2 || entry.Level >= ErrorLevel || (atomic.AddUint64(&count, 1)-1)%n == 0 {
+			next(entry)
+		}
+	}
+}
+
+// RouteStage returns a stage writing each entry to the logger in routes named by
+// key, e.g. a tenant ID taken from its fields, in place of the rest of the
+// pipeline. Entries whose key has no route continue down the pipeline.
+func RouteStage(key func(*Entry) string, routes map[string]Logger) Stage {
+	return func(entry *Entry, next func(*Entry)) {
+		l, ok := routes[key(entry)]
+		if !ok {
+			next(entry)
+			return
+		}
+		logEntry(l, entry)
+	}
+}
+
+// logEntry writes entry to l at its level.
+func logEntry(l Logger, entry *Entry) {
+	switch entry.Level {
+	case DebugLevel:
+		l.Debug(entry.Message, entry.Fields)
+	case InfoLevel:
+		l.Info(entry.Message, entry.Fields)
+	case WarnLevel:
+		l.Warn(entry.Message, entry.Fields)
+	case ErrorLevel:
+		l.Error(entry.Message, entry.Fields)
+	default:
+		l.Fatal(entry.Message, entry.Fields)
+	}
+}
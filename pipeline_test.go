@@ -0,0 +1,110 @@
+package logger
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// TestPipeline tests that stages run in order and can modify, drop, and route entries.
+func TestPipeline(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	tenant := new(bytes.Buffer)
+	routed := NewZap(Config{Level: InfoLevel, Output: tenant, DisableCaller: true})
+
+	pipeline := NewPipeline(
+		EnrichStage(func(entry *Entry) Fields {
+			return Fields{"service": "api", "tenant": "default"}
+		}),
+		HookStage(func(entry *Entry) error {
+			entry.Message = strings.ToUpper(entry.Message)
+			return nil
+		}),
+		FilterStage(func(entry *Entry) bool {
+			return entry.Fields["path"] != "/healthz"
+		}),
+		RouteStage(func(entry *Entry) string {
+			tenant, _ := entry.Fields["tenant"].(string)
+			return tenant
+		}, map[string]Logger{"acme": routed}),
+	)
+	log := NewZap(Config{Level: InfoLevel, Output: buffer, DisableCaller: true, Pipeline: pipeline})
+
+	log.Info("request", Fields{"path": "/orders"})
+	log.Info("request", Fields{"path": "/healthz"})
+	log.Info("request", Fields{"path": "/orders", "tenant": "acme"})
+
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("Expected 1 entry in the main output, got %d: %s", len(lines), buffer.String())
+	}
+	for _, want := range []string{`"msg":"REQUEST"`, `"service":"api"`, `"tenant":"default"`, `"path":"/orders"`} {
+		if !strings.Contains(lines[0], want) {
+			t.Errorf("Expected %s to contain %s", lines[0], want)
+		}
+	}
+	if !strings.Contains(tenant.String(), `"tenant":"acme"`) || strings.Count(tenant.String(), "\n") != 1 {
+		t.Errorf("Expected the acme entry to be routed, got %s", tenant.String())
+	}
+}
+
+// TestPipeline_Caller tests that the caller is reported through the pipeline.
+func TestPipeline_Caller(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	log := NewZap(Config{Level: InfoLevel, Output: buffer, Pipeline: NewPipeline(func(entry *Entry, next func(*Entry)) {
+		next(entry)
+		next(entry)
+	})})
+
+	log.Info("twice", nil)
+	if strings.Count(buffer.String(), "pipeline_test.go") != 2 {
+		t.Errorf("Expected 2 entries with the caller pipeline_test.go, got %s", buffer.String())
+	}
+}
+
+// TestPipeline_Level tests that entries raised below the level by a stage are dropped.
+func TestPipeline_Level(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	log := NewZap(Config{Level: InfoLevel, Output: buffer, Pipeline: NewPipeline(func(entry *Entry, next func(*Entry)) {
+		entry.Level = DebugLevel
+		next(entry)
+	})})
+
+	log.Info("demoted", nil)
+	if buffer.Len() != 0 {
+		t.Errorf("Expected no output, got %s", buffer.String())
+	}
+}
+
+// TestSampleStage tests that every nth entry is kept and errors are always kept.
+func TestSampleStage(t *testing.T) {
+	pipeline := NewPipeline(SampleStage(3))
+	kept := 0
+	for i := 0; i < 9; i++ {
+		pipeline.Run(&Entry{Level: InfoLevel}, func(*Entry) { kept++ })
+	}
+	if kept != 3 {
+		t.Errorf("Expected 3 entries to be kept, got %d", kept)
+	}
+
+	kept = 0
+	for i := 0; i < 3; i++ {
+		pipeline.Run(&Entry{Level: ErrorLevel}, func(*Entry) { kept++ })
+	}
+	if kept != 3 {
+		t.Errorf("Expected every error to be kept, got %d", kept)
+	}
+}
+
+// TestPipeline_Append tests that Append leaves the original pipeline unchanged.
+func TestPipeline_Append(t *testing.T) {
+	base := NewPipeline()
+	drop := base.Append(FilterStage(func(*Entry) bool { return false }))
+
+	kept := 0
+	base.Run(&Entry{}, func(*Entry) { kept++ })
+	drop.Run(&Entry{}, func(*Entry) { kept++ })
+	if kept != 1 {
+		t.Errorf("Expected 1 entry to be kept, got %d", kept)
+	}
+}
@@ -0,0 +1,45 @@
+package logger
+
+import (
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+// maxPooledFields is the capacity above which field buffers are not returned to
+// the pool, so that one unusually large entry does not pin memory.
+const maxPooledFields = 64
+
+// fieldPool holds reusable buffers for converting Fields to zap fields.
+var fieldPool = sync.Pool{
+	New: func() interface{} {
+		buf := make([]zap.Field, 0, 16)
+		return &buf
+	},
+}
+
+// entryPool holds reusable entries passed to hooks.
+var entryPool = sync.Pool{
+	New: func() interface{} {
+		return new(Entry)
+	},
+}
+
+// getFieldBuffer returns an empty field buffer from the pool.
+func getFieldBuffer() *[]zap.Field {
+	buf := fieldPool.Get().(*[]zap.Field)
+	*buf = (*buf)[:0]
+	return buf
+}
+
+// putFieldBuffer clears buf and returns it to the pool.
+func putFieldBuffer(buf *[]zap.Field) {
+	if cap(*buf) > maxPooledFields {
+		return
+	}
+	for i := range *buf {
+		(*buf)[i] = zap.Field{}
+	}
+	*buf = (*buf)[:0]
+	fieldPool.Put(buf)
+}
@@ -0,0 +1,23 @@
+package logger
+
+import (
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+// TestFieldBufferPool tests that pooled field buffers are returned empty.
+func TestFieldBufferPool(t *testing.T) {
+	buf := getFieldBuffer()
+	*buf = append(*buf, zap.String("key", "value"))
+	putFieldBuffer(buf)
+
+	buf = getFieldBuffer()
+	if len(*buf) != 0 {
+		t.Errorf("Expected an empty buffer, got %d fields", len(*buf))
+	}
+	if full := (*buf)[:cap(*buf)]; len(full) > 0 && full[0].Key != "" {
+		t.Errorf("Expected pooled fields to be cleared, got %+v", full[0])
+	}
+	putFieldBuffer(buf)
+}
@@ -0,0 +1,149 @@
+package logger
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// Pinger is implemented by outputs that can actively verify their backend,
+// e.g. by resolving and connecting to a host or authenticating, without
+// writing an entry. Custom transports can implement it to take part in
+// Preflight.
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// PreflightCheck is the result of a single preflight check.
+type PreflightCheck struct {
+	// Name identifies the check: "config", "output", "destination[i]", or
+	// "event output", followed by the output's type.
+	Name string
+	// Err is nil if the check passed.
+	Err error
+}
+
+// PreflightReport is the result of Preflight.
+type PreflightReport struct {
+	Checks []PreflightCheck
+}
+
+// OK reports whether every check passed.
+func (r PreflightReport) OK() bool {
+	return r.Err() == nil
+}
+
+// Err returns an error describing the failed checks, or nil if all passed.
+func (r PreflightReport) Err() error {
+	var failures []string
+	for _, check := range r.Checks {
+		if check.Err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", check.Name, check.Err))
+		}
+	}
+	if len(failures) == 0 {
+		return nil
+	}
+	return fmt.Errorf("logging preflight failed: %s", strings.Join(failures, "; "))
+}
+
+// MarshalJSON writes the report as {"ok":..., "checks":[{"name":..., "ok":..., "error":...}]}
+// for deploy tooling.
+func (r PreflightReport) MarshalJSON() ([]byte, error) {
+	type check struct {
+		Name  string `json:"name"`
+		OK    bool   `json:"ok"`
+		Error string `json:"error,omitempty"`
+	}
+	checks := make([]check, 0, len(r.Checks))
+	for _, c := range r.Checks {
+		entry := check{Name: c.Name, OK: c.Err == nil}
+		if c.Err != nil {
+			entry.Error = c.Err.Error()
+		}
+		checks = append(checks, entry)
+	}
+	return json.Marshal(struct {
+		OK     bool    `json:"ok"`
+		Checks []check `json:"checks"`
+	}{r.OK(), checks})
+}
+
+// Preflight checks config and every output it names without logging, for use
+// in deploy preflight checks: the configuration is validated, OutputPath is
+// opened (creating the file or connecting to the socket) and closed again, and
+// each output is pinged if it implements Pinger, or else health-checked if it
+// implements HealthChecker.
+func Preflight(ctx context.Context, config Config) PreflightReport {
+	var report PreflightReport
+	add := func(name string, err error) {
+		report.Checks = append(report.Checks, PreflightCheck{Name: name, Err: err})
+	}
+	check := func(name string, w io.Writer) {
+		add(fmt.Sprintf("%s (%T)", name, w), pingOutput(ctx, w))
+	}
+
+	add("config", validateConfig(config))
+
+	output, err := openOutput(config)
+	switch {
+	case err != nil:
+		add("output", err)
+	case output != nil:
+		check("output", output)
+		if config.Output == nil && output != os.Stdout && output != os.Stderr {
+			if c, ok := output.(io.Closer); ok {
+				c.Close()
+			}
+		}
+	}
+	for i, dest := range config.Destinations {
+		check(fmt.Sprintf("destination[%d]", i), dest.Output)
+	}
+	if config.EventOutput != nil {
+		check("event output", config.EventOutput)
+	}
+	return report
+}
+
+// pingOutput pings w if it implements Pinger, and otherwise checks its health
+// if it implements HealthChecker.
+func pingOutput(ctx context.Context, w io.Writer) error {
+	if pinger, ok := w.(Pinger); ok {
+		return pinger.Ping(ctx)
+	}
+	if checker, ok := w.(HealthChecker); ok {
+		return checker.Health(ctx)
+	}
+	return nil
+}
+
+// pingURL resolves the host of endpoint and opens a TCP connection to it.
+func pingURL(ctx context.Context, endpoint string) error {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return err
+	}
+	if u.Host == "" {
+		return fmt.Errorf("invalid endpoint %q", endpoint)
+	}
+	host := u.Host
+	if u.Port() == "" {
+		port := "80"
+		if u.Scheme == "https" {
+			port = "443"
+		}
+		host = net.JoinHostPort(u.Hostname(), port)
+	}
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", host)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
@@ -0,0 +1,84 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// failingPinger is an output whose backend cannot be reached.
+type failingPinger struct {
+	bytes.Buffer
+}
+
+func (p *failingPinger) Ping(ctx context.Context) error {
+	return errors.New("authentication failed")
+}
+
+// TestPreflight tests that outputs are checked without logging.
+func TestPreflight(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Expected no export, got %s %s", r.Method, r.URL)
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "app.log")
+	events := new(bytes.Buffer)
+	report := Preflight(context.Background(), Config{
+		OutputPath:   path,
+		Destinations: []Destination{{Output: NewOTLPExporter(OTLPConfig{Endpoint: server.URL + "/v1/logs"})}},
+		EventOutput:  events,
+	})
+	if !report.OK() {
+		t.Errorf("Expected the preflight to pass, got %v", report.Err())
+	}
+	if len(report.Checks) != 4 {
+		t.Errorf("Expected 4 checks, got %d", len(report.Checks))
+	}
+	if events.Len() != 0 {
+		t.Errorf("Expected nothing to be written, got %s", events.String())
+	}
+}
+
+// TestPreflight_Failures tests that failed checks are reported.
+func TestPreflight_Failures(t *testing.T) {
+	report := Preflight(context.Background(), Config{
+		Level:        FatalLevel + 1,
+		OutputPath:   filepath.Join(t.TempDir(), "missing", "app.log"),
+		Destinations: []Destination{{Output: &failingPinger{}}},
+	})
+	if report.OK() {
+		t.Fatalf("Expected the preflight to fail")
+	}
+	err := report.Err().Error()
+	for _, expected := range []string{"config: invalid level", "output: open output", "destination[0] (*logger.failingPinger): authentication failed"} {
+		if !strings.Contains(err, expected) {
+			t.Errorf("Expected %s to contain %s", err, expected)
+		}
+	}
+
+	data, jsonErr := json.Marshal(report)
+	if jsonErr != nil {
+		t.Fatalf("Unexpected error: %v", jsonErr)
+	}
+	if !bytes.HasPrefix(data, []byte(`{"ok":false,"checks":[{"name":"config","ok":false,"error":"invalid level`)) {
+		t.Errorf("Expected a structured report, got %s", data)
+	}
+}
+
+// TestOTLPExporter_Ping tests that an unreachable collector fails the ping.
+func TestOTLPExporter_Ping(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	endpoint := server.URL + "/v1/logs"
+	server.Close()
+
+	if err := NewOTLPExporter(OTLPConfig{Endpoint: endpoint}).Ping(context.Background()); err == nil {
+		t.Errorf("Expected an error for an unreachable collector")
+	}
+}
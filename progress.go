@@ -0,0 +1,159 @@
+package logger
+
+import (
+	"sync"
+	"time"
+)
+
+// ProgressConfig holds the configuration for a Progress.
+type ProgressConfig struct {
+	// Logger receives the heartbeat entries.
+	Logger Logger
+	// Message is the message of heartbeat entries; defaults to "progress".
+	// The final entry written by Done has " done" appended.
+	Message string
+	// Total is the number of items expected, used for the percentage and ETA.
+	// Zero means unknown.
+	Total int64
+	// Interval is the minimum time between heartbeats; defaults to 10 seconds.
+	Interval time.Duration
+	// Level is the level of heartbeat entries; defaults to InfoLevel.
+	Level Level
+	// Fields are added to every heartbeat, e.g. the job name.
+	Fields Fields
+	// Clock times the heartbeats; defaults to the system clock.
+	Clock Clock
+}
+
+// Progress logs heartbeat entries for a long-running job, with the items
+// processed, the rate since the previous heartbeat, and the ETA when the total
+// is known. Heartbeats are driven by Observe, so no goroutine or ticker is
+// needed:
+//
+//	progress := logger.NewProgress(logger.ProgressConfig{Logger: log, Message: "import", Total: n})
+//	for _, row := range rows {
+//		process(row)
+//		progress.Observe(1)
+//	}
+//	progress.Done()
+//
+// A Progress is safe for concurrent use.
+type Progress struct {
+	config ProgressConfig
+	mu     sync.Mutex
+	start  time.Time
+	last   time.Time
+	count  int64
+	// lastCount is the count at the previous heartbeat.
+	lastCount int64
+	done      bool
+}
+
+// NewProgress returns a new *Progress, starting its clock.
+func NewProgress(config ProgressConfig) *Progress {
+	if config.Message == "" {
+		config.Message = "progress"
+	}
+	if config.Interval <= 0 {
+		config.Interval = 10 * time.Second
+	}
+	if config.Level == DebugLevel {
+		config.Level = InfoLevel
+	}
+	p := &Progress{config: config}
+	p.start = p.now()
+	p.last = p.start
+	return p
+}
+
+// Observe records n more processed items and logs a heartbeat if Interval has
+// passed since the previous one.
+func (p *Progress) Observe(n int64) {
+	p.mu.Lock()
+	p.count += n
+	now := p.now()
+	if p.done || now.Sub(p.last) < p.config.Interval {
+		p.mu.Unlock()
+		return
+	}
+	entry := p.entry(now)
+	p.mu.Unlock()
+
+	logEntry(p.config.Logger, entry)
+}
+
+// Count returns the number of items processed so far.
+func (p *Progress) Count() int64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.count
+}
+
+// Done logs a final entry with the items processed, the elapsed time, and the
+// average rate. Later calls to Observe and Done log nothing.
+func (p *Progress) Done() {
+	p.mu.Lock()
+	if p.done {
+		p.mu.Unlock()
+		return
+	}
+	p.done = true
+	now := p.now()
+	elapsed := now.Sub(p.start)
+	entry := &Entry{Level: p.config.Level, Time: now, Message: p.config.Message + " done", Fields: p.fields(4)}
+	entry.Fields["processed"] = p.count
+	entry.Fields["elapsed"] = elapsed
+	entry.Fields["rate"] = perSecond(p.count, elapsed)
+	if p.config.Total > 0 {
+		entry.Fields["total"] = p.config.Total
+	}
+	p.mu.Unlock()
+
+	logEntry(p.config.Logger, entry)
+}
+
+// entry builds a heartbeat and starts the next interval. The caller must hold
+// p.mu.
+func (p *Progress) entry(now time.Time) *Entry {
+	delta := p.count - p.lastCount
+	current := perSecond(delta, now.Sub(p.last))
+	entry := &Entry{Level: p.config.Level, Time: now, Message: p.config.Message, Fields: p.fields(7)}
+	entry.Fields["processed"] = p.count
+	entry.Fields["delta"] = delta
+	entry.Fields["rate"] = current
+	entry.Fields["elapsed"] = now.Sub(p.start)
+	if total := p.config.Total; total > 0 {
+		entry.Fields["total"] = total
+		entry.Fields["percent"] = float64(p.count) * 100 / float64(total)
+		if remaining := total - p.count; remaining > 0 && current > 0 {
+			entry.Fields["eta"] = time.Duration(float64(remaining) / current * float64(time.Second))
+		}
+	}
+	p.last, p.lastCount = now, p.count
+	return entry
+}
+
+// fields returns a copy of the configured fields with room for n more.
+func (p *Progress) fields(n int) Fields {
+	fields := make(Fields, len(p.config.Fields)+n)
+	for k, v := range p.config.Fields {
+		fields[k] = v
+	}
+	return fields
+}
+
+// now returns the current time from the configured clock.
+func (p *Progress) now() time.Time {
+	if p.config.Clock != nil {
+		return p.config.Clock.Now()
+	}
+	return time.Now()
+}
+
+// perSecond returns n per second over d, or zero if d is not positive.
+func perSecond(n int64, d time.Duration) float64 {
+	if d <= 0 {
+		return 0
+	}
+	return float64(n) / d.Seconds()
+}
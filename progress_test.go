@@ -0,0 +1,73 @@
+package logger
+
+import (
+	"testing"
+	"time"
+)
+
+// TestProgress tests that heartbeats are logged once per interval with the
+// rate and ETA.
+func TestProgress(t *testing.T) {
+	observed, logs := Observe(Config{Level: DebugLevel})
+	clock := &manualClock{now: time.Date(2024, 5, 1, 13, 0, 0, 0, time.UTC)}
+	progress := NewProgress(ProgressConfig{
+		Logger:   observed,
+		Message:  "import",
+		Total:    100,
+		Interval: 10 * time.Second,
+		Fields:   Fields{"job": "import"},
+		Clock:    clock,
+	})
+
+	progress.Observe(10)
+	clock.now = clock.now.Add(5 * time.Second)
+	progress.Observe(10)
+	if logs.Len() != 0 {
+		t.Fatalf("Expected no heartbeat before the interval, got %v", logs.All())
+	}
+
+	clock.now = clock.now.Add(5 * time.Second)
+	progress.Observe(20)
+	entries := logs.All()
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 heartbeat, got %d", len(entries))
+	}
+	entry := entries[0]
+	if entry.Level != InfoLevel || entry.Message != "import" {
+		t.Errorf("Expected an Info heartbeat named import, got %v %q", entry.Level, entry.Message)
+	}
+	for key, expected := range map[string]interface{}{
+		"job":       "import",
+		"processed": int64(40),
+		"delta":     int64(40),
+		"rate":      4.0,
+		"total":     int64(100),
+		"percent":   40.0,
+		"eta":       15 * time.Second,
+		"elapsed":   10 * time.Second,
+	} {
+		if entry.Fields[key] != expected {
+			t.Errorf("Expected %s to be %v, got %v", key, expected, entry.Fields[key])
+		}
+	}
+
+	clock.now = clock.now.Add(10 * time.Second)
+	progress.Observe(10)
+	if fields := logs.All()[1].Fields; fields["delta"] != int64(10) || fields["rate"] != 1.0 {
+		t.Errorf("Expected the rate since the previous heartbeat, got %v", fields)
+	}
+
+	progress.Done()
+	progress.Done()
+	progress.Observe(1)
+	entries = logs.All()
+	if len(entries) != 3 {
+		t.Fatalf("Expected a single final entry, got %d entries", len(entries))
+	}
+	if entries[2].Message != "import done" || entries[2].Fields["processed"] != int64(50) || entries[2].Fields["rate"] != 2.5 {
+		t.Errorf("Expected the final entry with the average rate, got %q %v", entries[2].Message, entries[2].Fields)
+	}
+	if progress.Count() != 51 {
+		t.Errorf("Expected a count of 51, got %d", progress.Count())
+	}
+}
@@ -0,0 +1,217 @@
+package logger
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+// Protobuf wire types.
+const (
+	protoVarint  = 0
+	protoFixed64 = 1
+	protoBytes   = 2
+)
+
+// Field numbers of opentelemetry.proto.logs.v1.LogRecord.
+const (
+	protoLogTime         = 1
+	protoLogSeverity     = 2
+	protoLogSeverityText = 3
+	protoLogBody         = 5
+	protoLogAttributes   = 6
+	protoLogObserved     = 11
+)
+
+// Field numbers of opentelemetry.proto.common.v1.AnyValue and KeyValue.
+const (
+	protoValueString = 1
+	protoValueBool   = 2
+	protoValueInt    = 3
+	protoValueDouble = 4
+	protoValueArray  = 5
+	protoValueKvlist = 6
+	protoValueBytes  = 7
+
+	protoKey   = 1
+	protoValue = 2
+	// protoListValues is the repeated field of ArrayValue and KeyValueList.
+	protoListValues = 1
+)
+
+// newProtobufCore returns a core writing each entry as an OpenTelemetry
+// LogRecord in protobuf encoding, prefixed with its length as a varint so
+// that records can be streamed. The message is the body, the level is the
+// severity, and the caller and fields are attributes.
+func newProtobufCore(encoderConfig zapcore.EncoderConfig, ws zapcore.WriteSyncer, enabler zapcore.LevelEnabler) zapcore.Core {
+	render := func(buf *buffer.Buffer, entry zapcore.Entry, fields map[string]interface{}) error {
+		if entry.Caller.Defined && encoderConfig.CallerKey != "" && encoderConfig.CallerKey != zapcore.OmitKey {
+			fields[encoderConfig.CallerKey] = entry.Caller.TrimmedPath()
+		}
+
+		var record []byte
+		record = appendProtoFixed64(record, protoLogTime, uint64(entry.Time.UnixNano()))
+		record = appendProtoVarint(record, protoLogSeverity, uint64(otlpSeverity[entry.Level.String()]))
+		record = appendProtoString(record, protoLogSeverityText, formatValue(encodePrimitive(func(enc zapcore.PrimitiveArrayEncoder) {
+			encoderConfig.EncodeLevel(entry.Level, enc)
+		})))
+		record = appendProtoBytes(record, protoLogBody, appendProtoString(nil, protoValueString, entry.Message))
+		for _, k := range sortedKeys(fields) {
+			kv, err := protoKeyValue(k, fields[k])
+			if err != nil {
+				return err
+			}
+			record = appendProtoBytes(record, protoLogAttributes, kv)
+		}
+		record = appendProtoFixed64(record, protoLogObserved, uint64(time.Now().UnixNano()))
+
+		buf.Write(appendUvarint(nil, uint64(len(record))))
+		buf.Write(record)
+		return nil
+	}
+	return newMapCore(render, ws, enabler)
+}
+
+// protoKeyValue returns the encoded KeyValue for a field.
+func protoKeyValue(key string, v interface{}) ([]byte, error) {
+	value, err := protoAnyValue(v)
+	if err != nil {
+		return nil, err
+	}
+	kv := appendProtoString(nil, protoKey, key)
+	return appendProtoBytes(kv, protoValue, value), nil
+}
+
+// protoAnyValue returns the encoded AnyValue for a collected field value.
+func protoAnyValue(v interface{}) ([]byte, error) {
+	switch val := v.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return appendProtoString(nil, protoValueString, val), nil
+	case bool:
+		b := uint64(0)
+		if val {
+			b = 1
+		}
+		return appendProtoVarint(nil, protoValueBool, b), nil
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, uintptr, time.Duration:
+		i, _ := protoInt(val)
+		return appendProtoVarint(nil, protoValueInt, uint64(i)), nil
+	case float32:
+		return appendProtoFixed64(nil, protoValueDouble, math.Float64bits(float64(val))), nil
+	case float64:
+		return appendProtoFixed64(nil, protoValueDouble, math.Float64bits(val)), nil
+	case []byte:
+		return appendProtoBytes(nil, protoValueBytes, val), nil
+	case time.Time:
+		return appendProtoString(nil, protoValueString, val.Format(time.RFC3339Nano)), nil
+	case []interface{}:
+		var list []byte
+		for _, item := range val {
+			value, err := protoAnyValue(item)
+			if err != nil {
+				return nil, err
+			}
+			list = appendProtoBytes(list, protoListValues, value)
+		}
+		return appendProtoBytes(nil, protoValueArray, list), nil
+	case map[string]interface{}:
+		var list []byte
+		for _, k := range sortedKeys(val) {
+			kv, err := protoKeyValue(k, val[k])
+			if err != nil {
+				return nil, err
+			}
+			list = appendProtoBytes(list, protoListValues, kv)
+		}
+		return appendProtoBytes(nil, protoValueKvlist, list), nil
+	}
+	generic, err := jsonGeneric(v)
+	if err != nil {
+		return nil, fmt.Errorf("protobuf: encode %T: %w", v, err)
+	}
+	if number, ok := generic.(json.Number); ok {
+		if i, err := number.Int64(); err == nil {
+			return appendProtoVarint(nil, protoValueInt, uint64(i)), nil
+		}
+		f, _ := number.Float64()
+		return protoAnyValue(f)
+	}
+	return protoAnyValue(generic)
+}
+
+// protoInt converts an integer field value to an int64.
+func protoInt(v interface{}) (int64, bool) {
+	switch val := v.(type) {
+	case int:
+		return int64(val), true
+	case int8:
+		return int64(val), true
+	case int16:
+		return int64(val), true
+	case int32:
+		return int64(val), true
+	case int64:
+		return val, true
+	case uint:
+		return int64(val), true
+	case uint8:
+		return int64(val), true
+	case uint16:
+		return int64(val), true
+	case uint32:
+		return int64(val), true
+	case uint64:
+		return int64(val), true
+	case uintptr:
+		return int64(val), true
+	case time.Duration:
+		return int64(val), true
+	}
+	return 0, false
+}
+
+// appendUvarint appends v as a varint.
+func appendUvarint(b []byte, v uint64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	return append(b, buf[:binary.PutUvarint(buf[:], v)]...)
+}
+
+// appendProtoTag appends the key of a field.
+func appendProtoTag(b []byte, field, wireType int) []byte {
+	return appendUvarint(b, uint64(field<<3|wireType))
+}
+
+// appendProtoVarint appends a varint field.
+func appendProtoVarint(b []byte, field int, v uint64) []byte {
+	b = appendProtoTag(b, field, protoVarint)
+	return appendUvarint(b, v)
+}
+
+// appendProtoFixed64 appends a fixed64 or double field.
+func appendProtoFixed64(b []byte, field int, v uint64) []byte {
+	b = appendProtoTag(b, field, protoFixed64)
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], v)
+	return append(b, buf[:]...)
+}
+
+// appendProtoBytes appends a length-delimited field.
+func appendProtoBytes(b []byte, field int, v []byte) []byte {
+	b = appendProtoTag(b, field, protoBytes)
+	b = appendUvarint(b, uint64(len(v)))
+	return append(b, v...)
+}
+
+// appendProtoString appends a string field.
+func appendProtoString(b []byte, field int, s string) []byte {
+	b = appendProtoTag(b, field, protoBytes)
+	b = appendUvarint(b, uint64(len(s)))
+	return append(b, s...)
+}
@@ -0,0 +1,83 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+	"time"
+)
+
+// protoFields decodes the top-level fields of a protobuf message, keeping
+// varints and fixed64 values as uint64 and length-delimited ones as []byte.
+func protoFields(t *testing.T, b []byte) map[int][]interface{} {
+	fields := make(map[int][]interface{})
+	for len(b) > 0 {
+		key, n := binary.Uvarint(b)
+		b = b[n:]
+		field := int(key >> 3)
+		switch key & 7 {
+		case protoVarint:
+			v, n := binary.Uvarint(b)
+			b = b[n:]
+			fields[field] = append(fields[field], v)
+		case protoFixed64:
+			fields[field] = append(fields[field], binary.LittleEndian.Uint64(b))
+			b = b[8:]
+		case protoBytes:
+			size, n := binary.Uvarint(b)
+			b = b[n:]
+			fields[field] = append(fields[field], b[:size])
+			b = b[size:]
+		default:
+			t.Fatalf("Unexpected wire type %d", key&7)
+		}
+	}
+	return fields
+}
+
+// TestFormat_Protobuf tests that entries are written as length-prefixed OTel LogRecords.
+func TestFormat_Protobuf(t *testing.T) {
+	output := new(bytes.Buffer)
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	zapLogger := NewZap(Config{
+		Level:         InfoLevel,
+		Output:        output,
+		Format:        FormatProtobuf,
+		DisableCaller: true,
+		Clock:         fixedClock(now),
+	})
+
+	zapLogger.Warn("Warn message", Fields{"n": 7, "s": "x"})
+
+	size, n := binary.Uvarint(output.Bytes())
+	if int(size) != output.Len()-n {
+		t.Fatalf("Expected a length prefix of %d, got %d", output.Len()-n, size)
+	}
+	record := protoFields(t, output.Bytes()[n:])
+
+	if record[protoLogTime][0] != uint64(now.UnixNano()) {
+		t.Errorf("Expected the entry time, got %v", record[protoLogTime])
+	}
+	if record[protoLogSeverity][0] != uint64(13) || string(record[protoLogSeverityText][0].([]byte)) != "warn" {
+		t.Errorf("Expected severity 13 warn, got %v %s", record[protoLogSeverity], record[protoLogSeverityText])
+	}
+	body := protoFields(t, record[protoLogBody][0].([]byte))
+	if string(body[protoValueString][0].([]byte)) != "Warn message" {
+		t.Errorf("Expected the message as body, got %s", body[protoValueString])
+	}
+
+	attributes := record[protoLogAttributes]
+	if len(attributes) != 2 {
+		t.Fatalf("Expected 2 attributes, got %d", len(attributes))
+	}
+	first := protoFields(t, attributes[0].([]byte))
+	value := protoFields(t, first[protoValue][0].([]byte))
+	if string(first[protoKey][0].([]byte)) != "n" || value[protoValueInt][0] != uint64(7) {
+		t.Errorf("Expected n=7, got %v", first)
+	}
+	second := protoFields(t, attributes[1].([]byte))
+	value = protoFields(t, second[protoValue][0].([]byte))
+	if string(second[protoKey][0].([]byte)) != "s" || string(value[protoValueString][0].([]byte)) != "x" {
+		t.Errorf("Expected s=x, got %v", second)
+	}
+}
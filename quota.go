@@ -0,0 +1,148 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// quotaEntryOverhead approximates the bytes an entry takes beyond its message
+// and fields: the timestamp, level, caller, and punctuation.
+const quotaEntryOverhead = 64
+
+// QuotaConfig holds the configuration for a Quota.
+type QuotaConfig struct {
+	// Bytes caps the bytes of all entries per window; zero is unlimited.
+	Bytes int64
+	// Levels caps the bytes of the entries at each listed level per window, in
+	// addition to Bytes.
+	Levels map[Level]int64
+	// Key, if set, applies the budgets separately to each value of this
+	// field, e.g. "component", so one runaway component cannot starve the
+	// others.
+	Key string
+	// Window is the interval the budgets apply to; defaults to one minute.
+	Window time.Duration
+	// Clock times the windows; defaults to the system clock.
+	Clock Clock
+}
+
+// QuotaStats counts the entries a Quota let through and dropped.
+type QuotaStats struct {
+	Entries        uint64
+	Bytes          uint64
+	DroppedEntries uint64
+	DroppedBytes   uint64
+}
+
+// Quota is a pipeline stage capping the bytes logged per window, protecting
+// ingestion bills from runaway logging. An entry's size is estimated as its
+// message and fields encoded as JSON plus a fixed overhead. Entries over
+// budget are dropped and counted; the next entry let through is preceded by a
+// warning with the number of entries and bytes dropped in between.
+type Quota struct {
+	config QuotaConfig
+	mu     sync.Mutex
+	start  time.Time
+	used   map[quotaKey]int64
+	// dropped counts the entries and bytes dropped since the last warning.
+	dropped      uint64
+	droppedBytes uint64
+	stats        QuotaStats
+}
+
+// quotaKey identifies a budget: the total (all is set) or a level, per key value.
+type quotaKey struct {
+	value string
+	level Level
+	all   bool
+}
+
+// NewQuota returns a new *Quota.
+func NewQuota(config QuotaConfig) *Quota {
+	if config.Window <= 0 {
+		config.Window = time.Minute
+	}
+	return &Quota{config: config, used: make(map[quotaKey]int64)}
+}
+
+// Stage returns the pipeline stage enforcing the quota.
+func (q *Quota) Stage() Stage {
+	return func(entry *Entry, next func(*Entry)) {
+		size := int64(entrySize(entry))
+		var value string
+		if q.config.Key != "" {
+			value = fmt.Sprint(entry.Fields[q.config.Key])
+		}
+
+		q.mu.Lock()
+		now := q.now()
+		if q.start.IsZero() || now.Sub(q.start) >= q.config.Window {
+			q.start = now
+			q.used = make(map[quotaKey]int64)
+		}
+		total := quotaKey{value: value, all: true}
+		level := quotaKey{value: value, level: entry.Level}
+		if q.over(total, q.config.Bytes, size) || q.over(level, q.config.Levels[entry.Level], size) {
+			q.dropped++
+			q.droppedBytes += uint64(size)
+			q.stats.DroppedEntries++
+			q.stats.DroppedBytes += uint64(size)
+			q.mu.Unlock()
+			return
+		}
+		q.used[total] += size
+		q.used[level] += size
+		q.stats.Entries++
+		q.stats.Bytes += uint64(size)
+		dropped, droppedBytes := q.dropped, q.droppedBytes
+		q.dropped, q.droppedBytes = 0, 0
+		q.mu.Unlock()
+
+		if dropped > 0 {
+			next(&Entry{
+				Level:   WarnLevel,
+				Time:    entry.Time,
+				Message: "log quota exceeded",
+				Fields:  Fields{"dropped_entries": dropped, "dropped_bytes": droppedBytes},
+			})
+		}
+		next(entry)
+	}
+}
+
+// over reports whether size does not fit the remaining budget of key; a zero
+// budget is unlimited. It must be called with mu held.
+func (q *Quota) over(key quotaKey, budget, size int64) bool {
+	return budget > 0 && q.used[key]+size > budget
+}
+
+// Stats returns the counts since the quota was created.
+func (q *Quota) Stats() QuotaStats {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.stats
+}
+
+// now returns the current time from the configured clock.
+func (q *Quota) now() time.Time {
+	if q.config.Clock != nil {
+		return q.config.Clock.Now()
+	}
+	return time.Now()
+}
+
+// entrySize estimates the encoded size of entry.
+func entrySize(entry *Entry) int {
+	size := quotaEntryOverhead + len(entry.Message)
+	for k, v := range entry.Fields {
+		size += len(k) + 4
+		if b, err := json.Marshal(v); err == nil {
+			size += len(b)
+		} else {
+			size += len(fmt.Sprint(v))
+		}
+	}
+	return size
+}
@@ -0,0 +1,58 @@
+package logger
+
+import (
+	"testing"
+	"time"
+)
+
+// TestQuota tests that entries over the byte budget are dropped until the next window.
+func TestQuota(t *testing.T) {
+	clock := &manualClock{now: time.Unix(0, 0)}
+	quota := NewQuota(QuotaConfig{Bytes: 250, Levels: map[Level]int64{DebugLevel: 100}, Window: time.Minute, Clock: clock})
+	pipeline := NewPipeline(quota.Stage())
+
+	var passed []Entry
+	run := func(level Level, msg string) {
+		pipeline.Run(&Entry{Level: level, Message: msg}, func(e *Entry) { passed = append(passed, *e) })
+	}
+
+	run(DebugLevel, "0123456789") // 74 bytes
+	run(DebugLevel, "0123456789") // over the debug budget
+	run(InfoLevel, "0123456789")  // preceded by a warning
+	run(InfoLevel, "0123456789")
+	run(InfoLevel, "0123456789") // over the total budget
+	if len(passed) != 4 {
+		t.Fatalf("Expected 3 entries within the budget and a warning, got %d", len(passed))
+	}
+	if w := passed[1]; w.Level != WarnLevel || w.Message != "log quota exceeded" || w.Fields["dropped_entries"] != uint64(1) || w.Fields["dropped_bytes"] != uint64(74) {
+		t.Errorf("Expected a warning with the dropped counts, got %+v", w)
+	}
+	stats := quota.Stats()
+	if stats.Entries != 3 || stats.DroppedEntries != 2 || stats.DroppedBytes != 148 {
+		t.Errorf("Expected 2 dropped entries of 148 bytes, got %+v", stats)
+	}
+
+	clock.now = clock.now.Add(time.Minute)
+	run(InfoLevel, "0123456789")
+	if len(passed) != 6 || passed[4].Level != WarnLevel || passed[5].Level != InfoLevel {
+		t.Errorf("Expected a warning and the entry in the next window, got %+v", passed[4:])
+	}
+}
+
+// TestQuota_Key tests that budgets apply separately to each value of the key field.
+func TestQuota_Key(t *testing.T) {
+	quota := NewQuota(QuotaConfig{Bytes: 100, Key: "component"})
+	pipeline := NewPipeline(quota.Stage())
+
+	passed := 0
+	for _, component := range []string{"db", "db", "http"} {
+		pipeline.Run(&Entry{Message: "m", Fields: Fields{"component": component}}, func(e *Entry) {
+			if e.Message == "m" {
+				passed++
+			}
+		})
+	}
+	if passed != 2 {
+		t.Errorf("Expected one entry per component, got %d", passed)
+	}
+}
@@ -0,0 +1,104 @@
+package logger
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// RecoverConfig controls how recovered panics are logged.
+type RecoverConfig struct {
+	// Message is the log message; defaults to "panic recovered".
+	Message string
+	// Fatal logs the panic with Fatal instead of Error.
+	Fatal bool
+	// Repanic re-raises the panic after it has been logged.
+	Repanic bool
+	// Stack controls the depth, trimming, and rendering of the stack trace.
+	Stack StackConfig
+	// CrashReport writes a crash report for the panic when the logger is a *Zap
+	// with Config.CrashReport.Dir set. Fatal panics always get one.
+	CrashReport bool
+}
+
+// RecoverAndLog recovers a panic and logs its value and stack trace at Error.
+// It must be deferred directly:
+//
+//	defer logger.RecoverAndLog(log)
+func RecoverAndLog(l Logger) {
+	if r := recover(); r != nil {
+		logPanic(l, r, RecoverConfig{})
+	}
+}
+
+// RecoverAndLogWith is like RecoverAndLog but configurable. It must be deferred directly:
+//
+//	defer logger.RecoverAndLogWith(log, logger.RecoverConfig{Repanic: true})
+func RecoverAndLogWith(l Logger, config RecoverConfig) {
+	if r := recover(); r != nil {
+		logPanic(l, r, config)
+	}
+}
+
+// RecoverHandler returns an http.Handler that recovers panics in next, logs
+// them at Error, and responds with 500 Internal Server Error unless next has
+// already sent the response headers.
+func RecoverHandler(l Logger, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		recorder := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+		defer func() {
+			if rec := recover(); rec != nil {
+				if rec == http.ErrAbortHandler {
+					panic(rec)
+				}
+				l.Error("panic recovered", panicFields(rec, StackConfig{}, Fields{
+					"method": r.Method,
+					"path":   r.URL.Path,
+				}))
+				if !recorder.wroteHeader {
+					w.WriteHeader(http.StatusInternalServerError)
+				}
+			}
+		}()
+		next.ServeHTTP(recorder, r)
+	})
+}
+
+// logPanic logs a recovered panic value according to config.
+func logPanic(l Logger, r interface{}, config RecoverConfig) {
+	msg := config.Message
+	if msg == "" {
+		msg = "panic recovered"
+	}
+
+	fields := panicFields(r, config.Stack, nil)
+	if z, ok := l.(*Zap); ok && config.CrashReport && !config.Fatal && z.Config.CrashReport.Dir != "" {
+		if _, err := z.writeCrashReport(1, msg, fields); err != nil {
+			z.state.reportError(err)
+		}
+	}
+	if config.Fatal {
+		l.Fatal(msg, fields)
+	} else {
+		l.Error(msg, fields)
+	}
+
+	if config.Repanic {
+		panic(r)
+	}
+}
+
+// panicFields returns the fields describing a recovered panic value, merged
+// into extra, with the stack rendered according to stack.
+func panicFields(r interface{}, stack StackConfig, extra Fields) Fields {
+	fields := Fields{
+		"panic": fmt.Sprint(r),
+		"stack": captureStack(1, stack),
+	}
+	if err, ok := r.(error); ok {
+		fields["error"] = err.Error()
+	}
+	for k, v := range extra {
+		fields[k] = v
+	}
+	return fields
+}
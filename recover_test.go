@@ -0,0 +1,103 @@
+package logger
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestRecoverAndLog tests that a panic is recovered and logged at Error.
+func TestRecoverAndLog(t *testing.T) {
+	log, logs := Observe(Config{})
+
+	func() {
+		defer RecoverAndLog(log)
+		panic(errors.New("boom"))
+	}()
+
+	entries := logs.FilterLevelExact(ErrorLevel).All()
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 error entry, got %d", len(entries))
+	}
+	fields := entries[0].Fields
+	if fields["panic"] != "boom" || fields["error"] != "boom" {
+		t.Errorf("Expected panic and error fields, got %+v", fields)
+	}
+	if stack, _ := fields["stack"].(string); !strings.Contains(stack, "TestRecoverAndLog") {
+		t.Errorf("Expected stack to contain the panicking function, got %s", stack)
+	}
+}
+
+// TestRecoverAndLogWith tests the Fatal and Repanic options.
+func TestRecoverAndLogWith(t *testing.T) {
+	exited := false
+	log, logs := Observe(Config{ExitFunc: func(int) { exited = true }})
+
+	defer func() {
+		if r := recover(); r != "boom" {
+			t.Errorf("Expected the panic to be re-raised, got %v", r)
+		}
+		if logs.FilterLevelExact(FatalLevel).FilterMessage("worker crashed").Len() != 1 {
+			t.Errorf("Expected a fatal entry, got %+v", logs.All())
+		}
+		if !exited {
+			t.Errorf("Expected ExitFunc to be called")
+		}
+	}()
+
+	func() {
+		defer RecoverAndLogWith(log, RecoverConfig{Message: "worker crashed", Fatal: true, Repanic: true})
+		panic("boom")
+	}()
+}
+
+// TestRecoverHandler tests that handler panics are logged and answered with 500.
+func TestRecoverHandler(t *testing.T) {
+	log, logs := Observe(Config{})
+	handler := RecoverHandler(log, http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		panic("boom")
+	}))
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/orders", nil))
+
+	if recorder.Code != http.StatusInternalServerError {
+		t.Errorf("Expected status 500, got %d", recorder.Code)
+	}
+	if logs.FilterField("path", "/orders").FilterField("panic", "boom").Len() != 1 {
+		t.Errorf("Expected a panic entry for /orders, got %+v", logs.All())
+	}
+}
+
+// TestRecoverHandler_HeadersSent tests that no status is written after the
+// handler has started the response.
+func TestRecoverHandler_HeadersSent(t *testing.T) {
+	log, logs := Observe(Config{})
+	handler := RecoverHandler(log, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte("partial"))
+		panic("boom")
+	}))
+
+	recorder := &headerCountingRecorder{ResponseRecorder: httptest.NewRecorder()}
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/orders", nil))
+
+	if recorder.headers != 0 || recorder.Code != http.StatusOK {
+		t.Errorf("Expected no WriteHeader after the body was written, got %d calls and status %d", recorder.headers, recorder.Code)
+	}
+	if logs.FilterField("panic", "boom").Len() != 1 {
+		t.Errorf("Expected the panic to be logged, got %+v", logs.All())
+	}
+}
+
+// headerCountingRecorder counts explicit WriteHeader calls.
+type headerCountingRecorder struct {
+	*httptest.ResponseRecorder
+	headers int
+}
+
+func (r *headerCountingRecorder) WriteHeader(status int) {
+	r.headers++
+	r.ResponseRecorder.WriteHeader(status)
+}
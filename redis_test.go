@@ -0,0 +1,51 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// TestRedisStreamWriter tests that entries are added with XADD and trimmed.
+func TestRedisStreamWriter(t *testing.T) {
+	var commands [][]interface{}
+	client := RedisDoFunc(func(ctx context.Context, args ...interface{}) error {
+		commands = append(commands, args)
+		return nil
+	})
+	writer := MustRedisStreamWriter(RedisStreamConfig{Client: client, Stream: "logs", MaxLen: 1000, Approximate: true})
+	zapLogger := NewZap(Config{Level: InfoLevel, Output: writer})
+
+	zapLogger.Info("Info message", nil)
+
+	if len(commands) != 1 {
+		t.Fatalf("Expected 1 command, got %d", len(commands))
+	}
+	got := fmt.Sprint(commands[0][:6])
+	if expected := "[XADD logs MAXLEN ~ 1000 *]"; got != expected {
+		t.Errorf("Expected command %s, got %s", expected, got)
+	}
+	if commands[0][6] != "entry" {
+		t.Errorf("Expected field entry, got %v", commands[0][6])
+	}
+}
+
+// TestNewRedisStreamWriterE tests configuration validation and command errors.
+func TestNewRedisStreamWriterE(t *testing.T) {
+	client := RedisDoFunc(func(context.Context, ...interface{}) error { return errors.New("connection refused") })
+	for _, config := range []RedisStreamConfig{
+		{Stream: "logs"},
+		{Client: client},
+		{Client: client, Stream: "logs", MaxLen: -1},
+	} {
+		if _, err := NewRedisStreamWriterE(config); err == nil {
+			t.Errorf("Expected an error for %+v", config)
+		}
+	}
+
+	writer := MustRedisStreamWriter(RedisStreamConfig{Client: client, Stream: "logs"})
+	if _, err := writer.Write([]byte(`{"msg":"m"}`)); err == nil {
+		t.Errorf("Expected the command error to be returned")
+	}
+}
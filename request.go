@@ -0,0 +1,84 @@
+package logger
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// RequestIDHeader is the header used to carry request IDs.
+const RequestIDHeader = "X-Request-ID"
+
+// RequestIDConfig controls how RequestIDHandler assigns request IDs.
+type RequestIDConfig struct {
+	// Header carries the request ID; defaults to RequestIDHeader.
+	Header string
+	// TrustIncoming reuses a request ID sent by the client instead of minting one.
+	TrustIncoming bool
+	// Echo sets the request ID on the response header.
+	Echo bool
+	// Generate mints new request IDs; defaults to NewRequestID.
+	Generate func() string
+}
+
+// NewRequestID returns a new ULID to identify a request.
+func NewRequestID() string {
+	return newULID(time.Now())
+}
+
+// NewUUID returns a new random (version 4) UUID, for use as RequestIDConfig.Generate.
+func NewUUID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// NewRequestLogger mints a request ID and returns a child of l with a
+// request_id field, together with a copy of ctx carrying both the ID and the
+// child logger.
+func NewRequestLogger(ctx context.Context, l Logger) (context.Context, Logger) {
+	return withRequestID(ctx, l, NewRequestID())
+}
+
+// RequestIDFromContext returns the request ID carried by ctx, or "" if there is none.
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey).(string)
+	return id
+}
+
+// RequestIDHandler returns an http.Handler that assigns each request an ID and
+// serves it with a request context carrying the ID and a child of l bound to
+// it, retrievable with RequestIDFromContext and FromContext.
+func RequestIDHandler(l Logger, config RequestIDConfig, next http.Handler) http.Handler {
+	if config.Header == "" {
+		config.Header = RequestIDHeader
+	}
+	if config.Generate == nil {
+		config.Generate = NewRequestID
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := ""
+		if config.TrustIncoming {
+			id = r.Header.Get(config.Header)
+		}
+		if id == "" {
+			id = config.Generate()
+		}
+		if config.Echo {
+			w.Header().Set(config.Header, id)
+		}
+		ctx, _ := withRequestID(r.Context(), contextLogger(r.Context(), l), id)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// withRequestID binds id to a child of l and to a copy of ctx.
+func withRequestID(ctx context.Context, l Logger, id string) (context.Context, Logger) {
+	child := With(l, Fields{"request_id": id})
+	ctx = context.WithValue(ctx, requestIDKey, id)
+	return NewContext(ctx, child), child
+}
@@ -0,0 +1,66 @@
+package logger
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+)
+
+// TestNewRequestLogger tests that the request ID is bound to the logger and the context.
+func TestNewRequestLogger(t *testing.T) {
+	observed, logs := Observe(Config{Level: InfoLevel})
+	ctx, l := NewRequestLogger(context.Background(), observed)
+
+	id := RequestIDFromContext(ctx)
+	if len(id) != 26 {
+		t.Fatalf("Expected a ULID request ID, got %q", id)
+	}
+	FromContext(ctx).Info("Info message", nil)
+	l.Info("Info message", nil)
+	if n := logs.FilterField("request_id", id).Len(); n != 2 {
+		t.Errorf("Expected 2 entries with the request ID, got %d", n)
+	}
+
+	if FromContext(context.Background()) != Default() {
+		t.Errorf("Expected the default logger for a bare context")
+	}
+}
+
+// TestRequestIDHandler tests that requests get an ID, optionally reused and echoed.
+func TestRequestIDHandler(t *testing.T) {
+	observed, logs := Observe(Config{Level: InfoLevel})
+	handler := func(config RequestIDConfig) http.Handler {
+		return RequestIDHandler(observed, config, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			FromContext(r.Context()).Info("handled", nil)
+		}))
+	}
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(RequestIDHeader, "incoming")
+	handler(RequestIDConfig{}).ServeHTTP(rec, req)
+	if rec.Header().Get(RequestIDHeader) != "" {
+		t.Errorf("Expected no echoed header by default")
+	}
+	if logs.FilterField("request_id", "incoming").Len() != 0 {
+		t.Errorf("Expected the incoming ID not to be trusted by default")
+	}
+
+	rec = httptest.NewRecorder()
+	handler(RequestIDConfig{TrustIncoming: true, Echo: true}).ServeHTTP(rec, req)
+	if got := rec.Header().Get(RequestIDHeader); got != "incoming" {
+		t.Errorf("Expected echoed ID incoming, got %q", got)
+	}
+	if logs.FilterField("request_id", "incoming").Len() != 1 {
+		t.Errorf("Expected the incoming ID to be logged")
+	}
+
+	rec = httptest.NewRecorder()
+	handler(RequestIDConfig{Echo: true, Generate: NewUUID}).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	uuid := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	if got := rec.Header().Get(RequestIDHeader); !uuid.MatchString(got) {
+		t.Errorf("Expected a UUID request ID, got %q", got)
+	}
+}
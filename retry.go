@@ -0,0 +1,125 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"sync/atomic"
+	"time"
+)
+
+// RetryConfig holds the configuration for a retrying writer.
+type RetryConfig struct {
+	// Output is the writer whose failed writes are retried.
+	Output io.Writer
+	// MaxAttempts is the total number of attempts per entry; defaults to 3.
+	MaxAttempts int
+	// InitialBackoff is the wait before the first retry; defaults to 100ms.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the wait between retries; defaults to 5 seconds.
+	MaxBackoff time.Duration
+	// Multiplier grows the wait after each retry; defaults to 2.
+	Multiplier float64
+	// Jitter randomizes each wait by up to this fraction in either direction,
+	// so many clients do not retry in lockstep; defaults to 0.2. Must be
+	// between 0 and 1.
+	Jitter float64
+	// DeadLetter receives entries that exhaust their attempts, e.g. a local file.
+	DeadLetter io.Writer
+}
+
+// RetryWriter is a writer that retries failed writes with exponential backoff
+// and jitter. Entries that still fail are written to the dead letter writer if
+// one is configured, and their error is returned otherwise. Retries block the
+// caller, so wrap slow outputs in an asynchronous writer when latency matters.
+type RetryWriter struct {
+	config       RetryConfig
+	sleep        func(time.Duration)
+	deadLettered uint64
+}
+
+// NewRetryWriter returns a new *RetryWriter.
+func NewRetryWriter(config RetryConfig) *RetryWriter {
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = 3
+	}
+	if config.InitialBackoff <= 0 {
+		config.InitialBackoff = 100 * time.Millisecond
+	}
+	if config.MaxBackoff <= 0 {
+		config.MaxBackoff = 5 * time.Second
+	}
+	if config.Multiplier < 1 {
+		config.Multiplier = 2
+	}
+	if config.Jitter <= 0 || config.Jitter > 1 {
+		config.Jitter = 0.2
+	}
+	return &RetryWriter{config: config, sleep: time.Sleep}
+}
+
+// Write writes p to the output, retrying failures with backoff.
+func (w *RetryWriter) Write(p []byte) (int, error) {
+	backoff := w.config.InitialBackoff
+	var err error
+	for attempt := 1; ; attempt++ {
+		if _, err = w.config.Output.Write(p); err == nil {
+			return len(p), nil
+		}
+		if attempt == w.config.MaxAttempts {
+			break
+		}
+		w.sleep(w.jitter(backoff))
+		backoff = time.Duration(float64(backoff) * w.config.Multiplier)
+		if backoff > w.config.MaxBackoff {
+			backoff = w.config.MaxBackoff
+		}
+	}
+
+	if w.config.DeadLetter != nil {
+		if _, dlErr := w.config.DeadLetter.Write(p); dlErr == nil {
+			atomic.AddUint64(&w.deadLettered, 1)
+			return len(p), nil
+		}
+	}
+	return 0, fmt.Errorf("retry: giving up after %d attempts: %w", w.config.MaxAttempts, err)
+}
+
+// jitter randomizes d by up to the configured fraction in either direction.
+func (w *RetryWriter) jitter(d time.Duration) time.Duration {
+	delta := (rand.Float64()*2 - 1) * w.config.Jitter * float64(d)
+	return d + time.Duration(delta)
+}
+
+// DeadLettered returns the number of entries written to the dead letter writer.
+func (w *RetryWriter) DeadLettered() uint64 {
+	return atomic.LoadUint64(&w.deadLettered)
+}
+
+// Health returns the health of the output if it can report it.
+func (w *RetryWriter) Health(ctx context.Context) error {
+	if checker, ok := w.config.Output.(HealthChecker); ok {
+		return checker.Health(ctx)
+	}
+	return nil
+}
+
+// Ping pings the output if it can be pinged or health-checked.
+func (w *RetryWriter) Ping(ctx context.Context) error {
+	return pingOutput(ctx, w.config.Output)
+}
+
+// Sync flushes the output and the dead letter writer if they support it.
+func (w *RetryWriter) Sync() error {
+	var err error
+	if s, ok := w.config.Output.(syncer); ok {
+		err = s.Sync()
+	}
+	if s, ok := w.config.DeadLetter.(syncer); ok {
+		if dlErr := s.Sync(); err == nil {
+			err = dlErr
+		}
+	}
+	return err
+}
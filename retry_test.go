@@ -0,0 +1,58 @@
+package logger
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+// TestRetryWriter tests that failed writes are retried with growing, jittered backoff.
+func TestRetryWriter(t *testing.T) {
+	remote := &flakyWriter{fail: true}
+	writer := NewRetryWriter(RetryConfig{
+		Output:         remote,
+		MaxAttempts:    4,
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     300 * time.Millisecond,
+		Jitter:         0.1,
+	})
+	var waits []time.Duration
+	writer.sleep = func(d time.Duration) {
+		waits = append(waits, d)
+		if len(waits) == 2 {
+			remote.fail = false
+		}
+	}
+
+	if _, err := writer.Write([]byte("a")); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if remote.attempts != 3 || remote.written.String() != "a" {
+		t.Errorf("Expected success on the third attempt, got %d attempts", remote.attempts)
+	}
+	for i, base := range []time.Duration{100 * time.Millisecond, 200 * time.Millisecond} {
+		if waits[i] < base*9/10 || waits[i] > base*11/10 {
+			t.Errorf("Expected wait %d within 10%% of %v, got %v", i, base, waits[i])
+		}
+	}
+}
+
+// TestRetryWriter_DeadLetter tests that exhausted entries are dead-lettered or reported.
+func TestRetryWriter_DeadLetter(t *testing.T) {
+	deadLetter := new(bytes.Buffer)
+	writer := NewRetryWriter(RetryConfig{Output: failingWriter{}, MaxAttempts: 2, DeadLetter: deadLetter})
+	writer.sleep = func(time.Duration) {}
+
+	if _, err := writer.Write([]byte("a\n")); err != nil {
+		t.Errorf("Expected a dead-lettered entry to succeed, got %v", err)
+	}
+	if deadLetter.String() != "a\n" || writer.DeadLettered() != 1 {
+		t.Errorf("Expected the entry in the dead letter writer, got %q", deadLetter.String())
+	}
+
+	writer = NewRetryWriter(RetryConfig{Output: failingWriter{}, MaxAttempts: 2})
+	writer.sleep = func(time.Duration) {}
+	if _, err := writer.Write([]byte("a\n")); err == nil {
+		t.Errorf("Expected an error without a dead letter writer")
+	}
+}
@@ -0,0 +1,62 @@
+package logger
+
+import (
+	"sync"
+)
+
+// ringBuffer is a fixed-size, concurrency-safe buffer of the most recent entries.
+type ringBuffer struct {
+	mu      sync.Mutex
+	entries []Entry
+	next    int
+	full    bool
+}
+
+// newRingBuffer returns a ring buffer holding up to size entries, or nil if size
+// is not positive.
+func newRingBuffer(size int) *ringBuffer {
+	if size <= 0 {
+		return nil
+	}
+	return &ringBuffer{entries: make([]Entry, size)}
+}
+
+// add stores an entry, overwriting the oldest one when the buffer is full.
+func (r *ringBuffer) add(entry Entry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries[r.next] = entry
+	r.next = (r.next + 1) % len(r.entries)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// drain returns the stored entries from oldest to newest and empties the buffer.
+func (r *ringBuffer) drain() []Entry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	entries := r.ordered()
+	for i := range r.entries {
+		r.entries[i] = Entry{}
+	}
+	r.next, r.full = 0, false
+	return entries
+}
+
+// snapshot returns the stored entries from oldest to newest, keeping them.
+func (r *ringBuffer) snapshot() []Entry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.ordered()
+}
+
+// ordered returns the stored entries from oldest to newest; r.mu must be held.
+func (r *ringBuffer) ordered() []Entry {
+	if !r.full {
+		return append([]Entry(nil), r.entries[:r.next]...)
+	}
+	entries := make([]Entry, 0, len(r.entries))
+	entries = append(entries, r.entries[r.next:]...)
+	return append(entries, r.entries[:r.next]...)
+}
@@ -0,0 +1,92 @@
+package logger
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+// TestRingBuffer tests that the ring buffer keeps the most recent entries in order.
+func TestRingBuffer(t *testing.T) {
+	ring := newRingBuffer(3)
+	for i := 1; i <= 5; i++ {
+		ring.add(Entry{Message: fmt.Sprint(i)})
+	}
+
+	entries := ring.drain()
+	if len(entries) != 3 {
+		t.Fatalf("Expected 3 entries, got %d", len(entries))
+	}
+	for i, expected := range []string{"3", "4", "5"} {
+		if entries[i].Message != expected {
+			t.Errorf("Expected entry %d to be %s, got %s", i, expected, entries[i].Message)
+		}
+	}
+	if n := len(ring.drain()); n != 0 {
+		t.Errorf("Expected drain to empty the buffer, got %d entries", n)
+	}
+}
+
+// TestZap_FlightRecorder tests that suppressed debug entries are dumped on error.
+func TestZap_FlightRecorder(t *testing.T) {
+	requireDebug(t)
+	buffer := new(bytes.Buffer)
+	zapLogger := NewZap(Config{Level: InfoLevel, Output: buffer, FlightRecorder: 2})
+
+	zapLogger.Debug("step 1", nil)
+	zapLogger.Debug("step 2", Fields{"key": "value"})
+	zapLogger.DebugFields("step 3", Int("n", 3))
+	if buffer.Len() != 0 {
+		t.Fatalf("Expected debug entries to be held back, got %s", buffer.String())
+	}
+
+	zapLogger.Error("Error message", nil)
+	lines := bytes.Split(bytes.TrimSpace(buffer.Bytes()), []byte("\n"))
+	if len(lines) != 3 {
+		t.Fatalf("Expected 3 entries, got %d: %s", len(lines), buffer.String())
+	}
+	for i, expected := range []string{`"msg":"step 2"`, `"msg":"step 3"`, `"msg":"Error message"`} {
+		if !bytes.Contains(lines[i], []byte(expected)) {
+			t.Errorf("Expected entry %d to contain %s, got %s", i, expected, lines[i])
+		}
+	}
+	if !bytes.Contains(lines[0], []byte(`"flight_recorder":true`)) || !bytes.Contains(lines[0], []byte(`"level":"debug"`)) {
+		t.Errorf("Expected dumped entry to be marked, got %s", lines[0])
+	}
+
+	buffer.Reset()
+	zapLogger.Error("Another error", nil)
+	if bytes.Count(buffer.Bytes(), []byte("\n")) != 1 {
+		t.Errorf("Expected the recorder to be empty after a dump, got %s", buffer.String())
+	}
+}
+
+// TestZap_FlightRecorderDestinations tests that dumped entries respect the
+// level of each destination.
+func TestZap_FlightRecorderDestinations(t *testing.T) {
+	requireDebug(t)
+	file := new(bytes.Buffer)
+	pager := new(bytes.Buffer)
+	audit := new(bytes.Buffer)
+	zapLogger := NewZap(Config{
+		Level:          InfoLevel,
+		FlightRecorder: 10,
+		Destinations: []Destination{
+			{Output: file, Level: DebugLevel},
+			{Output: pager, Level: ErrorLevel},
+			{Name: "audit", Output: audit, Level: ErrorLevel},
+		},
+	})
+
+	zapLogger.Debug("step 1", nil)
+	zapLogger.Error("Error message", nil)
+
+	if !bytes.Contains(file.Bytes(), []byte(`"msg":"step 1"`)) {
+		t.Errorf("Expected the debug destination to receive the dump, got %s", file.String())
+	}
+	for name, output := range map[string]*bytes.Buffer{"pager": pager, "audit": audit} {
+		if bytes.Contains(output.Bytes(), []byte("flight_recorder")) || bytes.Count(output.Bytes(), []byte("\n")) != 1 {
+			t.Errorf("Expected only the error entry in %s, got %s", name, output.String())
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package logger
+
+import (
+	"go.uber.org/zap/zapcore"
+)
+
+// routeKey is the key of the control field added by Route.
+const routeKey = "_route"
+
+// routeTarget is the value of the control field added by Route.
+type routeTarget string
+
+// routeMarker marks converted route fields, which are never encoded.
+type routeMarker struct{}
+
+// Route returns a control field that sends the entry only to the destination
+// with the given Destination.Name, whatever the destination's own minimum
+// level, e.g. to direct security or billing entries from cross-cutting code:
+//
+//	log.WarnFields("login failed", logger.Route("security"), logger.String("user", user))
+//
+// The field itself is not written. The entry must still pass the logger's
+// level. An entry routed to a name no destination has goes to Config.Output.
+// A Route field bound with With, as Fields{f.Key: f.Value}, routes every entry
+// of the child logger.
+func Route(name string) Field {
+	return Field{Key: routeKey, Value: routeTarget(name)}
+}
+
+// routeField converts a route target to a zap field that is never encoded.
+func routeField(name routeTarget) zapcore.Field {
+	return zapcore.Field{Key: routeKey, Type: zapcore.SkipType, String: string(name), Interface: routeMarker{}}
+}
+
+// routeOf returns the route target among fields, if any.
+func routeOf(fields []zapcore.Field) (string, bool) {
+	for i := len(fields) - 1; i >= 0; i-- {
+		if _, ok := fields[i].Interface.(routeMarker); ok && fields[i].Type == zapcore.SkipType {
+			return fields[i].String, true
+		}
+	}
+	return "", false
+}
+
+// routeCore writes entries routed with Route to the core of the named
+// destination only, and other entries according to the core's own level.
+// Entries dumped by the flight recorder, which are below the logger level,
+// are written if the destination's own minimum level enables them.
+type routeCore struct {
+	zapcore.Core
+	// name is the destination name; empty for the main output.
+	name string
+	// minimum is the destination's own minimum level; nil for the main output.
+	minimum zapcore.LevelEnabler
+	// known holds the destination names; the main output takes entries routed
+	// to any other name.
+	known map[string]struct{}
+	// logger enables the levels of the logger, which routed entries must pass.
+	logger zapcore.LevelEnabler
+	// route is the route bound with With, if any.
+	route  string
+	routed bool
+}
+
+// newRouteCores wraps the main output core, if any, and the destination cores
+// when there are destinations.
+func newRouteCores(main zapcore.Core, dests []zapcore.Core, config Config, logger zapcore.LevelEnabler) []zapcore.Core {
+	known := make(map[string]struct{})
+	for _, dest := range config.Destinations {
+		if dest.Name != "" {
+			known[dest.Name] = struct{}{}
+		}
+	}
+	wrap := len(dests) > 0
+	var cores []zapcore.Core
+	if main != nil {
+		if wrap {
+			main = &routeCore{Core: main, known: known, logger: logger}
+		}
+		cores = append(cores, main)
+	}
+	for i, core := range dests {
+		if wrap {
+			dest := config.Destinations[i]
+			core = &routeCore{Core: core, name: dest.Name, minimum: dest.Level.zapLevel(), known: known, logger: logger}
+		}
+		cores = append(cores, core)
+	}
+	return cores
+}
+
+// With returns a child core, remembering a route among fields.
+func (c *routeCore) With(fields []zapcore.Field) zapcore.Core {
+	child := *c
+	child.Core = c.Core.With(fields)
+	if route, ok := routeOf(fields); ok {
+		child.route, child.routed = route, true
+	}
+	return &child
+}
+
+// Check adds c to the checked entry if the logger enables the level, since
+// the entry may be routed to c whatever c's own level.
+func (c *routeCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Core.Enabled(entry.Level) || c.logger.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+// Write writes routed entries if they are routed to c and other entries if c
+// enables their level.
+func (c *routeCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	route, routed := routeOf(fields)
+	if !routed {
+		route, routed = c.route, c.routed
+	}
+	if !routed {
+		if recorded(fields) {
+			if c.minimum != nil && !c.minimum.Enabled(entry.Level) {
+				return nil
+			}
+		} else if !c.Core.Enabled(entry.Level) {
+			return nil
+		}
+		return c.Core.Write(entry, fields)
+	}
+	if c.name != "" && route == c.name {
+		return c.Core.Write(entry, fields)
+	}
+	if _, ok := c.known[route]; c.name == "" && !ok {
+		return c.Core.Write(entry, fields)
+	}
+	return nil
+}
+
+// recorderMarker marks entries dumped by the flight recorder.
+type recorderMarker struct{}
+
+// recorderField is added to entries dumped by the flight recorder; it is never
+// encoded.
+var recorderField = zapcore.Field{Type: zapcore.SkipType, Interface: recorderMarker{}}
+
+// recorded reports whether fields belong to an entry dumped by the flight recorder.
+func recorded(fields []zapcore.Field) bool {
+	for i := len(fields) - 1; i >= 0; i-- {
+		if _, ok := fields[i].Interface.(recorderMarker); ok && fields[i].Type == zapcore.SkipType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,102 @@
+package logger
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// TestRoute tests that routed entries only reach the named destination,
+// whatever its level, and that the route field is not written.
+func TestRoute(t *testing.T) {
+	app := new(bytes.Buffer)
+	audit := new(bytes.Buffer)
+	zapLogger := NewZap(Config{
+		Level: InfoLevel,
+		Destinations: []Destination{
+			{Output: app, Level: InfoLevel},
+			{Name: "audit", Output: audit, Level: ErrorLevel},
+		},
+	})
+
+	zapLogger.InfoFields("login failed", Route("audit"), String("user", "alice"))
+	zapLogger.Info("request served", nil)
+	zapLogger.Error("request failed", nil)
+	zapLogger.DebugFields("debug entry", Route("audit"))
+
+	if strings.Contains(app.String(), "login failed") {
+		t.Errorf("Expected the routed entry to skip the default destination, got %q", app.String())
+	}
+	if n := strings.Count(app.String(), "\n"); n != 2 {
+		t.Errorf("Expected 2 entries in the default destination, got %d", n)
+	}
+	if !strings.Contains(audit.String(), `"user":"alice"`) {
+		t.Errorf("Expected the routed entry in the audit destination, got %q", audit.String())
+	}
+	if strings.Contains(audit.String(), routeKey) {
+		t.Errorf("Expected the route field not to be written, got %q", audit.String())
+	}
+	if strings.Contains(audit.String(), "debug entry") {
+		t.Errorf("Expected routed entries to pass the logger level, got %q", audit.String())
+	}
+	if n := strings.Count(audit.String(), "\n"); n != 2 {
+		t.Errorf("Expected 2 entries in the audit destination, got %d", n)
+	}
+}
+
+// TestRoute_Unknown tests that entries routed to an unknown name go to the main output.
+func TestRoute_Unknown(t *testing.T) {
+	output := new(bytes.Buffer)
+	audit := new(bytes.Buffer)
+	zapLogger := NewZap(Config{
+		Level:        InfoLevel,
+		Output:       output,
+		Destinations: []Destination{{Name: "audit", Output: audit, Level: InfoLevel}},
+	})
+
+	zapLogger.InfoFields("billing event", Route("billing"))
+
+	if !strings.Contains(output.String(), "billing event") {
+		t.Errorf("Expected the entry in the main output, got %q", output.String())
+	}
+	if audit.Len() != 0 {
+		t.Errorf("Expected no entry in the audit destination, got %q", audit.String())
+	}
+}
+
+// TestRoute_With tests that a route bound with With applies to every entry.
+func TestRoute_With(t *testing.T) {
+	app := new(bytes.Buffer)
+	security := new(bytes.Buffer)
+	zapLogger := NewZap(Config{
+		Level: InfoLevel,
+		Destinations: []Destination{
+			{Output: app, Level: InfoLevel},
+			{Name: "security", Output: security, Level: WarnLevel},
+		},
+	})
+
+	route := Route("security")
+	child := zapLogger.With(Fields{route.Key: route.Value})
+	child.Info("token issued", nil)
+	child.Info("token revoked", nil)
+	zapLogger.Info("unrouted", nil)
+
+	if n := strings.Count(security.String(), "\n"); n != 2 {
+		t.Errorf("Expected 2 entries in the security destination, got %d", n)
+	}
+	if app.String() == "" || strings.Contains(app.String(), "token") {
+		t.Errorf("Expected only the unrouted entry in the default destination, got %q", app.String())
+	}
+}
+
+// TestNewZapE_DuplicateDestinationName tests that destination names must be unique.
+func TestNewZapE_DuplicateDestinationName(t *testing.T) {
+	_, err := NewZapE(Config{Destinations: []Destination{
+		{Name: "audit", Output: new(bytes.Buffer)},
+		{Name: "audit", Output: new(bytes.Buffer)},
+	}})
+	if err == nil {
+		t.Errorf("Expected an error for duplicate destination names")
+	}
+}
@@ -0,0 +1,261 @@
+package logger
+
+import (
+	"container/list"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// RouterConfig holds the configuration for a Router.
+type RouterConfig struct {
+	// Key is the field whose value selects the sink, e.g. "tenant_id".
+	Key string
+	// Open returns the output for a key value, e.g. a per-tenant file or a
+	// writer publishing to a per-tenant topic. Outputs that implement io.Closer
+	// are closed when their sink is evicted.
+	Open func(value string) (io.Writer, error)
+	// Config is the template for the per-key loggers. Output and Pipeline are
+	// replaced, Context is ignored since the Router owns the sinks, the caller
+	// is not recorded, and fatal entries do not exit.
+	Config Config
+	// MaxOpen bounds the number of open sinks; the least recently used sink is
+	// closed to make room. Defaults to 64.
+	MaxOpen int
+	// IdleTimeout closes sinks that have not been written to for this long.
+	// Idle sinks are closed on routing and by Prune; zero keeps sinks open until
+	// they are evicted.
+	IdleTimeout time.Duration
+	// Clock times idleness; defaults to the system clock.
+	Clock Clock
+	// OnError is called when a sink cannot be opened or closed. Entries whose
+	// sink cannot be opened continue down the pipeline.
+	OnError func(error)
+}
+
+// Router is a pipeline stage writing each entry to a sink selected by the value
+// of a field, such as a per-tenant file, so that the logs of tenants stay
+// segregated. Sinks are opened on first use and kept in a least recently used
+// cache. Entries without the field continue down the pipeline, as do fatal
+// entries after being written to their sink, so that Config.OnFatal applies.
+type Router struct {
+	config RouterConfig
+	mu     sync.Mutex
+	sinks  map[string]*list.Element
+	lru    *list.List // front is the most recently used *routerSink
+}
+
+// routerSink is an open sink of a Router.
+type routerSink struct {
+	value    string
+	logger   *Zap
+	output   io.Writer
+	lastUsed time.Time
+	// inUse counts the writes in progress; an evicted sink is closed once
+	// they finish. Both are guarded by the Router's mu.
+	inUse   int
+	evicted bool
+}
+
+// NewRouterE returns a new *Router, or an error if Key or Open is not set.
+func NewRouterE(config RouterConfig) (*Router, error) {
+	if config.Key == "" {
+		return nil, errors.New("router: key is required")
+	}
+	if config.Open == nil {
+		return nil, errors.New("router: open is required")
+	}
+	return NewRouter(config), nil
+}
+
+// MustRouter is like NewRouterE but panics on error.
+func MustRouter(config RouterConfig) *Router {
+	r, err := NewRouterE(config)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+// NewRouter returns a new *Router. Without Key or Open, every entry continues
+// down the pipeline; use NewRouterE to check them up front.
+func NewRouter(config RouterConfig) *Router {
+	if config.MaxOpen <= 0 {
+		config.MaxOpen = 64
+	}
+	config.Config.Output = nil
+	config.Config.Pipeline = nil
+	config.Config.Context = nil
+	config.Config.DisableCaller = true
+	config.Config.OnFatal = FatalNone
+	return &Router{config: config, sinks: make(map[string]*list.Element), lru: list.New()}
+}
+
+// Stage returns the pipeline stage routing entries to the sinks.
+func (r *Router) Stage() Stage {
+	return func(entry *Entry, next func(*Entry)) {
+		value, ok := entry.Fields[r.config.Key]
+		if !ok || r.config.Open == nil {
+			next(entry)
+			return
+		}
+		sink, err := r.acquire(fmt.Sprint(value))
+		if err != nil {
+			r.report(err)
+			next(entry)
+			return
+		}
+		logEntry(sink.logger, entry)
+		r.release(sink)
+		if entry.Level == FatalLevel {
+			next(entry)
+		}
+	}
+}
+
+// acquire returns the sink for value, opening it if needed, and marks it in
+// use until release, so that it is not closed while written to.
+func (r *Router) acquire(value string) (*routerSink, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	r.prune(now)
+	if e, ok := r.sinks[value]; ok {
+		s := e.Value.(*routerSink)
+		s.lastUsed = now
+		s.inUse++
+		r.lru.MoveToFront(e)
+		return s, nil
+	}
+
+	output, err := r.config.Open(value)
+	if err != nil {
+		return nil, fmt.Errorf("router: open %q: %w", value, err)
+	}
+	for r.lru.Len() >= r.config.MaxOpen {
+		r.evict(r.lru.Back())
+	}
+	config := r.config.Config
+	config.Output = output
+	s := &routerSink{value: value, logger: NewZap(config), output: output, lastUsed: now, inUse: 1}
+	r.sinks[value] = r.lru.PushFront(s)
+	return s, nil
+}
+
+// release ends a write to s, closing s if it was evicted meanwhile.
+func (r *Router) release(s *routerSink) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s.inUse--
+	if s.inUse == 0 && s.evicted {
+		if err := r.closeSink(s); err != nil {
+			r.report(err)
+		}
+	}
+}
+
+// prune closes the sinks idle since before now minus IdleTimeout. It must be
+// called with mu held.
+func (r *Router) prune(now time.Time) {
+	if r.config.IdleTimeout <= 0 {
+		return
+	}
+	for e := r.lru.Back(); e != nil; e = r.lru.Back() {
+		if now.Sub(e.Value.(*routerSink).lastUsed) < r.config.IdleTimeout {
+			return
+		}
+		r.evict(e)
+	}
+}
+
+// evict removes a sink and flushes and closes it, or leaves that to release
+// while it is in use. It must be called with mu held.
+func (r *Router) evict(e *list.Element) {
+	s := r.lru.Remove(e).(*routerSink)
+	delete(r.sinks, s.value)
+	if s.inUse > 0 {
+		s.evicted = true
+		return
+	}
+	if err := r.closeSink(s); err != nil {
+		r.report(err)
+	}
+}
+
+// closeSink flushes the logger of s and closes its output.
+func (r *Router) closeSink(s *routerSink) error {
+	_ = s.logger.Sync()
+	if c, ok := s.output.(io.Closer); ok {
+		if err := c.Close(); err != nil {
+			return fmt.Errorf("router: close %q: %w", s.value, err)
+		}
+	}
+	return nil
+}
+
+// now returns the current time from the configured clock.
+func (r *Router) now() time.Time {
+	if r.config.Clock != nil {
+		return r.config.Clock.Now()
+	}
+	return time.Now()
+}
+
+// report passes err to OnError.
+func (r *Router) report(err error) {
+	if r.config.OnError != nil {
+		r.config.OnError(err)
+	}
+}
+
+// Len returns the number of open sinks.
+func (r *Router) Len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.lru.Len()
+}
+
+// Prune closes the sinks that have been idle for longer than IdleTimeout. It
+// can be called periodically to release sinks when no entries are routed.
+func (r *Router) Prune() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.prune(r.now())
+}
+
+// Sync flushes every open sink.
+func (r *Router) Sync() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var first error
+	for e := r.lru.Front(); e != nil; e = e.Next() {
+		if err := e.Value.(*routerSink).logger.Sync(); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// Close flushes and closes every open sink, returning the first error. Sinks
+// being written to are closed once their writes finish, reporting errors to
+// OnError.
+func (r *Router) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var first error
+	for e := r.lru.Front(); e != nil; e = r.lru.Front() {
+		s := r.lru.Remove(e).(*routerSink)
+		delete(r.sinks, s.value)
+		if s.inUse > 0 {
+			s.evicted = true
+			continue
+		}
+		if err := r.closeSink(s); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
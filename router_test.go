@@ -0,0 +1,216 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// closingBuffer is a buffer that records whether it was closed.
+type closingBuffer struct {
+	bytes.Buffer
+	closed bool
+}
+
+func (b *closingBuffer) Close() error {
+	b.closed = true
+	return nil
+}
+
+// TestRouter tests that entries are written to the sink of their key.
+func TestRouter(t *testing.T) {
+	sinks := map[string]*closingBuffer{}
+	router := MustRouter(RouterConfig{
+		Key: "tenant_id",
+		Open: func(value string) (io.Writer, error) {
+			sinks[value] = new(closingBuffer)
+			return sinks[value], nil
+		},
+		Config: Config{Level: DebugLevel},
+	})
+	buffer := new(bytes.Buffer)
+	log := NewZap(Config{Level: InfoLevel, Output: buffer, Pipeline: NewPipeline(router.Stage())})
+
+	log.Info("order placed", Fields{"tenant_id": "acme"})
+	log.Info("order placed", Fields{"tenant_id": 42})
+	log.Info("deploy started", nil)
+
+	if !strings.Contains(sinks["acme"].String(), `"tenant_id":"acme"`) {
+		t.Errorf("Expected the acme sink to contain its entry, got %s", sinks["acme"].String())
+	}
+	if !strings.Contains(sinks["42"].String(), `"tenant_id":42`) {
+		t.Errorf("Expected the 42 sink to contain its entry, got %s", sinks["42"].String())
+	}
+	if strings.Contains(buffer.String(), "order placed") || !strings.Contains(buffer.String(), "deploy started") {
+		t.Errorf("Expected only the unrouted entry in the main output, got %s", buffer.String())
+	}
+
+	if err := router.Close(); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if !sinks["acme"].closed || router.Len() != 0 {
+		t.Errorf("Expected the sinks to be closed")
+	}
+}
+
+// TestRouter_Eviction tests that the least recently used and idle sinks are closed.
+func TestRouter_Eviction(t *testing.T) {
+	clock := &manualClock{now: time.Unix(0, 0)}
+	sinks := map[string]*closingBuffer{}
+	opened := 0
+	router := MustRouter(RouterConfig{
+		Key: "tenant",
+		Open: func(value string) (io.Writer, error) {
+			opened++
+			sinks[value] = new(closingBuffer)
+			return sinks[value], nil
+		},
+		MaxOpen:     2,
+		IdleTimeout: time.Minute,
+		Clock:       clock,
+	})
+	stage := NewPipeline(router.Stage())
+	route := func(tenant string) {
+		stage.Run(&Entry{Level: InfoLevel, Message: "m", Fields: Fields{"tenant": tenant}}, func(*Entry) {})
+	}
+
+	route("a")
+	route("b")
+	route("a")
+	route("c")
+	if !sinks["b"].closed || sinks["a"].closed || router.Len() != 2 {
+		t.Errorf("Expected b to be evicted as least recently used")
+	}
+	if opened != 3 {
+		t.Errorf("Expected 3 sinks to be opened, got %d", opened)
+	}
+
+	clock.now = clock.now.Add(2 * time.Minute)
+	router.Prune()
+	if !sinks["a"].closed || !sinks["c"].closed || router.Len() != 0 {
+		t.Errorf("Expected idle sinks to be closed")
+	}
+}
+
+// TestRouter_OpenError tests that entries whose sink cannot be opened continue down the pipeline.
+func TestRouter_OpenError(t *testing.T) {
+	var reported error
+	router := MustRouter(RouterConfig{
+		Key:     "tenant",
+		Open:    func(string) (io.Writer, error) { return nil, errors.New("quota exceeded") },
+		OnError: func(err error) { reported = err },
+	})
+
+	passed := false
+	NewPipeline(router.Stage()).Run(&Entry{Fields: Fields{"tenant": "acme"}}, func(*Entry) { passed = true })
+	if !passed {
+		t.Errorf("Expected the entry to continue down the pipeline")
+	}
+	if reported == nil || !strings.Contains(reported.Error(), "quota exceeded") {
+		t.Errorf("Expected the open error to be reported, got %v", reported)
+	}
+	if _, err := NewRouterE(RouterConfig{Key: "tenant"}); err == nil {
+		t.Errorf("Expected an error without Open")
+	}
+}
+
+// TestRouter_Context tests that the sinks do not inherit Config.Context, so
+// evicted sinks leave no goroutine behind.
+func TestRouter_Context(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	router := MustRouter(RouterConfig{
+		Key:    "tenant",
+		Open:   func(string) (io.Writer, error) { return new(closingBuffer), nil },
+		Config: Config{Level: InfoLevel, Context: ctx},
+	})
+
+	sink, err := router.acquire("a")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	router.release(sink)
+	if sink.logger.Config.Context != nil {
+		t.Errorf("Expected the sink not to watch the template context")
+	}
+}
+
+// gatedCloser is a writer whose writes wait for gate, recording whether it
+// was closed during a write.
+type gatedCloser struct {
+	gate    chan struct{}
+	started chan struct{}
+	mu      sync.Mutex
+	writing bool
+	closed  bool
+	early   bool
+	buf     bytes.Buffer
+}
+
+func (g *gatedCloser) Write(p []byte) (int, error) {
+	g.mu.Lock()
+	g.writing = true
+	g.mu.Unlock()
+	g.started <- struct{}{}
+	<-g.gate
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.writing = false
+	return g.buf.Write(p)
+}
+
+func (g *gatedCloser) Close() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.closed = true
+	g.early = g.early || g.writing
+	return nil
+}
+
+// TestRouter_EvictInUse tests that a sink evicted during a write is closed
+// once the write finishes.
+func TestRouter_EvictInUse(t *testing.T) {
+	slow := &gatedCloser{gate: make(chan struct{}), started: make(chan struct{}, 1)}
+	router := MustRouter(RouterConfig{
+		Key: "tenant",
+		Open: func(value string) (io.Writer, error) {
+			if value == "a" {
+				return slow, nil
+			}
+			return new(closingBuffer), nil
+		},
+		MaxOpen: 1,
+	})
+	stage := NewPipeline(router.Stage())
+	route := func(tenant string) {
+		stage.Run(&Entry{Level: InfoLevel, Message: "m", Fields: Fields{"tenant": tenant}}, func(*Entry) {})
+	}
+
+	done := make(chan struct{})
+	go func() {
+		route("a")
+		close(done)
+	}()
+	<-slow.started
+	route("b")
+
+	slow.mu.Lock()
+	closed := slow.closed
+	slow.mu.Unlock()
+	if closed {
+		t.Errorf("Expected the sink to stay open while written to")
+	}
+
+	close(slow.gate)
+	<-done
+	slow.mu.Lock()
+	defer slow.mu.Unlock()
+	if !slow.closed || slow.early || !strings.Contains(slow.buf.String(), `"tenant":"a"`) {
+		t.Errorf("Expected the sink to be closed after its write, got closed=%v early=%v output=%q", slow.closed, slow.early, slow.buf.String())
+	}
+}
@@ -0,0 +1,68 @@
+package logger
+
+import (
+	"reflect"
+	"regexp"
+)
+
+// LevelRule changes the level of entries that match it. An entry matches when
+// its level is one of From, its message matches Message, and every field in
+// Fields is present with an equal value; unset criteria match any entry.
+type LevelRule struct {
+	// From limits the rule to entries at these levels.
+	From []Level
+	// Message matches the entry's message.
+	Message *regexp.Regexp
+	// Fields must all be present in the entry with equal values.
+	Fields Fields
+	// To is the level given to matching entries.
+	To Level
+}
+
+// matches reports whether the rule applies to entry.
+func (r LevelRule) matches(entry *Entry) bool {
+	if len(r.From) > 0 {
+		found := false
+		for _, level := range r.From {
+			if entry.Level == level {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if r.Message != nil && !r.Message.MatchString(entry.Message) {
+		return false
+	}
+	return fieldsMatch(entry.Fields, r.Fields)
+}
+
+// fieldsMatch reports whether every field in want is present in fields with an
+// equal value.
+func fieldsMatch(fields, want Fields) bool {
+	for k, v := range want {
+		got, ok := fields[k]
+		if !ok || !reflect.DeepEqual(got, v) {
+			return false
+		}
+	}
+	return true
+}
+
+// LevelRules returns a Hook that promotes or demotes entries using the first
+// matching rule, e.g. demoting a noisy dependency's errors to warnings. Entries
+// demoted below the logger's level are dropped; entries already filtered out by
+// the level never reach the hook, so rules cannot promote them.
+func LevelRules(rules ...LevelRule) Hook {
+	return func(entry *Entry) error {
+		for _, rule := range rules {
+			if rule.matches(entry) {
+				entry.Level = rule.To
+				return nil
+			}
+		}
+		return nil
+	}
+}
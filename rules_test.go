@@ -0,0 +1,35 @@
+package logger
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// TestLevelRules tests that matching entries are promoted or demoted by the first matching rule.
+func TestLevelRules(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	zapLogger := NewZap(Config{Level: InfoLevel, Output: buffer})
+	zapLogger.AddHook(LevelRules(
+		LevelRule{From: []Level{ErrorLevel}, Fields: Fields{"component": "cache"}, To: WarnLevel},
+		LevelRule{Message: regexp.MustCompile(`^payment failed`), To: ErrorLevel},
+		LevelRule{Message: regexp.MustCompile(`heartbeat`), To: DebugLevel},
+	))
+
+	zapLogger.Error("connection reset", Fields{"component": "cache"})
+	zapLogger.Error("connection reset", Fields{"component": "db"})
+	zapLogger.Info("payment failed: card declined", nil)
+	zapLogger.Info("heartbeat", nil)
+
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Expected 3 entries, got %d: %s", len(lines), buffer.String())
+	}
+	expected := []string{`"level":"warn"`, `"level":"error"`, `"level":"error"`}
+	for i, level := range expected {
+		if !strings.Contains(lines[i], level) {
+			t.Errorf("Expected entry %d to contain %s, got %s", i, level, lines[i])
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package logger
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// sanitizeFields sanitizes the keys and string values of zapFields in place.
+func (c *fieldConverter) sanitizeFields(zapFields []zap.Field) []zap.Field {
+	if !c.sanitize {
+		return zapFields
+	}
+	for i := range zapFields {
+		f := &zapFields[i]
+		f.Key = sanitizeString(f.Key)
+		if f.Type == zapcore.StringType {
+			f.String = sanitizeString(f.String)
+		}
+	}
+	return zapFields
+}
+
+// sanitizeMessage sanitizes msg if sanitization is enabled.
+func (c *fieldConverter) sanitizeMessage(msg string) string {
+	if !c.sanitize {
+		return msg
+	}
+	return sanitizeString(msg)
+}
+
+// sanitizeString replaces invalid UTF-8 sequences with U+FFFD and control
+// characters with their escaped form, e.g. \n or \x1b.
+func sanitizeString(s string) string {
+	clean := true
+	for _, r := range s {
+		if r == utf8.RuneError || isControl(r) {
+			clean = false
+			break
+		}
+	}
+	if clean {
+		return s
+	}
+
+	var b strings.Builder
+	b.Grow(len(s))
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+		switch {
+		case r == utf8.RuneError:
+			b.WriteRune(utf8.RuneError)
+		case r == '\n':
+			b.WriteString(`\n`)
+		case r == '\r':
+			b.WriteString(`\r`)
+		case r == '\t':
+			b.WriteString(`\t`)
+		case isControl(r):
+			fmt.Fprintf(&b, `\x%02x`, r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// isControl reports whether r is a C0 or C1 control character or DEL.
+func isControl(r rune) bool {
+	return r < 0x20 || (r >= 0x7f && r <= 0x9f)
+}
@@ -0,0 +1,36 @@
+package logger
+
+import (
+	"bytes"
+	"testing"
+)
+
+// TestSanitizeString tests that invalid UTF-8 and control characters are replaced.
+func TestSanitizeString(t *testing.T) {
+	tests := map[string]string{
+		"plain":            "plain",
+		"日本語":              "日本語",
+		"bad\xffbyte":      "bad�byte",
+		"line\nbreak\ttab": `line\nbreak\ttab`,
+		"\x1b[31mred":      `\x1b[31mred`,
+		"del\x7f":          `del\x7f`,
+	}
+	for input, expected := range tests {
+		if got := sanitizeString(input); got != expected {
+			t.Errorf("Expected %q to sanitize to %q, got %q", input, expected, got)
+		}
+	}
+}
+
+// TestSanitizeUTF8 tests that messages, keys, and string values are sanitized.
+func TestSanitizeUTF8(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	zapLogger := NewZap(Config{Level: InfoLevel, Output: buffer, SanitizeUTF8: true})
+
+	zapLogger.Info("Info\nmessage", Fields{"user\x00": "a\xffb"})
+	for _, expected := range []string{`"msg":"Info\\nmessage"`, `"user\\x00":"a�b"`} {
+		if !bytes.Contains(buffer.Bytes(), []byte(expected)) {
+			t.Errorf("Expected %s to contain %s", buffer.String(), expected)
+		}
+	}
+}
@@ -0,0 +1,241 @@
+package logger
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// FieldType is the type a Schema expects of a field value.
+type FieldType int
+
+const (
+	// FieldAny accepts any value.
+	FieldAny FieldType = iota
+	// FieldString accepts strings.
+	FieldString
+	// FieldInt accepts signed and unsigned integers.
+	FieldInt
+	// FieldFloat accepts floating-point numbers and integers.
+	FieldFloat
+	// FieldBool accepts booleans.
+	FieldBool
+	// FieldTime accepts time.Time values.
+	FieldTime
+	// FieldDuration accepts time.Duration values.
+	FieldDuration
+	// FieldError accepts errors.
+	FieldError
+	// FieldObject accepts maps, structs, and zapcore.ObjectMarshalers.
+	FieldObject
+	// FieldArray accepts slices, arrays, and zapcore.ArrayMarshalers.
+	FieldArray
+)
+
+// fieldTypeNames holds the names of the field types, indexed by FieldType.
+var fieldTypeNames = [...]string{"any", "string", "int", "float", "bool", "time", "duration", "error", "object", "array"}
+
+// String returns the name of the field type.
+func (t FieldType) String() string {
+	if t >= FieldAny && t <= FieldArray {
+		return fieldTypeNames[t]
+	}
+	return fmt.Sprintf("FieldType(%d)", int(t))
+}
+
+// FieldSpec declares a field of a Schema.
+type FieldSpec struct {
+	// Type is the expected type of the value.
+	Type FieldType
+	// Required makes entries without the field violate the schema.
+	Required bool
+}
+
+// SchemaMode selects what happens to entries that violate a Schema.
+type SchemaMode int
+
+const (
+	// SchemaAnnotate writes violating entries with a "schema_violations" field
+	// listing the violations.
+	SchemaAnnotate SchemaMode = iota
+	// SchemaReport writes violating entries unchanged and reports the
+	// violations as operational errors on Config.ErrorOutput and Config.OnError.
+	SchemaReport
+	// SchemaReject drops violating entries and reports the violations as
+	// operational errors.
+	SchemaReject
+)
+
+// Schema is a contract for the fields of every entry, keeping field types
+// consistent for the indexers that consume the logs. Fields bound with With
+// are checked when they are bound and count as present in later entries.
+type Schema struct {
+	// Fields declares the known fields by key.
+	Fields map[string]FieldSpec
+	// Closed makes fields that are not declared violate the schema.
+	Closed bool
+	// Mode selects what happens to violating entries; defaults to SchemaAnnotate.
+	Mode SchemaMode
+}
+
+// schemaViolationsKey is the key of the field listing violations under SchemaAnnotate.
+const schemaViolationsKey = "schema_violations"
+
+// enabled reports whether the schema declares anything to check.
+func (s Schema) enabled() bool {
+	return len(s.Fields) > 0 || s.Closed
+}
+
+// validate reports settings that are out of range.
+func (s Schema) validate() error {
+	if s.Mode < SchemaAnnotate || s.Mode > SchemaReject {
+		return fmt.Errorf("invalid schema mode %d", s.Mode)
+	}
+	for key, spec := range s.Fields {
+		if spec.Type < FieldAny || spec.Type > FieldArray {
+			return fmt.Errorf("invalid type %d for schema field %q", spec.Type, key)
+		}
+	}
+	return nil
+}
+
+// violations returns the violations of fields, sorted, given the keys bound
+// earlier with With; required fields are only checked when checkRequired is set.
+func (s Schema) violations(fields Fields, bound map[string]struct{}, checkRequired bool) []string {
+	var found []string
+	for key, value := range fields {
+		if _, ok := value.(routeTarget); ok {
+			continue
+		}
+		spec, ok := s.Fields[key]
+		if !ok {
+			if s.Closed {
+				found = append(found, fmt.Sprintf("undeclared field %q", key))
+			}
+			continue
+		}
+		if !spec.Type.matches(value) {
+			found = append(found, fmt.Sprintf("field %q: expected %s, got %T", key, spec.Type, value))
+		}
+	}
+	if checkRequired {
+		for key, spec := range s.Fields {
+			if !spec.Required {
+				continue
+			}
+			if _, ok := fields[key]; ok {
+				continue
+			}
+			if _, ok := bound[key]; !ok {
+				found = append(found, fmt.Sprintf("missing required field %q", key))
+			}
+		}
+	}
+	sort.Strings(found)
+	return found
+}
+
+// matches reports whether value has type t.
+func (t FieldType) matches(value interface{}) bool {
+	switch value.(type) {
+	case nil:
+		return t == FieldAny
+	case time.Time, *time.Time:
+		return t == FieldAny || t == FieldTime
+	case time.Duration, *time.Duration, humanDuration:
+		return t == FieldAny || t == FieldDuration
+	case humanBytes:
+		return t == FieldAny || t == FieldInt || t == FieldFloat
+	case error:
+		return t == FieldAny || t == FieldError
+	case zapcore.ObjectMarshaler:
+		return t == FieldAny || t == FieldObject
+	case zapcore.ArrayMarshaler:
+		return t == FieldAny || t == FieldArray
+	}
+	switch kind := reflect.TypeOf(value).Kind(); kind {
+	case reflect.String:
+		return t == FieldAny || t == FieldString
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return t == FieldAny || t == FieldInt || t == FieldFloat
+	case reflect.Float32, reflect.Float64:
+		return t == FieldAny || t == FieldFloat
+	case reflect.Bool:
+		return t == FieldAny || t == FieldBool
+	case reflect.Map, reflect.Struct:
+		return t == FieldAny || t == FieldObject
+	case reflect.Slice, reflect.Array:
+		return t == FieldAny || t == FieldArray
+	case reflect.Ptr:
+		// A nil pointer is written as null, like a nil value.
+		if v := reflect.ValueOf(value); !v.IsNil() {
+			return t == FieldAny || t.matches(v.Elem().Interface())
+		}
+		return t == FieldAny
+	}
+	return t == FieldAny
+}
+
+// enforceSchema checks fields against the schema of z and returns the fields
+// to write, or false if the entry is rejected.
+func (z *Zap) enforceSchema(msg string, fields Fields) (Fields, bool) {
+	schema := z.Config.Schema
+	violations := schema.violations(fields, z.bound, true)
+	if len(violations) == 0 {
+		return fields, true
+	}
+	switch schema.Mode {
+	case SchemaReport:
+		z.state.reportError(schemaError(msg, violations))
+	case SchemaReject:
+		z.state.reportError(fmt.Errorf("%w, entry dropped", schemaError(msg, violations)))
+		return nil, false
+	default:
+		annotated := make(Fields, len(fields)+1)
+		for k, v := range fields {
+			annotated[k] = v
+		}
+		annotated[schemaViolationsKey] = violations
+		return annotated, true
+	}
+	return fields, true
+}
+
+// schemaError describes the violations of an entry.
+func schemaError(msg string, violations []string) error {
+	return fmt.Errorf("schema: entry %q: %s", msg, strings.Join(violations, "; "))
+}
+
+// bindSchema checks fields bound with With against the schema of z and returns
+// the fields to bind and the keys bound so far. Violations are bound as
+// schema_violations under SchemaAnnotate and reported otherwise.
+func (z *Zap) bindSchema(fields Fields) (Fields, map[string]struct{}) {
+	bound := make(map[string]struct{}, len(z.bound)+len(fields))
+	for k := range z.bound {
+		bound[k] = struct{}{}
+	}
+	for k := range fields {
+		bound[k] = struct{}{}
+	}
+
+	schema := z.Config.Schema
+	violations := schema.violations(fields, nil, false)
+	if len(violations) == 0 {
+		return fields, bound
+	}
+	if schema.Mode != SchemaAnnotate {
+		z.state.reportError(fmt.Errorf("schema: bound fields: %s", strings.Join(violations, "; ")))
+		return fields, bound
+	}
+	annotated := make(Fields, len(fields)+1)
+	for k, v := range fields {
+		annotated[k] = v
+	}
+	annotated[schemaViolationsKey] = violations
+	return annotated, bound
+}
@@ -0,0 +1,112 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testSchema declares the fields used by the schema tests.
+var testSchema = map[string]FieldSpec{
+	"user_id":  {Type: FieldString, Required: true},
+	"status":   {Type: FieldInt},
+	"latency":  {Type: FieldDuration},
+	"amount":   {Type: FieldFloat},
+	"err":      {Type: FieldError},
+	"tags":     {Type: FieldArray},
+	"metadata": {Type: FieldObject},
+}
+
+// TestSchema_Annotate tests that violations are listed on the entry.
+func TestSchema_Annotate(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	zapLogger := NewZap(Config{Level: InfoLevel, Output: buffer, Schema: Schema{Fields: testSchema, Closed: true}})
+
+	zapLogger.Info("ok", Fields{
+		"user_id":  "u1",
+		"status":   200,
+		"latency":  time.Second,
+		"amount":   3,
+		"err":      errors.New("e"),
+		"tags":     []string{"a"},
+		"metadata": map[string]string{"k": "v"},
+	})
+	if bytes.Contains(buffer.Bytes(), []byte(schemaViolationsKey)) {
+		t.Errorf("Expected no violations, got %s", buffer.String())
+	}
+
+	buffer.Reset()
+	zapLogger.InfoFields("bad", String("status", "200"), Int("retries", 3))
+	expected := `"schema_violations":["field \"status\": expected int, got string","missing required field \"user_id\"","undeclared field \"retries\""]`
+	if !strings.Contains(buffer.String(), expected) {
+		t.Errorf("Expected %s to contain %s", buffer.String(), expected)
+	}
+
+	buffer.Reset()
+	zapLogger.With(Fields{"user_id": "u1"}).Info("bound", nil)
+	if bytes.Contains(buffer.Bytes(), []byte(schemaViolationsKey)) {
+		t.Errorf("Expected bound fields to satisfy required fields, got %s", buffer.String())
+	}
+
+	buffer.Reset()
+	zapLogger.With(Fields{"user_id": 7}).Info("bound", nil)
+	if !strings.Contains(buffer.String(), `"schema_violations":["field \"user_id\": expected string, got int"]`) {
+		t.Errorf("Expected bound fields to be checked, got %s", buffer.String())
+	}
+}
+
+// TestSchema_Reject tests that violating entries are dropped and reported.
+func TestSchema_Reject(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	errorOutput := new(bytes.Buffer)
+	var reported []error
+	zapLogger := NewZap(Config{
+		Level:       InfoLevel,
+		Output:      buffer,
+		ErrorOutput: errorOutput,
+		OnError:     func(err error) { reported = append(reported, err) },
+		Schema:      Schema{Fields: testSchema, Mode: SchemaReject},
+	})
+
+	zapLogger.Info("payment", Fields{"user_id": "u1", "amount": "12.50"})
+	if buffer.Len() != 0 {
+		t.Errorf("Expected the entry to be dropped, got %s", buffer.String())
+	}
+	if len(reported) != 1 || !strings.Contains(errorOutput.String(), `schema: entry "payment": field "amount": expected float, got string, entry dropped`) {
+		t.Errorf("Expected the violation to be reported, got %v %s", reported, errorOutput.String())
+	}
+
+	zapLogger.Info("payment", Fields{"user_id": "u1", "amount": 12.5, "undeclared": true})
+	if !strings.Contains(buffer.String(), `"undeclared":true`) {
+		t.Errorf("Expected undeclared fields to be allowed by an open schema, got %s", buffer.String())
+	}
+
+	if _, err := NewZapE(Config{Output: buffer, Schema: Schema{Mode: SchemaReject + 1}}); err == nil {
+		t.Errorf("Expected an error for an invalid schema mode")
+	}
+}
+
+// TestSchema_NilPointer tests that nil pointers are checked like nil values
+// instead of panicking.
+func TestSchema_NilPointer(t *testing.T) {
+	type user struct{ Name string }
+	buffer := new(bytes.Buffer)
+	zapLogger := NewZap(Config{Level: InfoLevel, Output: buffer, Schema: Schema{Fields: map[string]FieldSpec{
+		"user":  {Type: FieldObject},
+		"owner": {Type: FieldAny},
+	}}})
+
+	zapLogger.Info("nil pointers", Fields{"user": (*user)(nil), "owner": (*user)(nil)})
+	expected := `"schema_violations":["field \"user\": expected object, got *logger.user"]`
+	if !strings.Contains(buffer.String(), expected) {
+		t.Errorf("Expected %s to contain %s", buffer.String(), expected)
+	}
+
+	buffer.Reset()
+	zapLogger.Info("pointer", Fields{"user": &user{Name: "u1"}})
+	if bytes.Contains(buffer.Bytes(), []byte(schemaViolationsKey)) {
+		t.Errorf("Expected a non-nil pointer to match its element type, got %s", buffer.String())
+	}
+}
@@ -0,0 +1,50 @@
+package logger
+
+// SeverityMode controls whether entries carry an RFC 5424 numeric severity.
+type SeverityMode int
+
+const (
+	// SeverityOff writes only the level string.
+	SeverityOff SeverityMode = iota
+	// SeverityAdd writes the level string and a numeric severity field.
+	SeverityAdd
+	// SeverityOnly writes the numeric severity in place of the level string.
+	SeverityOnly
+)
+
+// severityKey is the key of the numeric severity field written by SeverityAdd.
+const severityKey = "severity"
+
+// SyslogSeverity returns the RFC 5424 severity number for level: 7 for debug,
+// 6 for info, 4 for warning, 3 for error, and 2 (critical) for fatal.
+func SyslogSeverity(level Level) int {
+	switch level {
+	case DebugLevel:
+		return 7
+	case InfoLevel:
+		return 6
+	case WarnLevel:
+		return 4
+	case ErrorLevel:
+		return 3
+	default:
+		return 2
+	}
+}
+
+// severityLevel returns the level whose SyslogSeverity is severity, mapping
+// severities above critical to FatalLevel.
+func severityLevel(severity int) Level {
+	switch {
+	case severity >= 7:
+		return DebugLevel
+	case severity == 6:
+		return InfoLevel
+	case severity >= 4:
+		return WarnLevel
+	case severity == 3:
+		return ErrorLevel
+	default:
+		return FatalLevel
+	}
+}
@@ -0,0 +1,36 @@
+package logger
+
+import (
+	"bytes"
+	"testing"
+)
+
+// TestSyslogSeverity tests the RFC 5424 severity of each level.
+func TestSyslogSeverity(t *testing.T) {
+	expected := map[Level]int{DebugLevel: 7, InfoLevel: 6, WarnLevel: 4, ErrorLevel: 3, FatalLevel: 2}
+	for level, severity := range expected {
+		if got := SyslogSeverity(level); got != severity {
+			t.Errorf("Expected severity %d for level %d, got %d", severity, level, got)
+		}
+	}
+}
+
+// TestSeverityMode tests that the numeric severity is added or replaces the level.
+func TestSeverityMode(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	NewZap(Config{Level: InfoLevel, Output: buffer, Severity: SeverityAdd}).Warn("Warn message", nil)
+	for _, expected := range []string{`"level":"warn"`, `"severity":4`} {
+		if !bytes.Contains(buffer.Bytes(), []byte(expected)) {
+			t.Errorf("Expected %s to contain %s", buffer.String(), expected)
+		}
+	}
+
+	buffer.Reset()
+	NewZap(Config{Level: InfoLevel, Output: buffer, Severity: SeverityOnly}).Error("Error message", nil)
+	if !bytes.Contains(buffer.Bytes(), []byte(`"level":3`)) {
+		t.Errorf("Expected numeric level, got %s", buffer.String())
+	}
+	if bytes.Contains(buffer.Bytes(), []byte(`"severity"`)) {
+		t.Errorf("Expected no severity field, got %s", buffer.String())
+	}
+}
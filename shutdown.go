@@ -0,0 +1,103 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync/atomic"
+)
+
+// AbandonedError reports entries that were discarded because a shutdown
+// deadline expired before they could be written.
+type AbandonedError struct {
+	// Abandoned is the number of discarded entries.
+	Abandoned int
+	// Err is the context error that ended the shutdown.
+	Err error
+}
+
+// Error implements error.
+func (e *AbandonedError) Error() string {
+	return fmt.Sprintf("logger: shutdown abandoned %d entries: %v", e.Abandoned, e.Err)
+}
+
+// Unwrap returns the context error.
+func (e *AbandonedError) Unwrap() error {
+	return e.Err
+}
+
+// shutdowner is implemented by outputs that drain queued entries on shutdown.
+type shutdowner interface {
+	Shutdown(ctx context.Context) error
+}
+
+// shutdownOnDone shuts z down once ctx is canceled, for Config.Context, and
+// returns early if z is shut down first.
+func (z *Zap) shutdownOnDone(ctx context.Context) {
+	select {
+	case <-ctx.Done():
+	case <-z.state.done:
+		return
+	}
+
+	z.state.mu.Lock()
+	if w := z.state.window; w != nil {
+		w.timer.Stop()
+		z.state.window = nil
+	}
+	z.state.mu.Unlock()
+
+	if err := z.Shutdown(context.Background()); err != nil {
+		z.state.reportError(err)
+	}
+}
+
+// Shutdown stops z and every logger sharing its state from accepting entries,
+// then drains and flushes its output, destinations, and event output: outputs with a
+// Shutdown method, such as *AsyncWriter, are shut down and the others synced.
+// It returns when done or when ctx expires, in which case the returned error
+// is an *AbandonedError counting the entries that were discarded.
+func (z *Zap) Shutdown(ctx context.Context) error {
+	atomic.StoreUint32(&z.state.closed, 1)
+	z.state.doneOnce.Do(func() { close(z.state.done) })
+
+	outputs := []io.Writer{z.Config.Output}
+	for _, dest := range z.Config.Destinations {
+		outputs = append(outputs, dest.Output)
+	}
+	if z.Config.EventOutput != nil {
+		outputs = append(outputs, z.Config.EventOutput)
+	}
+
+	abandoned := 0
+	var failures []string
+	for _, output := range outputs {
+		if output == nil || output == os.Stdout || output == os.Stderr {
+			continue
+		}
+		var err error
+		switch w := output.(type) {
+		case shutdowner:
+			err = w.Shutdown(ctx)
+		case syncer:
+			err = w.Sync()
+		}
+		var abandonedErr *AbandonedError
+		if errors.As(err, &abandonedErr) {
+			abandoned += abandonedErr.Abandoned
+		} else if err != nil {
+			failures = append(failures, err.Error())
+		}
+	}
+
+	if abandoned > 0 {
+		return &AbandonedError{Abandoned: abandoned, Err: ctx.Err()}
+	}
+	if len(failures) > 0 {
+		return fmt.Errorf("logger: shutdown: %s", strings.Join(failures, "; "))
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestZap_Shutdown tests that Shutdown drains async outputs and stops logging.
+func TestZap_Shutdown(t *testing.T) {
+	output := &syncBuffer{}
+	zapLogger := NewZap(Config{Level: InfoLevel, Output: NewAsyncWriter(AsyncConfig{Output: output})})
+
+	for i := 0; i < 10; i++ {
+		zapLogger.Info("Info message", nil)
+	}
+	if err := zapLogger.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if n := strings.Count(output.String(), "\n"); n != 10 {
+		t.Errorf("Expected 10 drained entries, got %d", n)
+	}
+	if output.syncs == 0 {
+		t.Errorf("Expected the output to be synced")
+	}
+
+	zapLogger.Error("Error message", nil)
+	if Enabled(zapLogger, FatalLevel) || strings.Count(output.String(), "\n") != 10 {
+		t.Errorf("Expected no entries after Shutdown")
+	}
+}
+
+// TestZap_ShutdownDeadline tests that entries left when the deadline expires are reported.
+func TestZap_ShutdownDeadline(t *testing.T) {
+	output := &gatedWriter{gate: make(chan struct{}), started: make(chan struct{}, 1)}
+	defer close(output.gate)
+	async := NewAsyncWriter(AsyncConfig{Output: output, QueueSize: 10})
+	zapLogger := NewZap(Config{Level: InfoLevel, Output: async})
+
+	zapLogger.Info("first", nil)
+	<-output.started
+	zapLogger.Info("second", nil)
+	zapLogger.Info("third", nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err := zapLogger.Shutdown(ctx)
+
+	var abandoned *AbandonedError
+	if !errors.As(err, &abandoned) || abandoned.Abandoned != 2 {
+		t.Fatalf("Expected 2 abandoned entries, got %v", err)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the deadline error to be wrapped, got %v", err)
+	}
+}
+
+// TestZap_Context tests that canceling Config.Context shuts the logger down.
+func TestZap_Context(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	output := &syncBuffer{}
+	async := NewAsyncWriter(AsyncConfig{Output: output})
+	zapLogger := NewZap(Config{Level: InfoLevel, Output: async, Context: ctx})
+	zapLogger.SetLevelFor(DebugLevel, time.Hour)
+
+	for i := 0; i < 10; i++ {
+		zapLogger.Info("Info message", nil)
+	}
+	cancel()
+
+	select {
+	case <-async.stopped:
+	case <-time.After(time.Second):
+		t.Fatalf("Expected the async writer to stop once the context is canceled")
+	}
+	if n := strings.Count(output.String(), "\n"); n != 10 {
+		t.Errorf("Expected 10 drained entries, got %d", n)
+	}
+	zapLogger.state.mu.Lock()
+	window := zapLogger.state.window
+	zapLogger.state.mu.Unlock()
+	if window != nil {
+		t.Errorf("Expected the level window to end")
+	}
+	if Enabled(zapLogger, FatalLevel) {
+		t.Errorf("Expected no entries after the context is canceled")
+	}
+}
+
+// TestZap_ContextShutdown tests that Shutdown ends the goroutine watching Config.Context.
+func TestZap_ContextShutdown(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	before := runtime.NumGoroutine()
+	zapLogger := NewZap(Config{Level: InfoLevel, Output: &syncBuffer{}, Context: ctx})
+	if err := zapLogger.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > before {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected the context goroutine to exit after Shutdown, got %d goroutines, want %d", runtime.NumGoroutine(), before)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,148 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// SplitConfig holds the configuration for a split writer.
+type SplitConfig struct {
+	// Dir is the directory holding one file per component.
+	Dir string
+	// Key is the field naming the component of an entry; defaults to "logger",
+	// e.g. as bound with log.With(logger.Fields{"logger": "http"}).
+	Key string
+	// Default names the file of entries without Key, or whose value is not a
+	// string; defaults to "app".
+	Default string
+	// Ext is the extension of the files; defaults to ".log".
+	Ext string
+	// File is the rotation policy shared by every file; its Path is ignored.
+	File FileConfig
+}
+
+// SplitWriter is an output that writes the JSON entries of each component to
+// its own file under a directory, e.g. http.log, db.log, and worker.log, for
+// operators who prefer per-component files. The files are opened on first use
+// and rotated according to the shared FileConfig.
+type SplitWriter struct {
+	config SplitConfig
+	mu     sync.Mutex
+	files  map[string]*FileWriter
+	closed bool
+}
+
+// NewSplitWriterE returns a new *SplitWriter, or an error if no directory is
+// configured.
+func NewSplitWriterE(config SplitConfig) (*SplitWriter, error) {
+	if config.Dir == "" {
+		return nil, errors.New("split: directory is not configured")
+	}
+	if config.File.MaxBytes < 0 || config.File.MaxBackups < 0 {
+		return nil, errors.New("split: negative MaxBytes or MaxBackups")
+	}
+	if config.Key == "" {
+		config.Key = "logger"
+	}
+	if config.Default == "" {
+		config.Default = "app"
+	}
+	if config.Ext == "" {
+		config.Ext = ".log"
+	}
+	return &SplitWriter{config: config, files: make(map[string]*FileWriter)}, nil
+}
+
+// MustSplitWriter is like NewSplitWriterE but panics on error.
+func MustSplitWriter(config SplitConfig) *SplitWriter {
+	w, err := NewSplitWriterE(config)
+	if err != nil {
+		panic(err)
+	}
+	return w
+}
+
+// Write appends a single JSON entry to the file of its component. Entries that
+// are not JSON objects go to the default file.
+func (w *SplitWriter) Write(p []byte) (int, error) {
+	name := w.config.Default
+	if entry, err := decodeEntry(p); err == nil {
+		if value, ok := entry[w.config.Key].(string); ok && value != "" {
+			name = value
+		}
+	}
+
+	file, err := w.file(fileNameSafe(name))
+	if err != nil {
+		return 0, err
+	}
+	return file.Write(p)
+}
+
+// file returns the writer of the named file, opening it on first use.
+func (w *SplitWriter) file(name string) (*FileWriter, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return nil, errors.New("split: writer is closed")
+	}
+	if file, ok := w.files[name]; ok {
+		return file, nil
+	}
+	config := w.config.File
+	config.Path = filepath.Join(w.config.Dir, name+w.config.Ext)
+	file, err := NewFileWriterE(config)
+	if err != nil {
+		return nil, fmt.Errorf("split: %w", err)
+	}
+	w.files[name] = file
+	return file, nil
+}
+
+// Files returns the paths of the files currently written, sorted.
+func (w *SplitWriter) Files() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	paths := make([]string, 0, len(w.files))
+	for _, file := range w.files {
+		paths = append(paths, file.Name())
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// Sync flushes every file, returning the first error.
+func (w *SplitWriter) Sync() error {
+	return w.each(func(file *FileWriter) error { return file.Sync() })
+}
+
+// Close closes every file, returning the first error. Later writes fail.
+func (w *SplitWriter) Close() error {
+	w.mu.Lock()
+	w.closed = true
+	w.mu.Unlock()
+	return w.each(func(file *FileWriter) error { return file.Close() })
+}
+
+// each calls fn for every open file, returning the first error.
+func (w *SplitWriter) each(fn func(*FileWriter) error) error {
+	w.mu.Lock()
+	files := make([]*FileWriter, 0, len(w.files))
+	for _, file := range w.files {
+		files = append(files, file)
+	}
+	w.mu.Unlock()
+
+	var first error
+	for _, file := range files {
+		if err := fn(file); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
@@ -0,0 +1,80 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestSplitWriter tests that entries are written to one file per component.
+func TestSplitWriter(t *testing.T) {
+	dir := t.TempDir()
+	split := MustSplitWriter(SplitConfig{Dir: dir})
+	zapLogger := NewZap(Config{Level: InfoLevel, Output: split, DisableCaller: true})
+
+	zapLogger.With(Fields{"logger": "http"}).Info("request served", nil)
+	zapLogger.With(Fields{"logger": "db"}).Info("query run", nil)
+	zapLogger.With(Fields{"logger": "http"}).Info("request failed", nil)
+	zapLogger.With(Fields{"logger": "../etc/passwd"}).Info("escaped", nil)
+	zapLogger.Info("started", nil)
+	if err := split.Close(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := []string{
+		filepath.Join(dir, ".._etc_passwd.log"),
+		filepath.Join(dir, "app.log"),
+		filepath.Join(dir, "db.log"),
+		filepath.Join(dir, "http.log"),
+	}
+	if fmt.Sprint(split.Files()) != fmt.Sprint(expected) {
+		t.Errorf("Expected files %v, got %v", expected, split.Files())
+	}
+	for name, messages := range map[string][]string{
+		"http.log": {"request served", "request failed"},
+		"db.log":   {"query run"},
+		"app.log":  {"started"},
+	} {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if n := strings.Count(string(data), "\n"); n != len(messages) {
+			t.Errorf("Expected %d entries in %s, got %d", len(messages), name, n)
+		}
+		for _, msg := range messages {
+			if !strings.Contains(string(data), msg) {
+				t.Errorf("Expected %q in %s, got %q", msg, name, data)
+			}
+		}
+	}
+
+	if _, err := split.Write([]byte("{}\n")); err == nil {
+		t.Errorf("Expected an error writing after Close")
+	}
+}
+
+// TestSplitWriter_Rotate tests that every file follows the shared rotation policy.
+func TestSplitWriter_Rotate(t *testing.T) {
+	dir := t.TempDir()
+	split := MustSplitWriter(SplitConfig{Dir: dir, Key: "component", File: FileConfig{MaxBytes: 30}})
+	defer split.Close()
+
+	for i := 0; i < 2; i++ {
+		split.Write([]byte(`{"component":"worker","msg":"job done"}` + "\n"))
+	}
+
+	matches, _ := filepath.Glob(filepath.Join(dir, "worker-*.log"))
+	if len(matches) != 1 {
+		t.Errorf("Expected 1 rotated worker file, got %v", matches)
+	}
+}
+
+// TestNewSplitWriterE tests that a directory is required.
+func TestNewSplitWriterE(t *testing.T) {
+	if _, err := NewSplitWriterE(SplitConfig{}); err == nil {
+		t.Errorf("Expected an error without a directory")
+	}
+}
@@ -0,0 +1,209 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// SpoolConfig holds the configuration for a spooling writer.
+type SpoolConfig struct {
+	// Output is the remote writer entries are destined for.
+	Output io.Writer
+	// Path is the local spool file. Entries left in it by a previous run are
+	// replayed once the output accepts writes.
+	Path string
+	// MaxBytes caps the size of the spool file; entries that do not fit are
+	// dropped. Zero means no cap.
+	MaxBytes int64
+	// RetryInterval is the minimum time between attempts to replay the spool
+	// while the output is failing; defaults to 5 seconds.
+	RetryInterval time.Duration
+	// Clock times the retry interval; defaults to the system clock.
+	Clock Clock
+}
+
+// SpoolWriter is a writer that appends entries to a local spool file while its
+// output is unavailable and replays them, in order, when it recovers. New
+// entries queue behind spooled ones, so the output always receives entries in
+// the order they were written.
+type SpoolWriter struct {
+	// dropped is accessed atomically and must stay first, where 64-bit
+	// alignment is guaranteed on 32-bit platforms.
+	dropped   uint64
+	config    SpoolConfig
+	mu        sync.Mutex
+	size      int64
+	lastRetry time.Time
+}
+
+// NewSpoolWriterE returns a new *SpoolWriter, or an error if the output or path
+// is missing or an existing spool file cannot be read.
+func NewSpoolWriterE(config SpoolConfig) (*SpoolWriter, error) {
+	if config.Output == nil {
+		return nil, errors.New("spool: output is not configured")
+	}
+	if config.Path == "" {
+		return nil, errors.New("spool: path is not configured")
+	}
+	if config.RetryInterval <= 0 {
+		config.RetryInterval = 5 * time.Second
+	}
+
+	w := &SpoolWriter{config: config}
+	info, err := os.Stat(config.Path)
+	switch {
+	case err == nil:
+		w.size = info.Size()
+	case !errors.Is(err, os.ErrNotExist):
+		return nil, fmt.Errorf("spool: %w", err)
+	}
+	return w, nil
+}
+
+// MustSpoolWriter is like NewSpoolWriterE but panics on error.
+func MustSpoolWriter(config SpoolConfig) *SpoolWriter {
+	w, err := NewSpoolWriterE(config)
+	if err != nil {
+		panic(err)
+	}
+	return w
+}
+
+// Write sends p to the output, or appends it to the spool if the output fails
+// or earlier entries are still spooled.
+func (w *SpoolWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.size > 0 && w.now().Sub(w.lastRetry) >= w.config.RetryInterval {
+		_ = w.replay()
+	}
+	if w.size == 0 {
+		if _, err := w.config.Output.Write(p); err == nil {
+			return len(p), nil
+		}
+		w.lastRetry = w.now()
+	}
+	if err := w.spool(p); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Sync replays the spool and flushes the output if it supports it.
+func (w *SpoolWriter) Sync() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if err := w.replay(); err != nil {
+		return err
+	}
+	if s, ok := w.config.Output.(syncer); ok {
+		return s.Sync()
+	}
+	return nil
+}
+
+// Spooled returns the size in bytes of the entries waiting in the spool.
+func (w *SpoolWriter) Spooled() int64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.size
+}
+
+// Dropped returns the number of entries dropped because the spool was full.
+func (w *SpoolWriter) Dropped() uint64 {
+	return atomic.LoadUint64(&w.dropped)
+}
+
+// Health reports an error while entries are waiting in the spool.
+func (w *SpoolWriter) Health(ctx context.Context) error {
+	if n := w.Spooled(); n > 0 {
+		return fmt.Errorf("spool: %d bytes waiting for the output", n)
+	}
+	return nil
+}
+
+// spool appends p to the spool file. The caller must hold w.mu.
+func (w *SpoolWriter) spool(p []byte) error {
+	if w.config.MaxBytes > 0 && w.size+int64(len(p)) > w.config.MaxBytes {
+		atomic.AddUint64(&w.dropped, 1)
+		return errors.New("spool: full, entry dropped")
+	}
+
+	file, err := os.OpenFile(w.config.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("spool: %w", err)
+	}
+	n, err := file.Write(p)
+	w.size += int64(n)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("spool: %w", err)
+	}
+	return nil
+}
+
+// replay sends the spooled entries to the output in order, keeping those
+// after the first failure in the spool. The caller must hold w.mu.
+func (w *SpoolWriter) replay() error {
+	if w.size == 0 {
+		return nil
+	}
+	w.lastRetry = w.now()
+
+	data, err := os.ReadFile(w.config.Path)
+	if err != nil {
+		return fmt.Errorf("spool: %w", err)
+	}
+	for len(data) > 0 {
+		line := data
+		if i := bytes.IndexByte(data, '\n'); i >= 0 {
+			line = data[:i+1]
+		}
+		if _, err := w.config.Output.Write(line); err != nil {
+			if rewriteErr := w.rewrite(data); rewriteErr != nil {
+				return rewriteErr
+			}
+			return fmt.Errorf("spool: replay: %w", err)
+		}
+		data = data[len(line):]
+	}
+
+	w.size = 0
+	if err := os.Remove(w.config.Path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("spool: %w", err)
+	}
+	return nil
+}
+
+// rewrite replaces the spool with the entries not yet replayed. The caller
+// must hold w.mu.
+func (w *SpoolWriter) rewrite(remaining []byte) error {
+	tmp := w.config.Path + ".tmp"
+	if err := os.WriteFile(tmp, remaining, 0o600); err != nil {
+		return fmt.Errorf("spool: %w", err)
+	}
+	if err := os.Rename(tmp, w.config.Path); err != nil {
+		return fmt.Errorf("spool: %w", err)
+	}
+	w.size = int64(len(remaining))
+	return nil
+}
+
+// now returns the current time from the configured clock.
+func (w *SpoolWriter) now() time.Time {
+	if w.config.Clock != nil {
+		return w.config.Clock.Now()
+	}
+	return time.Now()
+}
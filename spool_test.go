@@ -0,0 +1,78 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestSpoolWriter tests that entries are spooled while the output fails and
+// replayed in order when it recovers.
+func TestSpoolWriter(t *testing.T) {
+	remote := &flakyWriter{}
+	clock := &manualClock{now: time.Unix(0, 0)}
+	path := filepath.Join(t.TempDir(), "remote.spool")
+	writer := MustSpoolWriter(SpoolConfig{Output: remote, Path: path, RetryInterval: time.Minute, Clock: clock})
+
+	writer.Write([]byte("a\n"))
+	remote.fail = true
+	writer.Write([]byte("b\n"))
+	writer.Write([]byte("c\n"))
+	if n := writer.Spooled(); n != 4 {
+		t.Errorf("Expected 4 spooled bytes, got %d", n)
+	}
+
+	remote.fail = false
+	writer.Write([]byte("d\n"))
+	if remote.written.String() != "a\n" {
+		t.Errorf("Expected no replay before the retry interval, got %q", remote.written.String())
+	}
+
+	clock.now = clock.now.Add(time.Minute)
+	writer.Write([]byte("e\n"))
+	if got := remote.written.String(); got != "a\nb\nc\nd\ne\n" {
+		t.Errorf("Expected entries in order, got %q", got)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("Expected the spool file to be removed, got %v", err)
+	}
+}
+
+// TestSpoolWriter_Resume tests that a spool left by a previous run is replayed.
+func TestSpoolWriter_Resume(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "remote.spool")
+	if err := os.WriteFile(path, []byte("old\n"), 0o600); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	remote := &flakyWriter{}
+	writer := MustSpoolWriter(SpoolConfig{Output: remote, Path: path})
+	if err := writer.Sync(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if remote.written.String() != "old\n" {
+		t.Errorf("Expected the old entry to be replayed, got %q", remote.written.String())
+	}
+}
+
+// TestSpoolWriter_MaxBytes tests that entries beyond the cap are dropped.
+func TestSpoolWriter_MaxBytes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "remote.spool")
+	writer := MustSpoolWriter(SpoolConfig{Output: failingWriter{}, Path: path, MaxBytes: 4})
+
+	writer.Write([]byte("a\n"))
+	writer.Write([]byte("b\n"))
+	if _, err := writer.Write([]byte("c\n")); err == nil {
+		t.Errorf("Expected an error when the spool is full")
+	}
+	if writer.Dropped() != 1 || writer.Spooled() != 4 {
+		t.Errorf("Expected 1 dropped entry and 4 spooled bytes, got %d and %d", writer.Dropped(), writer.Spooled())
+	}
+	if err := writer.Sync(); err == nil {
+		t.Errorf("Expected the replay to fail")
+	}
+	if data, _ := os.ReadFile(path); string(data) != "a\nb\n" {
+		t.Errorf("Expected the spool to be kept, got %q", data)
+	}
+}
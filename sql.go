@@ -0,0 +1,229 @@
+package logger
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SQLPlaceholder selects the bind parameter syntax of the database driver.
+type SQLPlaceholder int
+
+const (
+	// SQLQuestion uses ? placeholders, as SQLite and MySQL drivers expect.
+	SQLQuestion SQLPlaceholder = iota
+	// SQLDollar uses $1, $2, ... placeholders, as PostgreSQL drivers expect.
+	SQLDollar
+)
+
+// sqlIdentifier matches the table names the SQL writer accepts.
+var sqlIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// SQLConfig holds the configuration for the SQL writer.
+type SQLConfig struct {
+	// DB is the database entries are inserted into.
+	DB *sql.DB
+	// Table is the table entries are inserted into; defaults to "logs".
+	Table string
+	// Placeholder is the driver's bind parameter syntax; defaults to SQLQuestion.
+	Placeholder SQLPlaceholder
+	// BatchSize is the number of entries inserted per transaction; defaults to 1.
+	BatchSize int
+	// MaxBuffered caps the entries kept for the next transaction after failed
+	// ones; the oldest are dropped beyond it. Defaults to 100 times BatchSize.
+	MaxBuffered int
+	// Timeout bounds a single batch insert; defaults to 10 seconds.
+	Timeout time.Duration
+	// KeyNames are the keys of the core entry fields; set it to the
+	// Config.KeyNames of the logger writing to the table.
+	KeyNames KeyNames
+	// OnError is called when a transaction triggered by Write fails. The
+	// entries stay buffered for the next one, and Health reports the failure.
+	OnError func(error)
+}
+
+// SQLWriter is an output that inserts the JSON entries written by Zap into a
+// table with ts, level, msg, and fields columns, the remaining fields being
+// stored as a JSON object. Create the table with MigrateSQL.
+type SQLWriter struct {
+	config  SQLConfig
+	insert  string
+	mu      sync.Mutex
+	batch   [][]interface{}
+	lastErr error
+}
+
+// NewSQLWriterE returns a new *SQLWriter, or an error if the database is
+// missing or the table name is not a plain identifier.
+func NewSQLWriterE(config SQLConfig) (*SQLWriter, error) {
+	if config.DB == nil {
+		return nil, errors.New("sql: database is not configured")
+	}
+	if config.Table == "" {
+		config.Table = "logs"
+	}
+	if !sqlIdentifier.MatchString(config.Table) {
+		return nil, fmt.Errorf("sql: invalid table name %q", config.Table)
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = 1
+	}
+	if config.MaxBuffered <= 0 {
+		config.MaxBuffered = 100 * config.BatchSize
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 10 * time.Second
+	}
+
+	placeholders := []string{"?", "?", "?", "?"}
+	if config.Placeholder == SQLDollar {
+		placeholders = []string{"$1", "$2", "$3", "$4"}
+	}
+	insert := fmt.Sprintf("INSERT INTO %s (ts, level, msg, fields) VALUES (%s)", config.Table, strings.Join(placeholders, ", "))
+	return &SQLWriter{config: config, insert: insert}, nil
+}
+
+// MustSQLWriter is like NewSQLWriterE but panics on error.
+func MustSQLWriter(config SQLConfig) *SQLWriter {
+	w, err := NewSQLWriterE(config)
+	if err != nil {
+		panic(err)
+	}
+	return w
+}
+
+// MigrateSQL creates the table used by the SQL writer and an index on its ts
+// column, if they do not exist yet.
+func MigrateSQL(ctx context.Context, db *sql.DB, table string) error {
+	if !sqlIdentifier.MatchString(table) {
+		return fmt.Errorf("sql: invalid table name %q", table)
+	}
+	statements := []string{
+		fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (ts TEXT NOT NULL, level TEXT NOT NULL, msg TEXT NOT NULL, fields TEXT)", table),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s_ts ON %s (ts)", table, table),
+	}
+	for _, statement := range statements {
+		if _, err := db.ExecContext(ctx, statement); err != nil {
+			return fmt.Errorf("sql: migrate: %w", err)
+		}
+	}
+	return nil
+}
+
+// Write decodes a single JSON entry and queues it for insertion. Once queued
+// the entry is accepted, even if the transaction it triggers fails, so that
+// wrappers such as RetryWriter do not queue it twice.
+func (w *SQLWriter) Write(p []byte) (int, error) {
+	row, err := sqlRowFromJSON(p, w.config.KeyNames)
+	if err != nil {
+		return 0, err
+	}
+
+	w.mu.Lock()
+	w.batch = append(w.batch, row)
+	full := len(w.batch) >= w.config.BatchSize
+	w.mu.Unlock()
+
+	if full {
+		if err := w.Sync(); err != nil && w.config.OnError != nil {
+			w.config.OnError(err)
+		}
+	}
+	return len(p), nil
+}
+
+// Sync inserts any buffered entries in a single transaction. If the
+// transaction fails, the entries are kept for the next one, up to MaxBuffered.
+func (w *SQLWriter) Sync() error {
+	w.mu.Lock()
+	batch := w.batch
+	w.batch = nil
+	w.mu.Unlock()
+
+	if len(batch) == 0 {
+		return nil
+	}
+
+	err := w.insertBatch(batch)
+	w.mu.Lock()
+	w.lastErr = err
+	if err != nil {
+		w.batch = append(batch, w.batch...)
+		if over := len(w.batch) - w.config.MaxBuffered; over > 0 {
+			w.batch = w.batch[over:]
+		}
+	}
+	w.mu.Unlock()
+	return err
+}
+
+// insertBatch inserts batch in a single transaction.
+func (w *SQLWriter) insertBatch(batch [][]interface{}) error {
+	ctx, cancel := context.WithTimeout(context.Background(), w.config.Timeout)
+	defer cancel()
+
+	tx, err := w.config.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("sql: begin: %w", err)
+	}
+	stmt, err := tx.PrepareContext(ctx, w.insert)
+	if err != nil {
+		_ = tx.Rollback()
+		return fmt.Errorf("sql: prepare: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, row := range batch {
+		if _, err := stmt.ExecContext(ctx, row...); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("sql: insert: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("sql: commit: %w", err)
+	}
+	return nil
+}
+
+// Health returns the error of the most recent transaction if it failed, and
+// otherwise pings the database.
+func (w *SQLWriter) Health(ctx context.Context) error {
+	w.mu.Lock()
+	err := w.lastErr
+	w.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	if err := w.config.DB.PingContext(ctx); err != nil {
+		return fmt.Errorf("sql: ping: %w", err)
+	}
+	return nil
+}
+
+// sqlRowFromJSON converts a JSON entry with the core keys named by names into
+// the ts, level, msg, and fields columns.
+func sqlRowFromJSON(p []byte, names KeyNames) ([]interface{}, error) {
+	entry, err := decodeEntry(p)
+	if err != nil {
+		return nil, fmt.Errorf("sql: decode entry: %w", err)
+	}
+
+	core := takeEntryCore(entry, names)
+	ts := time.Now()
+	if core.hasTime {
+		ts = core.time
+	}
+	level, msg := core.level, core.message
+
+	fields, err := json.Marshal(entry)
+	if err != nil {
+		return nil, fmt.Errorf("sql: encode fields: %w", err)
+	}
+	return []interface{}{ts.UTC().Format(time.RFC3339Nano), level, msg, string(fields)}, nil
+}
@@ -0,0 +1,184 @@
+package logger
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// TestSQLWriter tests that entries are inserted in batched transactions.
+func TestSQLWriter(t *testing.T) {
+	db, rec := openRecordingDB(t)
+	if err := MigrateSQL(context.Background(), db, "app_logs"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	writer := MustSQLWriter(SQLConfig{DB: db, Table: "app_logs", Placeholder: SQLDollar, BatchSize: 2})
+	zapLogger := NewZap(Config{Level: InfoLevel, Output: writer, DisableCaller: true})
+
+	zapLogger.Info("first", Fields{"key": "value"})
+	if n := rec.count("INSERT"); n != 0 {
+		t.Errorf("Expected no inserts before the batch is full, got %d", n)
+	}
+	zapLogger.Warn("second", nil)
+
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	if rec.commits != 1 {
+		t.Errorf("Expected 1 commit, got %d", rec.commits)
+	}
+	var inserts [][]driver.Value
+	for i, statement := range rec.statements {
+		if strings.HasPrefix(statement, "INSERT INTO app_logs (ts, level, msg, fields) VALUES ($1, $2, $3, $4)") {
+			inserts = append(inserts, rec.args[i])
+		}
+	}
+	if len(inserts) != 2 {
+		t.Fatalf("Expected 2 inserts, got %d: %v", len(inserts), rec.statements)
+	}
+	if inserts[0][1] != "info" || inserts[0][2] != "first" || inserts[0][3] != `{"key":"value"}` {
+		t.Errorf("Unexpected first row: %v", inserts[0])
+	}
+	if !strings.HasPrefix(rec.statements[0], "CREATE TABLE IF NOT EXISTS app_logs") {
+		t.Errorf("Expected the migration to create the table, got %s", rec.statements[0])
+	}
+}
+
+// TestSQLWriter_Retry tests that the entries of a failed transaction are kept
+// for the next one.
+func TestSQLWriter_Retry(t *testing.T) {
+	db, rec := openRecordingDB(t)
+	var reported []error
+	writer := MustSQLWriter(SQLConfig{
+		DB:        db,
+		BatchSize: 2,
+		OnError:   func(err error) { reported = append(reported, err) },
+	})
+
+	rec.commitErr = errors.New("database is locked")
+	writer.Write([]byte(`{"level":"info","msg":"first"}`))
+	if _, err := writer.Write([]byte(`{"level":"info","msg":"second"}`)); err != nil {
+		t.Errorf("Expected the buffered entry to be accepted, got %v", err)
+	}
+	if len(reported) != 1 || writer.Health(context.Background()) == nil {
+		t.Errorf("Expected the failed commit to be reported, got %v", reported)
+	}
+	if err := writer.Sync(); err == nil {
+		t.Fatalf("Expected the commit to fail")
+	}
+
+	rec.mu.Lock()
+	rec.commitErr = nil
+	rec.statements, rec.args = nil, nil
+	rec.mu.Unlock()
+	if err := writer.Sync(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if rec.commits != 1 || len(rec.args) != 2 || rec.args[0][2] != "first" || rec.args[1][2] != "second" {
+		t.Errorf("Expected both entries to be inserted on retry, got %d commits and %v", rec.commits, rec.args)
+	}
+	if err := writer.Health(context.Background()); err != nil {
+		t.Errorf("Expected Health to recover after the commit, got %v", err)
+	}
+}
+
+// TestSQLWriter_KeyNames tests that entries written with custom key names are
+// split into columns.
+func TestSQLWriter_KeyNames(t *testing.T) {
+	row, err := sqlRowFromJSON([]byte(`{"@timestamp":"2024-05-01T13:00:00Z","lvl":3,"message":"m","key":"value"}`), KeyNames{Time: "@timestamp", Level: "lvl", Message: "message"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if row[0] != "2024-05-01T13:00:00Z" || row[1] != "error" || row[2] != "m" || row[3] != `{"key":"value"}` {
+		t.Errorf("Unexpected row: %v", row)
+	}
+}
+
+// TestNewSQLWriterE tests configuration validation.
+func TestNewSQLWriterE(t *testing.T) {
+	db, _ := openRecordingDB(t)
+	if _, err := NewSQLWriterE(SQLConfig{}); err == nil {
+		t.Errorf("Expected an error without a database")
+	}
+	if _, err := NewSQLWriterE(SQLConfig{DB: db, Table: "logs; DROP TABLE users"}); err == nil {
+		t.Errorf("Expected an error for an invalid table name")
+	}
+	if err := MigrateSQL(context.Background(), db, "bad name"); err == nil {
+		t.Errorf("Expected an error migrating an invalid table name")
+	}
+}
+
+// recordingDriver is a database/sql driver that records the statements it executes.
+type recordingDriver struct {
+	mu         sync.Mutex
+	statements []string
+	args       [][]driver.Value
+	commits    int
+	// commitErr, if set, fails the commits.
+	commitErr error
+}
+
+// openRecordingDB opens a database backed by a fresh recordingDriver.
+func openRecordingDB(t *testing.T) (*sql.DB, *recordingDriver) {
+	rec := &recordingDriver{}
+	db := sql.OpenDB(rec)
+	t.Cleanup(func() { db.Close() })
+	return db, rec
+}
+
+func (r *recordingDriver) count(prefix string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n := 0
+	for _, statement := range r.statements {
+		if strings.HasPrefix(statement, prefix) {
+			n++
+		}
+	}
+	return n
+}
+
+func (r *recordingDriver) Connect(context.Context) (driver.Conn, error) { return recordingConn{r}, nil }
+func (r *recordingDriver) Driver() driver.Driver                        { return r }
+func (r *recordingDriver) Open(string) (driver.Conn, error)             { return recordingConn{r}, nil }
+
+type recordingConn struct{ rec *recordingDriver }
+
+func (c recordingConn) Prepare(query string) (driver.Stmt, error) {
+	return recordingStmt{c.rec, query}, nil
+}
+func (c recordingConn) Close() error              { return nil }
+func (c recordingConn) Begin() (driver.Tx, error) { return recordingTx{c.rec}, nil }
+
+type recordingStmt struct {
+	rec   *recordingDriver
+	query string
+}
+
+func (s recordingStmt) Close() error  { return nil }
+func (s recordingStmt) NumInput() int { return -1 }
+func (s recordingStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.rec.mu.Lock()
+	defer s.rec.mu.Unlock()
+	s.rec.statements = append(s.rec.statements, s.query)
+	s.rec.args = append(s.rec.args, args)
+	return driver.RowsAffected(1), nil
+}
+func (s recordingStmt) Query([]driver.Value) (driver.Rows, error) { return nil, driver.ErrSkip }
+
+type recordingTx struct{ rec *recordingDriver }
+
+func (tx recordingTx) Commit() error {
+	tx.rec.mu.Lock()
+	defer tx.rec.mu.Unlock()
+	if tx.rec.commitErr != nil {
+		return tx.rec.commitErr
+	}
+	tx.rec.commits++
+	return nil
+}
+func (tx recordingTx) Rollback() error { return nil }
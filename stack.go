@@ -0,0 +1,90 @@
+package logger
+
+import (
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// packagePath is the import path of this package, used to trim its frames.
+const packagePath = "github.com/ralonr/logger"
+
+// maxStackFrames bounds the frames read when capturing a stack trace.
+const maxStackFrames = 64
+
+// StackConfig controls how captured stack traces are rendered.
+type StackConfig struct {
+	// MaxDepth limits the number of frames written; zero writes them all.
+	MaxDepth int
+	// TrimRuntime drops frames of the Go runtime, such as runtime.gopanic.
+	TrimRuntime bool
+	// TrimLogger drops frames of this package.
+	TrimLogger bool
+	// Compact renders the stack on one line, e.g.
+	// "main.handle (main.go:42) < main.main (main.go:12)", for console output.
+	Compact bool
+}
+
+// Stack returns the stack trace of its caller rendered according to config,
+// for attaching to an entry, e.g. Fields{"stack": logger.Stack(config)}.
+func Stack(config StackConfig) string {
+	return captureStack(1, config)
+}
+
+// captureStack returns the stack trace rendered according to config, skipping
+// skip frames above its caller.
+func captureStack(skip int, config StackConfig) string {
+	pcs := make([]uintptr, maxStackFrames)
+	n := runtime.Callers(skip+2, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+
+	var b strings.Builder
+	depth := 0
+	for {
+		frame, more := frames.Next()
+		if config.keep(frame) {
+			writeFrame(&b, frame, depth, config.Compact)
+			depth++
+		}
+		if !more || (config.MaxDepth > 0 && depth >= config.MaxDepth) {
+			break
+		}
+	}
+	return b.String()
+}
+
+// keep reports whether frame is written.
+func (c StackConfig) keep(frame runtime.Frame) bool {
+	pkg := packageOf(frame.Function)
+	if c.TrimRuntime && (pkg == "runtime" || strings.HasPrefix(pkg, "runtime/")) {
+		return false
+	}
+	if c.TrimLogger && pkg == packagePath {
+		return false
+	}
+	return true
+}
+
+// writeFrame writes frame as "function\n\tfile:line\n", or in compact form.
+func writeFrame(b *strings.Builder, frame runtime.Frame, depth int, compact bool) {
+	line := strconv.Itoa(frame.Line)
+	if !compact {
+		b.WriteString(frame.Function)
+		b.WriteString("\n\t")
+		b.WriteString(frame.File)
+		b.WriteByte(':')
+		b.WriteString(line)
+		b.WriteByte('\n')
+		return
+	}
+	if depth > 0 {
+		b.WriteString(" < ")
+	}
+	b.WriteString(frame.Function[strings.LastIndex(frame.Function, "/")+1:])
+	b.WriteString(" (")
+	b.WriteString(filepath.Base(frame.File))
+	b.WriteByte(':')
+	b.WriteString(line)
+	b.WriteByte(')')
+}
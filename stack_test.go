@@ -0,0 +1,47 @@
+package logger
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestStack tests the depth, trimming, and compact rendering of stack traces.
+func TestStack(t *testing.T) {
+	full := Stack(StackConfig{})
+	if !strings.HasPrefix(full, packagePath+".TestStack\n\t") || !strings.Contains(full, "stack_test.go:") {
+		t.Errorf("Expected the stack to start with the caller, got %s", full)
+	}
+
+	if frames := strings.Count(Stack(StackConfig{MaxDepth: 1}), "\n\t"); frames != 1 {
+		t.Errorf("Expected 1 frame, got %d", frames)
+	}
+
+	trimmed := Stack(StackConfig{TrimRuntime: true, TrimLogger: true})
+	if strings.Contains(trimmed, "runtime.") || strings.Contains(trimmed, packagePath) {
+		t.Errorf("Expected runtime and logger frames to be trimmed, got %s", trimmed)
+	}
+	if !strings.Contains(trimmed, "testing.tRunner") {
+		t.Errorf("Expected other frames to be kept, got %s", trimmed)
+	}
+
+	compact := Stack(StackConfig{Compact: true, MaxDepth: 2})
+	if strings.Contains(compact, "\n") || !strings.HasPrefix(compact, "logger.TestStack (stack_test.go:") || !strings.Contains(compact, " < testing.tRunner (") {
+		t.Errorf("Expected a compact one-line stack, got %s", compact)
+	}
+}
+
+// TestRecoverAndLogWith_Stack tests that recovered panics use the stack configuration.
+func TestRecoverAndLogWith_Stack(t *testing.T) {
+	log, logs := Observe(Config{})
+
+	func() {
+		defer RecoverAndLogWith(log, RecoverConfig{Stack: StackConfig{TrimRuntime: true, Compact: true, MaxDepth: 1}})
+		panic(errors.New("boom"))
+	}()
+
+	stack, _ := logs.All()[0].Fields["stack"].(string)
+	if !strings.HasPrefix(stack, "logger.logPanic (recover.go:") || strings.Contains(stack, " < ") {
+		t.Errorf("Expected a single compact frame, got %s", stack)
+	}
+}
@@ -0,0 +1,95 @@
+package logger
+
+import (
+	"fmt"
+	"strings"
+)
+
+// renderTemplate replaces each {key} placeholder in msg with the value of the
+// field of that key, typed fields taking precedence over the Fields map.
+// Placeholders without a matching field are left as they are, and {{ and }}
+// stand for literal braces. It reports whether msg held any placeholder.
+func renderTemplate(msg string, fields Fields, typed []Field) (string, bool) {
+	if strings.IndexByte(msg, '{') < 0 && strings.IndexByte(msg, '}') < 0 {
+		return msg, false
+	}
+	var b strings.Builder
+	b.Grow(len(msg))
+	templated := false
+	for i := 0; i < len(msg); i++ {
+		c := msg[i]
+		if (c == '{' || c == '}') && i+1 < len(msg) && msg[i+1] == c {
+			b.WriteByte(c)
+			i++
+			templated = true
+			continue
+		}
+		if c != '{' {
+			b.WriteByte(c)
+			continue
+		}
+		end := strings.IndexByte(msg[i+1:], '}')
+		if end <= 0 {
+			b.WriteByte(c)
+			continue
+		}
+		key := msg[i+1 : i+1+end]
+		value, ok := templateValue(key, fields, typed)
+		if !ok {
+			b.WriteByte(c)
+			continue
+		}
+		b.WriteString(formatTemplateValue(value))
+		i += end + 1
+		templated = true
+	}
+	return b.String(), templated
+}
+
+// templateValue returns the value of the field with key.
+func templateValue(key string, fields Fields, typed []Field) (interface{}, bool) {
+	for i := len(typed) - 1; i >= 0; i-- {
+		if typed[i].Key == key {
+			return typed[i].Value, true
+		}
+	}
+	value, ok := fields[key]
+	return value, ok
+}
+
+// formatTemplateValue formats a field value for a rendered message.
+func formatTemplateValue(value interface{}) string {
+	switch val := value.(type) {
+	case string:
+		return val
+	case error:
+		return val.Error()
+	case RawJSON:
+		return string(val)
+	default:
+		return fmt.Sprint(val)
+	}
+}
+
+// applyTemplate renders msg according to Config.MessageTemplates and adds the
+// template under Config.MessageTemplateKey, without modifying the caller's
+// fields.
+func (z *Zap) applyTemplate(msg string, fields Fields, typed []Field) (string, Fields, []Field) {
+	rendered, templated := renderTemplate(msg, fields, typed)
+	if !templated {
+		return msg, fields, typed
+	}
+	if key := z.Config.MessageTemplateKey; key != "" {
+		if len(typed) > 0 || fields == nil {
+			typed = append(typed[:len(typed):len(typed)], Field{Key: key, Value: msg})
+		} else {
+			copied := make(Fields, len(fields)+1)
+			for k, v := range fields {
+				copied[k] = v
+			}
+			copied[key] = msg
+			fields = copied
+		}
+	}
+	return rendered, fields, typed
+}
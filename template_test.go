@@ -0,0 +1,88 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+// TestRenderTemplate tests rendering placeholders from fields.
+func TestRenderTemplate(t *testing.T) {
+	for _, test := range []struct {
+		msg       string
+		fields    Fields
+		typed     []Field
+		expected  string
+		templated bool
+	}{
+		{"plain message", Fields{"a": 1}, nil, "plain message", false},
+		{"user {user_id} purchased {sku}", Fields{"user_id": 42, "sku": "A-1"}, nil, "user 42 purchased A-1", true},
+		{"user {user_id} failed: {error}", nil, []Field{Int("user_id", 7), Err(errors.New("boom"))}, "user 7 failed: boom", true},
+		{"value {k}", Fields{"k": "map"}, []Field{String("k", "typed")}, "value typed", true},
+		{"missing {nope} and {}", Fields{"a": 1}, nil, "missing {nope} and {}", false},
+		{"literal {{k}} and }}", Fields{"k": 1}, nil, "literal {k} and }", true},
+		{"unclosed {k", Fields{"k": 1}, nil, "unclosed {k", false},
+	} {
+		rendered, templated := renderTemplate(test.msg, test.fields, test.typed)
+		if rendered != test.expected || templated != test.templated {
+			t.Errorf("Expected %q to render as %q (%v), got %q (%v)", test.msg, test.expected, test.templated, rendered, templated)
+		}
+	}
+}
+
+// TestZap_MessageTemplates tests that templated messages are rendered and the
+// fields and template are still written.
+func TestZap_MessageTemplates(t *testing.T) {
+	buf := new(bytes.Buffer)
+	zapLogger := NewZap(Config{
+		Level:              InfoLevel,
+		Output:             buf,
+		MessageTemplates:   true,
+		MessageTemplateKey: "template",
+	})
+
+	fields := Fields{"user_id": 42, "sku": "A-1"}
+	zapLogger.Info("user {user_id} purchased {sku}", fields)
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Expected a JSON entry, got %q: %v", buf.String(), err)
+	}
+	if entry["msg"] != "user 42 purchased A-1" {
+		t.Errorf("Expected the rendered message, got %v", entry["msg"])
+	}
+	if entry["user_id"] != float64(42) || entry["sku"] != "A-1" {
+		t.Errorf("Expected the fields to be written, got %v", entry)
+	}
+	if entry["template"] != "user {user_id} purchased {sku}" {
+		t.Errorf("Expected the template to be written, got %v", entry["template"])
+	}
+	if _, ok := fields["template"]; ok {
+		t.Errorf("Expected the caller's fields to be left unchanged, got %v", fields)
+	}
+
+	buf.Reset()
+	zapLogger.InfoFields("order {order} shipped", String("order", "o-9"))
+	if !bytes.Contains(buf.Bytes(), []byte(`"msg":"order o-9 shipped"`)) || !bytes.Contains(buf.Bytes(), []byte(`"template":"order {order} shipped"`)) {
+		t.Errorf("Expected the typed fields to render the message, got %q", buf.String())
+	}
+
+	buf.Reset()
+	zapLogger.Info("no placeholders", nil)
+	if bytes.Contains(buf.Bytes(), []byte(`"template"`)) {
+		t.Errorf("Expected no template field for plain messages, got %q", buf.String())
+	}
+}
+
+// TestZap_MessageTemplatesDisabled tests that messages are written as-is by default.
+func TestZap_MessageTemplatesDisabled(t *testing.T) {
+	buf := new(bytes.Buffer)
+	zapLogger := NewZap(Config{Level: InfoLevel, Output: buf})
+
+	zapLogger.Info("user {user_id}", Fields{"user_id": 42})
+
+	if !bytes.Contains(buf.Bytes(), []byte(`"msg":"user {user_id}"`)) {
+		t.Errorf("Expected the message to be written as-is, got %q", buf.String())
+	}
+}
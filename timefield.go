@@ -0,0 +1,69 @@
+package logger
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// DurationFormat controls how time.Duration field values are written.
+type DurationFormat int
+
+const (
+	// DurationSeconds writes durations as floating-point seconds, e.g. 1.5.
+	DurationSeconds DurationFormat = iota
+	// DurationMillis writes durations as floating-point milliseconds, e.g. 1500.
+	DurationMillis
+	// DurationNanos writes durations as integer nanoseconds, e.g. 1500000000.
+	DurationNanos
+	// DurationString writes durations as Go duration strings, e.g. "1.5s".
+	DurationString
+)
+
+// Duration returns a Field with a duration value, written according to Config.DurationFormat.
+func Duration(key string, value time.Duration) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Time returns a Field with a time value, written according to Config.FieldTimeFormat.
+func Time(key string, value time.Time) Field {
+	return Field{Key: key, Value: value}
+}
+
+// durationField converts a duration to a zap field in the configured format.
+func (c *fieldConverter) durationField(k string, d time.Duration) zap.Field {
+	switch c.durations {
+	case DurationMillis:
+		return zap.Float64(k, float64(d)/float64(time.Millisecond))
+	case DurationNanos:
+		return zap.Int64(k, int64(d))
+	case DurationString:
+		return zap.String(k, d.String())
+	default:
+		return zap.Float64(k, d.Seconds())
+	}
+}
+
+// timeField converts a time to a zap field in the configured layout and time zone.
+func (c *fieldConverter) timeField(k string, t time.Time) zap.Field {
+	if c.timeZone != nil {
+		t = t.In(c.timeZone)
+	}
+	switch c.timeLayout {
+	case "":
+		return zap.String(k, t.Format(TimeFormatRFC3339))
+	case TimeFormatUnixMillis:
+		return zap.Int64(k, t.UnixNano()/int64(time.Millisecond))
+	default:
+		return zap.String(k, t.Format(c.timeLayout))
+	}
+}
+
+// fieldTimeLayout returns the layout of time field values: FieldTimeFormat,
+// falling back to TimeFormat so that fields match the entry timestamp.
+func fieldTimeLayout(config Config) string {
+	if config.FieldTimeFormat != "" {
+		return config.FieldTimeFormat
+	}
+	return config.TimeFormat
+}
@@ -0,0 +1,79 @@
+package logger
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+// TestDurationFormat tests the duration field encodings.
+func TestDurationFormat(t *testing.T) {
+	tests := []struct {
+		format   DurationFormat
+		expected string
+	}{
+		{DurationSeconds, `"elapsed":1.5`},
+		{DurationMillis, `"elapsed":1500`},
+		{DurationNanos, `"elapsed":1500000000`},
+		{DurationString, `"elapsed":"1.5s"`},
+	}
+	for _, test := range tests {
+		buffer := new(bytes.Buffer)
+		zapLogger := NewZap(Config{Level: InfoLevel, Output: buffer, DurationFormat: test.format})
+
+		zapLogger.InfoFields("done", Duration("elapsed", 1500*time.Millisecond))
+		if !bytes.Contains(buffer.Bytes(), []byte(test.expected)) {
+			t.Errorf("Expected %s to contain %s", buffer.String(), test.expected)
+		}
+	}
+
+	if _, err := NewZapE(Config{Output: new(bytes.Buffer), DurationFormat: DurationString + 1}); err == nil {
+		t.Errorf("Expected an error for an invalid duration format")
+	}
+}
+
+// TestFieldTimeFormat tests the time field layouts and time zone.
+func TestFieldTimeFormat(t *testing.T) {
+	at := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		config   Config
+		expected string
+	}{
+		{Config{TimeZone: time.UTC}, `"at":"2024-03-01T12:00:00Z"`},
+		{Config{TimeFormat: TimeFormatUnixMillis}, `"at":1709294400000`},
+		{Config{TimeFormat: TimeFormatUnixMillis, FieldTimeFormat: "2006-01-02", TimeZone: time.UTC}, `"at":"2024-03-01"`},
+		{Config{TimeZone: time.FixedZone("CET", 3600)}, `"at":"2024-03-01T13:00:00+01:00"`},
+	}
+	for _, test := range tests {
+		buffer := new(bytes.Buffer)
+		test.config.Level = InfoLevel
+		test.config.Output = buffer
+		zapLogger := NewZap(test.config)
+
+		zapLogger.Info("scheduled", Fields{"at": at})
+		if !bytes.Contains(buffer.Bytes(), []byte(test.expected)) {
+			t.Errorf("Expected %s to contain %s", buffer.String(), test.expected)
+		}
+	}
+}
+
+// TestTimeFields_CustomFormat tests that custom encoders render durations and times like JSON.
+func TestTimeFields_CustomFormat(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	zapLogger := NewZap(Config{
+		Level:          InfoLevel,
+		Output:         buffer,
+		Format:         FormatCSV,
+		CSV:            CSVConfig{Columns: []string{"elapsed", "at"}},
+		DurationFormat: DurationMillis,
+		TimeZone:       time.UTC,
+		Clock:          fixedClock(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)),
+	})
+
+	var missing *time.Time
+	zapLogger.Info("done", Fields{"elapsed": 250 * time.Millisecond, "at": time.Date(2024, 3, 1, 11, 0, 0, 0, time.UTC), "missing": missing})
+	expected := "2024-03-01T12:00:00Z,info,done,250,2024-03-01T11:00:00Z\n"
+	if buffer.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, buffer.String())
+	}
+}
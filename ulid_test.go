@@ -0,0 +1,26 @@
+package logger
+
+import (
+	"testing"
+	"time"
+)
+
+// TestNewULID tests the ULID encoding.
+func TestNewULID(t *testing.T) {
+	ts := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	id := newULID(ts)
+
+	if len(id) != 26 {
+		t.Fatalf("Expected 26 characters, got %d (%s)", len(id), id)
+	}
+	// 1709294400000 ms encodes to the 10-character timestamp prefix.
+	if prefix := id[:10]; prefix != "01HQWY5CG0" {
+		t.Errorf("Expected timestamp prefix 01HQWY5CG0, got %s", prefix)
+	}
+	if other := newULID(ts); other == id {
+		t.Errorf("Expected unique ULIDs, got %s twice", id)
+	}
+	if later := newULID(ts.Add(time.Millisecond)); later <= id {
+		t.Errorf("Expected %s to sort after %s", later, id)
+	}
+}
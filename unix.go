@@ -0,0 +1,114 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+)
+
+// UnixWriter is an output that writes entries to a Unix domain socket, such as
+// the socket source of a local Vector or Fluent Bit collector. Stream sockets
+// receive newline-delimited entries; datagram sockets receive one entry per
+// datagram. A write that fails before sending anything reconnects once and
+// retries, so the collector can be restarted without restarting the
+// application.
+type UnixWriter struct {
+	network string
+	path    string
+	mu      sync.Mutex
+	conn    net.Conn
+}
+
+// NewUnixWriter connects to the socket at address, which is either
+// unix:///path/to/socket for a stream socket or unixgram:///path/to/socket
+// for a datagram socket.
+func NewUnixWriter(address string) (*UnixWriter, error) {
+	network, path, ok := parseUnixAddress(address)
+	if !ok {
+		return nil, fmt.Errorf("unix: invalid address %q", address)
+	}
+	w := &UnixWriter{network: network, path: path}
+	if err := w.dial(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// parseUnixAddress splits a unix:// or unixgram:// address into its network and path.
+func parseUnixAddress(address string) (network, path string, ok bool) {
+	for _, network := range []string{"unix", "unixgram"} {
+		if path := strings.TrimPrefix(address, network+"://"); path != address && path != "" {
+			return network, path, true
+		}
+	}
+	return "", "", false
+}
+
+// isUnixAddress reports whether an output path names a Unix domain socket.
+func isUnixAddress(path string) bool {
+	return strings.HasPrefix(path, "unix://") || strings.HasPrefix(path, "unixgram://")
+}
+
+// dial connects to the socket. The caller must hold w.mu or own w exclusively.
+func (w *UnixWriter) dial() error {
+	conn, err := net.Dial(w.network, w.path)
+	if err != nil {
+		return fmt.Errorf("unix: dial %s: %w", w.path, err)
+	}
+	w.conn = conn
+	return nil
+}
+
+// Write sends a single entry, reconnecting once if the connection was lost.
+// An entry that was partly sent before the connection failed is dropped
+// rather than resent, so the collector never receives it twice or as a
+// fragment; the next write reconnects.
+func (w *UnixWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.conn != nil {
+		n, err := w.conn.Write(p)
+		if err == nil {
+			return n, nil
+		}
+		w.conn.Close()
+		w.conn = nil
+		if n > 0 {
+			return n, fmt.Errorf("unix: write: %w", err)
+		}
+	}
+	if err := w.dial(); err != nil {
+		return 0, err
+	}
+	n, err := w.conn.Write(p)
+	if err != nil {
+		return n, fmt.Errorf("unix: write: %w", err)
+	}
+	return n, nil
+}
+
+// Health reconnects if the connection was lost and reports whether the socket
+// is reachable.
+func (w *UnixWriter) Health(ctx context.Context) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.conn != nil {
+		return nil
+	}
+	return w.dial()
+}
+
+// Close closes the connection.
+func (w *UnixWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.conn == nil {
+		return nil
+	}
+	err := w.conn.Close()
+	w.conn = nil
+	return err
+}
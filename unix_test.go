@@ -0,0 +1,141 @@
+package logger
+
+import (
+	"bufio"
+	"errors"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestUnixWriter_Stream tests logging to a stream socket given as an output path.
+func TestUnixWriter_Stream(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "collector.sock")
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		t.Skipf("Unix sockets unavailable: %v", err)
+	}
+	defer listener.Close()
+
+	lines := make(chan string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		line, _ := bufio.NewReader(conn).ReadString('\n')
+		lines <- line
+	}()
+
+	zapLogger, err := NewZapE(Config{Level: InfoLevel, OutputPath: "unix://" + path})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	zapLogger.Info("Info message", nil)
+
+	select {
+	case line := <-lines:
+		if !strings.Contains(line, `"msg":"Info message"`) {
+			t.Errorf("Expected the entry on the socket, got %s", line)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Timed out waiting for the entry")
+	}
+}
+
+// TestUnixWriter_Datagram tests that each entry is sent as one datagram.
+func TestUnixWriter_Datagram(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "collector.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		t.Skipf("Unix sockets unavailable: %v", err)
+	}
+	defer conn.Close()
+
+	writer, err := NewUnixWriter("unixgram://" + path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer writer.Close()
+	writer.Write([]byte("first"))
+	writer.Write([]byte("second"))
+
+	buf := make([]byte, 64)
+	for _, expected := range []string{"first", "second"} {
+		conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		n, err := conn.Read(buf)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if got := string(buf[:n]); got != expected {
+			t.Errorf("Expected datagram %q, got %q", expected, got)
+		}
+	}
+}
+
+// TestUnixWriter_PartialWrite tests that a partly sent entry is not resent
+// after reconnecting.
+func TestUnixWriter_PartialWrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "collector.sock")
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		t.Skipf("Unix sockets unavailable: %v", err)
+	}
+	defer listener.Close()
+
+	lines := make(chan string, 2)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		reader := bufio.NewReader(conn)
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			lines <- line
+		}
+	}()
+
+	writer := &UnixWriter{network: "unix", path: path, conn: partialConn{}}
+	defer writer.Close()
+	if n, err := writer.Write([]byte("first\n")); n != 3 || err == nil {
+		t.Errorf("Expected the partial write to fail, got %d, %v", n, err)
+	}
+	if _, err := writer.Write([]byte("second\n")); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	select {
+	case line := <-lines:
+		if line != "second\n" {
+			t.Errorf("Expected only the second entry after reconnecting, got %q", line)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Timed out waiting for the entry")
+	}
+}
+
+// partialConn is a connection that sends three bytes of every write and then fails.
+type partialConn struct {
+	net.Conn
+}
+
+func (partialConn) Write(p []byte) (int, error) { return 3, errors.New("broken pipe") }
+
+func (partialConn) Close() error { return nil }
+
+// TestNewUnixWriter tests address validation and connection errors.
+func TestNewUnixWriter(t *testing.T) {
+	for _, address := range []string{"unix://", "tcp://localhost:1", "unix://" + filepath.Join(t.TempDir(), "missing.sock")} {
+		if _, err := NewUnixWriter(address); err == nil {
+			t.Errorf("Expected an error for %q", address)
+		}
+	}
+}
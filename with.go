@@ -0,0 +1,114 @@
+package logger
+
+import "go.uber.org/zap"
+
+// With returns a child of l that adds fields to every entry. A *Zap child
+// encodes the fields once, up front; loggers with a With(Fields) Logger method
+// use it; any other Logger is wrapped and merges the fields on each call, with
+// per-call fields taking precedence.
+func With(l Logger, fields Fields) Logger {
+	switch parent := l.(type) {
+	case *Zap:
+		return parent.With(fields)
+	case interface{ With(Fields) Logger }:
+		return parent.With(fields)
+	}
+	return &fieldsLogger{logger: l, fields: fields}
+}
+
+// With returns a child logger, sharing the level, hooks, and other runtime
+// state of z, that adds fields to every entry.
+func (z *Zap) With(fields Fields) *Zap {
+	if len(fields) == 0 {
+		return z
+	}
+	bound := z.bound
+	if z.Config.Schema.enabled() {
+		fields, bound = z.bindSchema(fields)
+	}
+	return &Zap{
+		logger: z.logger.With(z.conv.finish(z.conv.appendFields(nil, fields), false)...),
+		Config: z.Config,
+		state:  z.state,
+		conv:   z.conv,
+		skip:   z.skip,
+		bound:  bound,
+	}
+}
+
+// WithGroup returns a child logger, sharing the runtime state of z, that nests
+// every later field under name, as slog.Logger.WithGroup does: fields added
+// with With on the child and per-call fields are written as {"name": {...}}.
+// Fields the logger adds itself, such as seq, are nested too. An empty name
+// returns z.
+func (z *Zap) WithGroup(name string) *Zap {
+	if name == "" {
+		return z
+	}
+	return &Zap{
+		logger: z.logger.With(z.conv.finish([]zap.Field{zap.Namespace(name)}, false)...),
+		Config: z.Config,
+		state:  z.state,
+		conv:   z.conv,
+		skip:   z.skip,
+		bound:  z.bound,
+	}
+}
+
+// fieldsLogger is the child logger With returns for arbitrary Logger implementations.
+type fieldsLogger struct {
+	logger Logger
+	fields Fields
+}
+
+// With returns a child that adds fields to those already bound.
+func (l *fieldsLogger) With(fields Fields) Logger {
+	return &fieldsLogger{logger: l.logger, fields: mergeFields(l.fields, fields)}
+}
+
+// Debug logs a debug message with the bound and given fields.
+func (l *fieldsLogger) Debug(msg string, fields Fields) {
+	l.logger.Debug(msg, mergeFields(l.fields, fields))
+}
+
+// Info logs an info message with the bound and given fields.
+func (l *fieldsLogger) Info(msg string, fields Fields) {
+	l.logger.Info(msg, mergeFields(l.fields, fields))
+}
+
+// Warn logs a warning message with the bound and given fields.
+func (l *fieldsLogger) Warn(msg string, fields Fields) {
+	l.logger.Warn(msg, mergeFields(l.fields, fields))
+}
+
+// Error logs an error message with the bound and given fields.
+func (l *fieldsLogger) Error(msg string, fields Fields) {
+	l.logger.Error(msg, mergeFields(l.fields, fields))
+}
+
+// Fatal logs a fatal message with the bound and given fields.
+func (l *fieldsLogger) Fatal(msg string, fields Fields) {
+	l.logger.Fatal(msg, mergeFields(l.fields, fields))
+}
+
+// Enabled reports whether the wrapped logger writes entries at level.
+func (l *fieldsLogger) Enabled(level Level) bool {
+	return Enabled(l.logger, level)
+}
+
+// DebugEnabled reports whether the wrapped logger writes debug entries.
+func (l *fieldsLogger) DebugEnabled() bool {
+	return Enabled(l.logger, DebugLevel)
+}
+
+// mergeFields returns a new map holding base overlaid with fields.
+func mergeFields(base, fields Fields) Fields {
+	merged := make(Fields, len(base)+len(fields))
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+	return merged
+}
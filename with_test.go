@@ -0,0 +1,72 @@
+package logger
+
+import (
+	"bytes"
+	"testing"
+)
+
+// TestZap_With tests that a child logger adds its fields and shares the parent's level.
+func TestZap_With(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	parent := NewZap(Config{Level: InfoLevel, Output: buffer})
+	child := parent.With(Fields{"component": "db"})
+
+	child.Info("Info message", Fields{"query": "select"})
+	for _, expected := range []string{`"component":"db"`, `"query":"select"`, `with_test.go`} {
+		if !bytes.Contains(buffer.Bytes(), []byte(expected)) {
+			t.Errorf("Expected %s to contain %s", buffer.String(), expected)
+		}
+	}
+
+	buffer.Reset()
+	parent.Info("Info message", nil)
+	if bytes.Contains(buffer.Bytes(), []byte("component")) {
+		t.Errorf("Expected the parent to be unchanged, got %s", buffer.String())
+	}
+
+	buffer.Reset()
+	parent.SetLevel(WarnLevel)
+	child.Info("Info message", nil)
+	if buffer.Len() != 0 {
+		t.Errorf("Expected the child to share the parent's level, got %s", buffer.String())
+	}
+}
+
+// TestWith tests that With binds fields for observed and wrapped loggers.
+func TestWith(t *testing.T) {
+	observed, logs := Observe(Config{Level: InfoLevel})
+	With(With(observed, Fields{"a": 1}), Fields{"b": 2}).Info("Info message", Fields{"c": 3})
+	entry := logs.All()[0]
+	if len(entry.Fields) != 3 {
+		t.Errorf("Expected 3 fields, got %v", entry.Fields)
+	}
+
+	wrapped, wrappedLogs := Observe(Config{Level: InfoLevel})
+	child := With(plainLogger{wrapped}, Fields{"a": 1, "b": 1})
+	child.Info("Info message", Fields{"b": 2})
+	if fields := wrappedLogs.All()[0].Fields; fields["a"] != 1 || fields["b"] != 2 {
+		t.Errorf("Expected per-call fields to take precedence, got %v", fields)
+	}
+}
+
+// plainLogger hides every method of the wrapped logger but those of Logger.
+type plainLogger struct {
+	Logger
+}
+
+// TestZap_WithGroup tests that fields added after a group are nested under it.
+func TestZap_WithGroup(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	parent := NewZap(Config{Level: InfoLevel, Output: buffer, DisableCaller: true})
+	child := parent.With(Fields{"service": "api"}).WithGroup("db").With(Fields{"query": "select"})
+
+	child.Info("Info message", Fields{"rows": 3})
+	expected := `"service":"api","db":{"query":"select","rows":3}}`
+	if !bytes.Contains(buffer.Bytes(), []byte(expected)) {
+		t.Errorf("Expected %s to contain %s", buffer.String(), expected)
+	}
+
+	if parent.WithGroup("") != parent {
+		t.Errorf("Expected an empty group to return the logger itself")
+	}
+}
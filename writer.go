@@ -0,0 +1,52 @@
+package logger
+
+import (
+	"io"
+	"sync"
+)
+
+// safeWriter serializes writes to an underlying writer.
+type safeWriter struct {
+	mu     sync.Mutex
+	output io.Writer
+}
+
+// WrapSafe returns a writer that serializes writes to w with a mutex, so that
+// writers which are not safe for concurrent use can be passed as Output. Each
+// entry is written in full while the lock is held, continuing after short
+// writes, so lines never interleave.
+func WrapSafe(w io.Writer) io.Writer {
+	if s, ok := w.(*safeWriter); ok {
+		return s
+	}
+	return &safeWriter{output: w}
+}
+
+// Write writes p to the underlying writer while holding the lock.
+func (s *safeWriter) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	written := 0
+	for written < len(p) {
+		n, err := s.output.Write(p[written:])
+		written += n
+		if err != nil {
+			return written, err
+		}
+		if n == 0 {
+			return written, io.ErrShortWrite
+		}
+	}
+	return written, nil
+}
+
+// Sync flushes the underlying writer if it supports it.
+func (s *safeWriter) Sync() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if syncer, ok := s.output.(syncer); ok {
+		return syncer.Sync()
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package logger
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+)
+
+// TestWrapSafe tests that concurrent entries are written without interleaving.
+func TestWrapSafe(t *testing.T) {
+	output := &chunkedWriter{}
+	zapLogger := NewZap(Config{Level: InfoLevel, Output: WrapSafe(output)})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				zapLogger.Info("Info message", Fields{"key": "value"})
+			}
+		}()
+	}
+	wg.Wait()
+
+	lines := bytes.Split(bytes.TrimSpace(output.Bytes()), []byte("\n"))
+	if len(lines) != 500 {
+		t.Fatalf("Expected 500 entries, got %d", len(lines))
+	}
+	for _, line := range lines {
+		if !bytes.HasPrefix(line, []byte("{")) || !bytes.HasSuffix(line, []byte("}")) {
+			t.Fatalf("Expected whole JSON lines, got %s", line)
+		}
+	}
+}
+
+// TestWrapSafe_Idempotent tests that wrapping twice returns the same writer.
+func TestWrapSafe_Idempotent(t *testing.T) {
+	w := WrapSafe(new(bytes.Buffer))
+	if WrapSafe(w) != w {
+		t.Errorf("Expected WrapSafe to return an already wrapped writer unchanged")
+	}
+}
+
+// chunkedWriter is a non-thread-safe writer that accepts at most 8 bytes per call.
+type chunkedWriter struct {
+	bytes.Buffer
+}
+
+func (c *chunkedWriter) Write(p []byte) (int, error) {
+	if len(p) > 8 {
+		p = p[:8]
+	}
+	return c.Buffer.Write(p)
+}
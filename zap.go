@@ -1,114 +1,660 @@
 package logger
 
 import (
+	"fmt"
 	"os"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
 
 // Zap is a logger implementation using zap.
+//
+// A *Zap is safe for concurrent use. Config is a snapshot of the configuration
+// the logger was built with and must not be modified after NewZap returns;
+// settings that may change at runtime, such as the level and hooks, are held
+// in shared state and changed through SetLevel and AddHook.
 type Zap struct {
 	logger *zap.Logger
 	Config Config
+	state  *state
+	conv   *fieldConverter
+	// skip is the number of stack frames skipped beyond Config.CallerSkip.
+	skip int
+	// bound holds the keys bound with With when Config.Schema is enabled.
+	bound map[string]struct{}
 }
 
-// NewZap returns a new *Zap.
+// state holds the runtime-mutable settings of a logger. All access goes through
+// atomics or the mutex.
+type state struct {
+	seq   uint64 // accessed atomically; kept first for 64-bit alignment
+	level zap.AtomicLevel
+	mu    sync.RWMutex
+	hooks []Hook
+	// recorder holds suppressed debug entries when Config.FlightRecorder is set.
+	recorder *ringBuffer
+	// history holds the most recent entries for crash reports.
+	history *ringBuffer
+	// once and every track call sites for Once and Every.
+	once  callSites
+	every callSites
+	// closed is set by Shutdown; accessed atomically.
+	closed uint32
+	// done is closed by Shutdown, ending the goroutine watching Config.Context.
+	done     chan struct{}
+	doneOnce sync.Once
+	// events writes the entries logged with Event.
+	events zapcore.Core
+	// packages holds the *packageLevels overriding the level by caller package.
+	packages atomic.Value
+	// errors receives the logger's operational errors.
+	errors zapcore.WriteSyncer
+	// onError is Config.OnError.
+	onError func(error)
+	// pipeline is Config.Pipeline.
+	pipeline *Pipeline
+	// window restores the level after SetLevelFor; guarded by mu.
+	window *levelWindow
+}
+
+// levelWindow is a temporary level set by SetLevelFor.
+type levelWindow struct {
+	timer   *time.Timer
+	restore Level
+}
+
+// NewZap returns a new *Zap. If Config.OutputPath cannot be opened, the error
+// is reported on stderr and the logger writes to stderr instead; use NewZapE to
+// handle the failure.
 func NewZap(config Config) *Zap {
-	atomicLevel := zap.NewAtomicLevel()
-	switch config.Level {
+	output, err := openOutput(config)
+	if err != nil {
+		fmt.Fprintf(errorOutput(config), "logger: %v; writing to stderr\n", err)
+		output = os.Stderr
+	}
+	config.Output = output
+	return newZap(config)
+}
+
+// NewZapE returns a new *Zap, or an error if the configuration is invalid or
+// the output cannot be opened.
+func NewZapE(config Config) (*Zap, error) {
+	if err := validateConfig(config); err != nil {
+		return nil, err
+	}
+	output, err := openOutput(config)
+	if err != nil {
+		return nil, err
+	}
+	config.Output = output
+	return newZap(config), nil
+}
+
+// MustZap is like NewZapE but panics if the logger cannot be built.
+func MustZap(config Config) *Zap {
+	z, err := NewZapE(config)
+	if err != nil {
+		panic(err)
+	}
+	return z
+}
+
+// newZap builds a *Zap from a configuration whose Output is set.
+func newZap(config Config) *Zap {
+	st := &state{
+		errors:   zapcore.Lock(zapcore.AddSync(errorOutput(config))),
+		onError:  config.OnError,
+		done:     make(chan struct{}),
+		pipeline: config.Pipeline,
+		level:    zap.NewAtomicLevelAt(config.Level.zapLevel()),
+		recorder: newRingBuffer(config.FlightRecorder),
+		history:  newCrashHistory(config.CrashReport),
+		events:   newEventCore(config),
+	}
+	st.packages.Store(newPackageLevels(config.PackageLevels))
+
+	var main zapcore.Core
+	if config.Output != nil {
+		output := config.Output
+		config.Format = resolveFormat(config.Format, output)
+		if config.Color && config.Format == FormatConsole {
+			output, config.Color = colorOutput(output)
+		}
+		main = st.reportingCore(newFormatCore(config, config.Format, newEncoderConfig(config), zapcore.AddSync(output), st))
+	}
+	var dests []zapcore.Core
+	for _, dest := range config.Destinations {
+		dests = append(dests, st.reportingCore(newFormatCore(config, resolveFormat(dest.Format, dest.Output), destinationEncoderConfig(config, dest), zapcore.AddSync(dest.Output), destinationEnabler(dest, st))))
+	}
+	cores := newRouteCores(main, dests, config, st)
+	core := config.Zap.wrapCore(zapcore.NewTee(cores...))
+
+	options := []zap.Option{zap.WithFatalHook(noopFatalHook{}), zap.ErrorOutput(st.errors)}
+	if config.Clock != nil {
+		options = append(options, zap.WithClock(zapClock{config.Clock}))
+	}
+	if !config.DisableCaller {
+		options = append(options, zap.AddCaller(), zap.AddCallerSkip(3+config.CallerSkip))
+	}
+	options = append(options, config.Zap.Options...)
+	logger := zap.New(core, options...)
+	conv := newFieldConverter(config)
+	if config.BuildInfo {
+		logger = logger.With(conv.finish(conv.appendFields(nil, BuildInfo()), false)...)
+	}
+
+	if config.ExitFunc == nil {
+		config.ExitFunc = os.Exit // default to os.Exit
+	}
+
+	z := &Zap{
+		logger: logger,
+		Config: config,
+		state:  st,
+		conv:   conv,
+	}
+	if config.Context != nil && config.Context.Done() != nil {
+		go z.shutdownOnDone(config.Context)
+	}
+	return z
+}
+
+// destinationEnabler enables levels at or above both the destination's minimum
+// level and the logger's current level.
+func destinationEnabler(dest Destination, loggerLevel zapcore.LevelEnabler) zapcore.LevelEnabler {
+	minimum := dest.Level.zapLevel()
+	return zap.LevelEnablerFunc(func(l zapcore.Level) bool {
+		return l >= minimum && loggerLevel.Enabled(l)
+	})
+}
+
+// withCallerSkip returns a copy of z, sharing its state, that skips n more
+// stack frames when reporting the caller.
+func (z *Zap) withCallerSkip(n int) *Zap {
+	return &Zap{
+		logger: z.logger.WithOptions(zap.AddCallerSkip(n)),
+		Config: z.Config,
+		state:  z.state,
+		conv:   z.conv,
+		skip:   z.skip + n,
+		bound:  z.bound,
+	}
+}
+
+// SetLevel changes the minimum level of entries that are logged, ending any
+// window started by SetLevelFor.
+func (z *Zap) SetLevel(level Level) {
+	z.state.mu.Lock()
+	defer z.state.mu.Unlock()
+	if w := z.state.window; w != nil {
+		w.timer.Stop()
+		z.state.window = nil
+	}
+	z.state.level.SetLevel(level.zapLevel())
+}
+
+// SetLevelFor changes the minimum level for d and then restores the level in
+// effect before, e.g. to turn on debug logging for five minutes without
+// having to remember to turn it off. The change applies to z and every logger
+// derived from it. Calling SetLevelFor again within the window replaces the
+// level and extends the window, still restoring the original level; SetLevel
+// ends the window.
+func (z *Zap) SetLevelFor(level Level, d time.Duration) {
+	z.state.mu.Lock()
+	defer z.state.mu.Unlock()
+	w := &levelWindow{restore: z.Level()}
+	if previous := z.state.window; previous != nil {
+		previous.timer.Stop()
+		w.restore = previous.restore
+	}
+	z.state.window = w
+	w.timer = time.AfterFunc(d, func() {
+		z.state.mu.Lock()
+		defer z.state.mu.Unlock()
+		if z.state.window == w {
+			z.state.level.SetLevel(w.restore.zapLevel())
+			z.state.window = nil
+		}
+	})
+	z.state.level.SetLevel(level.zapLevel())
+}
+
+// Level returns the current minimum level of entries that are logged.
+func (z *Zap) Level() Level {
+	return fromZapLevel(z.state.level.Level())
+}
+
+// Enabled reports whether entries at level are logged. Package level
+// overrides are not considered, since they depend on the caller.
+func (z *Zap) Enabled(level Level) bool {
+	if level == DebugLevel && !debugCompiled {
+		return false
+	}
+	return z.shouldLog(level)
+}
+
+// DebugEnabled reports whether debug entries are logged.
+func (z *Zap) DebugEnabled() bool {
+	return z.Enabled(DebugLevel)
+}
+
+// AddHook registers a hook that runs on every entry before it is written.
+func (z *Zap) AddHook(hook Hook) {
+	z.state.mu.Lock()
+	defer z.state.mu.Unlock()
+	z.state.hooks = append(z.state.hooks, hook)
+}
+
+// currentHooks returns the registered hooks.
+func (z *Zap) currentHooks() []Hook {
+	z.state.mu.RLock()
+	defer z.state.mu.RUnlock()
+	return z.state.hooks
+}
+
+// zapLevel converts a Level to the equivalent zap level, defaulting to info.
+func (l Level) zapLevel() zapcore.Level {
+	switch l {
 	case DebugLevel:
-		atomicLevel.SetLevel(zap.DebugLevel)
+		return zap.DebugLevel
 	case InfoLevel:
-		atomicLevel.SetLevel(zap.InfoLevel)
+		return zap.InfoLevel
 	case WarnLevel:
-		atomicLevel.SetLevel(zap.WarnLevel)
+		return zap.WarnLevel
 	case ErrorLevel:
-		atomicLevel.SetLevel(zap.ErrorLevel)
+		return zap.ErrorLevel
 	case FatalLevel:
-		atomicLevel.SetLevel(zap.FatalLevel)
+		return zap.FatalLevel
 	default:
-		atomicLevel.SetLevel(zap.InfoLevel)
+		return zap.InfoLevel
 	}
+}
 
-	output := zapcore.AddSync(config.Output)
+// fromZapLevel converts a zap level to the equivalent Level.
+func fromZapLevel(l zapcore.Level) Level {
+	switch {
+	case l <= zap.DebugLevel:
+		return DebugLevel
+	case l == zap.InfoLevel:
+		return InfoLevel
+	case l == zap.WarnLevel:
+		return WarnLevel
+	case l == zap.ErrorLevel:
+		return ErrorLevel
+	default:
+		return FatalLevel
+	}
+}
+
+// zapClock adapts a Clock to zapcore.Clock.
+type zapClock struct {
+	Clock
+}
+
+// NewTicker implements zapcore.Clock.
+func (zapClock) NewTicker(d time.Duration) *time.Ticker {
+	return time.NewTicker(d)
+}
+
+// noopFatalHook stops zap from exiting on fatal entries so that Fatal can apply
+// Config.OnFatal and Config.ExitFunc itself.
+type noopFatalHook struct{}
+
+// OnWrite implements zapcore.CheckWriteHook.
+func (noopFatalHook) OnWrite(*zapcore.CheckedEntry, []zapcore.Field) {}
+
+// newEncoderConfig returns the encoder configuration shared by all zap-based outputs.
+func newEncoderConfig(config Config) zapcore.EncoderConfig {
+	names := config.KeyNames.withDefaults()
 	encoderConfig := zap.NewProductionEncoderConfig()
-	encoderConfig.CallerKey = "caller"
-	encoderConfig.EncodeCaller = zapcore.FullCallerEncoder
-	encoderConfig.EncodeTime = zapcore.RFC3339TimeEncoder
+	encoderConfig.TimeKey = names.Time
+	encoderConfig.LevelKey = names.Level
+	encoderConfig.MessageKey = names.Message
+	encoderConfig.CallerKey = names.Caller
+	encoderConfig.EncodeCaller = callerEncoder(config)
+	if config.CallerFunction {
+		encoderConfig.FunctionKey = names.Function
+	}
+	encoderConfig.EncodeTime = timeEncoder(config)
+	encoderConfig.EncodeLevel = levelEncoder(config)
+	return encoderConfig
+}
 
-	core := zapcore.NewCore(zapcore.NewJSONEncoder(encoderConfig), output, atomicLevel)
-	logger := zap.New(core, zap.AddCaller(), zap.AddCallerSkip(1))
+// destinationEncoderConfig returns the zap encoder configuration for a
+// destination, applying its level labels and severities.
+func destinationEncoderConfig(config Config, dest Destination) zapcore.EncoderConfig {
+	config = withLevelLabels(config, dest.LevelLabels)
+	config.Color = false
+	encoderConfig := newEncoderConfig(config)
+	if len(dest.Severities) == 0 {
+		return encoderConfig
+	}
+	severities := dest.Severities
+	encodeLabel := encoderConfig.EncodeLevel
+	encoderConfig.EncodeLevel = func(l zapcore.Level, enc zapcore.PrimitiveArrayEncoder) {
+		if severity, ok := severities[fromZapLevel(l)]; ok {
+			enc.AppendInt(severity)
+			return
+		}
+		encodeLabel(l, enc)
+	}
+	return encoderConfig
+}
 
-	if config.ExitFunc == nil {
-		config.ExitFunc = os.Exit // default to os.Exit
+// withLevelLabels returns a copy of config whose level labels are overridden by labels.
+func withLevelLabels(config Config, labels map[Level]string) Config {
+	merged := make(map[Level]string, len(config.LevelLabels)+len(labels))
+	for level, label := range config.LevelLabels {
+		merged[level] = label
 	}
+	for level, label := range labels {
+		merged[level] = label
+	}
+	config.LevelLabels = merged
+	return config
+}
 
-	return &Zap{
-		logger: logger,
-		Config: config,
+// levelEncoder returns the zap level encoder for the configured labels, case,
+// and severity mode.
+func levelEncoder(config Config) zapcore.LevelEncoder {
+	labels := config.LevelLabels
+	upper := config.UppercaseLevels
+	numeric := config.Severity == SeverityOnly
+	color := config.Color && config.Format == FormatConsole
+	return func(l zapcore.Level, enc zapcore.PrimitiveArrayEncoder) {
+		if numeric {
+			enc.AppendInt(SyslogSeverity(fromZapLevel(l)))
+			return
+		}
+		label, ok := labels[fromZapLevel(l)]
+		if !ok {
+			label = l.String()
+			if upper {
+				label = l.CapitalString()
+			}
+		}
+		if color {
+			label = levelColor(l) + label + colorReset
+		}
+		enc.AppendString(label)
 	}
 }
 
-// Debug logs a debug message with structured fields.
-func (z *Zap) Debug(msg string, fields Fields) {
-	if z.shouldLog(DebugLevel) {
-		z.logger.Debug(msg, mapToZapFields(fields)...)
+// timeEncoder returns the zap time encoder for the configured format and zone.
+func timeEncoder(config Config) zapcore.TimeEncoder {
+	format := config.TimeFormat
+	if format == "" {
+		format = TimeFormatRFC3339
+	}
+	location := config.TimeZone
+	encodeLayout := zapcore.TimeEncoderOfLayout(format)
+
+	return func(t time.Time, enc zapcore.PrimitiveArrayEncoder) {
+		if location != nil {
+			t = t.In(location)
+		}
+		if format == TimeFormatUnixMillis {
+			enc.AppendInt64(t.UnixNano() / int64(time.Millisecond))
+			return
+		}
+		encodeLayout(t, enc)
+	}
+}
+
+// callerEncoder returns the zap caller encoder for the configured CallerEncoding.
+func callerEncoder(config Config) zapcore.CallerEncoder {
+	switch config.CallerEncoding {
+	case CallerShort:
+		return zapcore.ShortCallerEncoder
+	case CallerTrimmed:
+		root := config.CallerRoot
+		if root == "" {
+			if info, ok := debug.ReadBuildInfo(); ok {
+				root = info.Main.Path
+			}
+		}
+		return trimmedCallerEncoder(root)
+	default:
+		return zapcore.FullCallerEncoder
+	}
+}
+
+// trimmedCallerEncoder renders the caller relative to root, falling back to the
+// short form when the path is outside of it.
+func trimmedCallerEncoder(root string) zapcore.CallerEncoder {
+	root = strings.TrimSuffix(root, "/") + "/"
+	return func(caller zapcore.EntryCaller, enc zapcore.PrimitiveArrayEncoder) {
+		if !caller.Defined || root == "/" {
+			zapcore.ShortCallerEncoder(caller, enc)
+			return
+		}
+		idx := strings.Index(caller.File, root)
+		if idx < 0 {
+			zapcore.ShortCallerEncoder(caller, enc)
+			return
+		}
+		enc.AppendString(caller.File[idx+len(root):] + ":" + strconv.Itoa(caller.Line))
 	}
 }
 
 // Info logs an info message with structured fields.
 func (z *Zap) Info(msg string, fields Fields) {
-	if z.shouldLog(InfoLevel) {
-		z.logger.Info(msg, mapToZapFields(fields)...)
-	}
+	z.log(InfoLevel, msg, fields, nil)
 }
 
 // Warn logs a warning message with structured fields.
 func (z *Zap) Warn(msg string, fields Fields) {
-	if z.shouldLog(WarnLevel) {
-		z.logger.Warn(msg, mapToZapFields(fields)...)
-	}
+	z.log(WarnLevel, msg, fields, nil)
 }
 
 // Error logs an error message with structured fields.
 func (z *Zap) Error(msg string, fields Fields) {
-	if z.shouldLog(ErrorLevel) {
-		z.logger.Error(msg, mapToZapFields(fields)...)
-	}
+	z.log(ErrorLevel, msg, fields, nil)
 }
 
-// Fatal logs a fatal message with structured fields and exits the application.
+// Fatal logs a fatal message with structured fields, then exits, panics, or
+// returns according to Config.OnFatal.
 func (z *Zap) Fatal(msg string, fields Fields) {
-	if z.shouldLog(FatalLevel) {
-		z.logger.Fatal(msg, mapToZapFields(fields)...)
-		z.Config.ExitFunc(1)
+	if z.log(FatalLevel, msg, fields, nil) {
+		z.afterFatal(msg, fields)
+	}
+}
+
+// InfoFields logs an info message with typed fields.
+func (z *Zap) InfoFields(msg string, fields ...Field) {
+	z.log(InfoLevel, msg, nil, fields)
+}
+
+// WarnFields logs a warning message with typed fields.
+func (z *Zap) WarnFields(msg string, fields ...Field) {
+	z.log(WarnLevel, msg, nil, fields)
+}
+
+// ErrorFields logs an error message with typed fields.
+func (z *Zap) ErrorFields(msg string, fields ...Field) {
+	z.log(ErrorLevel, msg, nil, fields)
+}
+
+// FatalFields logs a fatal message with typed fields, then exits, panics, or
+// returns according to Config.OnFatal.
+func (z *Zap) FatalFields(msg string, fields ...Field) {
+	if z.log(FatalLevel, msg, nil, fields) {
+		z.afterFatal(msg, fieldsToMap(nil, fields))
+	}
+}
+
+// afterFatal applies Config.OnFatal once a fatal entry has been written.
+func (z *Zap) afterFatal(msg string, fields Fields) {
+	if z.Config.CrashReport.Dir != "" {
+		if _, err := z.writeCrashReport(2, msg, fields); err != nil {
+			z.state.reportError(err)
+		}
+	}
+	switch z.Config.OnFatal {
+	case FatalPanic:
+		panic(msg)
+	case FatalNone:
+	default:
+		z.Config.ExitFunc(exitCode(z.Config, fields))
+	}
+}
+
+// log writes an entry with map and typed fields at the given level and reports
+// whether it was logged. It must be called directly from the public logging
+// methods so that the caller skip stays accurate.
+func (z *Zap) log(level Level, msg string, fields Fields, typed []Field) bool {
+	override, overridden := z.callerLevel()
+	if !z.levelEnabled(level, override, overridden) {
+		if level == DebugLevel && z.state.recorder != nil {
+			z.state.recorder.add(Entry{Level: level, Time: z.now(), Message: msg, Fields: fieldsToMap(fields, typed)})
+		}
+		return false
+	}
+
+	if z.Config.MessageTemplates {
+		msg, fields, typed = z.applyTemplate(msg, fields, typed)
 	}
+
+	if hooks := z.currentHooks(); len(hooks) > 0 {
+		if len(typed) > 0 {
+			fields, typed = fieldsToMap(fields, typed), nil
+		}
+		entry := entryPool.Get().(*Entry)
+		*entry = Entry{Level: level, Time: z.now(), Message: msg, Fields: fields}
+		runHooks(hooks, entry, z.state.reportError)
+		level, msg, fields = entry.Level, entry.Message, entry.Fields
+		*entry = Entry{}
+		entryPool.Put(entry)
+
+		if !z.levelEnabled(level, override, overridden) {
+			return false
+		}
+	}
+
+	if z.Config.Schema.enabled() {
+		if len(typed) > 0 {
+			fields, typed = fieldsToMap(fields, typed), nil
+		}
+		var ok bool
+		if fields, ok = z.enforceSchema(msg, fields); !ok {
+			return false
+		}
+	}
+
+	if p := z.state.pipeline; p != nil {
+		if len(typed) > 0 {
+			fields, typed = fieldsToMap(fields, typed), nil
+		}
+		written := false
+		for _, entry := range p.collect(Entry{Level: level, Time: z.now(), Message: msg, Fields: fields}) {
+			if z.levelEnabled(entry.Level, override, overridden) && z.write(entry.Level, entry.Message, entry.Fields, nil) {
+				written = true
+			}
+		}
+		return written
+	}
+	return z.write(level, msg, fields, typed)
+}
+
+// write encodes an entry that passed the level check, hooks, and pipeline and
+// hands it to the cores. It must be called directly from log, since the caller
+// skip counts its frame.
+func (z *Zap) write(level Level, msg string, fields Fields, typed []Field) bool {
+	if level >= ErrorLevel && z.state.recorder != nil {
+		z.dumpRecorder()
+	}
+	if z.state.history != nil {
+		z.state.history.add(Entry{Level: level, Time: z.now(), Message: msg, Fields: fieldsToMap(fields, typed)})
+	}
+
+	msg, truncated := z.conv.limitMessage(z.conv.sanitizeMessage(msg))
+	if len(fields)+len(typed)+z.extraFields() == 0 && !truncated {
+		z.logger.Log(level.zapLevel(), msg)
+		return true
+	}
+
+	buf := getFieldBuffer()
+	zapFields := z.conv.appendFields(*buf, fields)
+	zapFields = z.conv.appendTyped(zapFields, typed)
+	zapFields = z.conv.finish(zapFields, truncated)
+	if z.Config.Sequence {
+		zapFields = append(zapFields, zap.Uint64("seq", atomic.AddUint64(&z.state.seq, 1)))
+	}
+	if z.Config.EntryID {
+		zapFields = append(zapFields, zap.String("id", newULID(z.now())))
+	}
+	if z.Config.Severity == SeverityAdd {
+		zapFields = append(zapFields, zap.Int(severityKey, SyslogSeverity(level)))
+	}
+	if z.Config.GoroutineID {
+		zapFields = append(zapFields, zap.Uint64("goroutine", goroutineID()))
+	}
+
+	z.logger.Log(level.zapLevel(), msg, zapFields...)
+	*buf = zapFields
+	putFieldBuffer(buf)
+	return true
+}
+
+// dumpRecorder writes the entries held by the flight recorder, bypassing the
+// logger level, and marks them with a flight_recorder field. Destinations only
+// receive the entries their own level enables.
+func (z *Zap) dumpRecorder() {
+	core := z.logger.Core()
+	for _, entry := range z.state.recorder.drain() {
+		msg, truncated := z.conv.limitMessage(z.conv.sanitizeMessage(entry.Message))
+		zapFields := z.conv.finish(z.conv.appendFields(nil, entry.Fields), truncated)
+		zapFields = append(zapFields, zap.Bool("flight_recorder", true), recorderField)
+		if z.Config.Severity == SeverityAdd {
+			zapFields = append(zapFields, zap.Int(severityKey, SyslogSeverity(entry.Level)))
+		}
+		_ = core.Write(zapcore.Entry{
+			Level:   entry.Level.zapLevel(),
+			Time:    entry.Time,
+			Message: msg,
+		}, zapFields)
+	}
+}
+
+// extraFields returns the number of fields the logger adds to every entry.
+func (z *Zap) extraFields() int {
+	n := 0
+	if z.Config.Sequence {
+		n++
+	}
+	if z.Config.EntryID {
+		n++
+	}
+	if z.Config.Severity == SeverityAdd {
+		n++
+	}
+	if z.Config.GoroutineID {
+		n++
+	}
+	return n
+}
+
+// now returns the current time from the configured clock.
+func (z *Zap) now() time.Time {
+	if z.Config.Clock != nil {
+		return z.Config.Clock.Now()
+	}
+	return time.Now()
+}
+
+// Sync flushes any buffered log entries.
+func (z *Zap) Sync() error {
+	return z.logger.Sync()
 }
 
 // shouldLog determines if a log entry should be logged based on the log level.
 func (z *Zap) shouldLog(level Level) bool {
-	return level >= z.Config.Level
-}
-
-// mapToZapFields converts Fields to zap.Field with type-specific handling for better performance.
-func mapToZapFields(fields Fields) []zap.Field {
-	zapFields := make([]zap.Field, 0, len(fields))
-	for k, v := range fields {
-		switch val := v.(type) {
-		case string:
-			zapFields = append(zapFields, zap.String(k, val))
-		case int:
-			zapFields = append(zapFields, zap.Int(k, val))
-		case int64:
-			zapFields = append(zapFields, zap.Int64(k, val))
-		case float64:
-			zapFields = append(zapFields, zap.Float64(k, val))
-		case bool:
-			zapFields = append(zapFields, zap.Bool(k, val))
-		default:
-			zapFields = append(zapFields, zap.Any(k, v))
-		}
-	}
-	return zapFields
+	return z.state.level.Enabled(level.zapLevel()) && atomic.LoadUint32(&z.state.closed) == 0
 }
@@ -0,0 +1,96 @@
+package logger
+
+import (
+	"io"
+	"testing"
+)
+
+// newBenchmarkZap returns a logger writing to io.Discard at info level.
+func newBenchmarkZap() *Zap {
+	return NewZap(Config{
+		Level:    InfoLevel,
+		Output:   io.Discard,
+		ExitFunc: func(int) {},
+	})
+}
+
+// BenchmarkZap_Disabled measures a call below the configured level.
+func BenchmarkZap_Disabled(b *testing.B) {
+	zapLogger := newBenchmarkZap()
+	fields := Fields{"key": "value"}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		zapLogger.Debug("Debug message", fields)
+	}
+}
+
+// BenchmarkZap_NoFields measures an enabled call without fields.
+func BenchmarkZap_NoFields(b *testing.B) {
+	zapLogger := newBenchmarkZap()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		zapLogger.Info("Info message", nil)
+	}
+}
+
+// BenchmarkZap_Fields measures an enabled call with a handful of fields.
+func BenchmarkZap_Fields(b *testing.B) {
+	zapLogger := newBenchmarkZap()
+	fields := Fields{
+		"string":  "value",
+		"int":     42,
+		"float64": 3.14,
+		"bool":    true,
+		"any":     []string{"a", "b"},
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		zapLogger.Info("Info message", fields)
+	}
+}
+
+// BenchmarkZap_CompositeFields measures an enabled call with string maps and slices.
+func BenchmarkZap_CompositeFields(b *testing.B) {
+	zapLogger := newBenchmarkZap()
+	fields := Fields{
+		"headers": map[string]string{"Accept": "*/*", "User-Agent": "curl"},
+		"tags":    []string{"a", "b"},
+		"ports":   []int{80, 443},
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		zapLogger.Info("Info message", fields)
+	}
+}
+
+// BenchmarkZap_Parallel measures enabled calls from concurrent goroutines.
+func BenchmarkZap_Parallel(b *testing.B) {
+	zapLogger := newBenchmarkZap()
+	fields := Fields{"key": "value", "n": 1}
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			zapLogger.Info("Info message", fields)
+		}
+	})
+}
+
+// BenchmarkZap_TypedFields measures an enabled call with typed fields.
+func BenchmarkZap_TypedFields(b *testing.B) {
+	zapLogger := newBenchmarkZap()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		zapLogger.InfoFields("Info message",
+			String("string", "value"),
+			Int("int", 42),
+			Float64("float64", 3.14),
+			Bool("bool", true),
+		)
+	}
+}
@@ -2,8 +2,17 @@ package logger
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
 	"testing"
+	"time"
+
+	"go.uber.org/zap/zapcore"
 )
 
 // TestNewZap tests the NewZap function.
@@ -32,8 +41,90 @@ func TestNewZap(t *testing.T) {
 	}
 }
 
+// TestNewZap_DefaultOutput tests that a nil Output defaults to stderr or stdout.
+func TestNewZap_DefaultOutput(t *testing.T) {
+	if output := NewZap(Config{}).Config.Output; output != os.Stderr {
+		t.Errorf("Expected Output to default to os.Stderr, got %v", output)
+	}
+	if output := NewZap(Config{Stdout: true}).Config.Output; output != os.Stdout {
+		t.Errorf("Expected Output to be os.Stdout, got %v", output)
+	}
+}
+
+// TestNewZapE tests that invalid configurations and outputs are reported.
+func TestNewZapE(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	zapLogger, err := NewZapE(Config{OutputPath: path})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	zapLogger.Info("Info message", nil)
+	if data, _ := os.ReadFile(path); !bytes.Contains(data, []byte("Info message")) {
+		t.Errorf("Expected %s to contain %s", data, "Info message")
+	}
+
+	invalid := []Config{
+		{OutputPath: filepath.Join(t.TempDir(), "missing", "app.log")},
+		{Level: Level(42)},
+		{OnFatal: FatalBehavior(9)},
+		{FlightRecorder: -1},
+	}
+	for _, config := range invalid {
+		if _, err := NewZapE(config); err == nil {
+			t.Errorf("Expected an error for %+v, got nil", config)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected MustZap to panic")
+		}
+	}()
+	MustZap(Config{Level: Level(42)})
+}
+
+// TestZap_Destinations tests that each destination only receives entries at or above its level.
+func TestZap_Destinations(t *testing.T) {
+	requireDebug(t)
+	file := new(bytes.Buffer)
+	stdout := new(bytes.Buffer)
+	pager := new(bytes.Buffer)
+	zapLogger := NewZap(Config{
+		Level: DebugLevel,
+		Destinations: []Destination{
+			{Output: file, Level: DebugLevel},
+			{Output: stdout, Level: InfoLevel},
+			{Output: pager, Level: ErrorLevel},
+		},
+	})
+	if zapLogger.Config.Output != nil {
+		t.Errorf("Expected no default output when destinations are set, got %v", zapLogger.Config.Output)
+	}
+
+	zapLogger.Debug("Debug message", nil)
+	zapLogger.Info("Info message", nil)
+	zapLogger.Error("Error message", nil)
+
+	for _, test := range []struct {
+		name     string
+		output   *bytes.Buffer
+		expected int
+	}{{"file", file, 3}, {"stdout", stdout, 2}, {"pager", pager, 1}} {
+		if n := bytes.Count(test.output.Bytes(), []byte("\n")); n != test.expected {
+			t.Errorf("Expected %d entries in %s, got %d", test.expected, test.name, n)
+		}
+	}
+
+	zapLogger.SetLevel(ErrorLevel)
+	zapLogger.Info("Info message", nil)
+	if n := bytes.Count(file.Bytes(), []byte("\n")); n != 3 {
+		t.Errorf("Expected the logger level to apply to destinations, got %d entries", n)
+	}
+}
+
 // TestZap_Debug tests the Debug method.
 func TestZap_Debug(t *testing.T) {
+	requireDebug(t)
 	buffer := new(bytes.Buffer)
 	config := Config{
 		Level:    DebugLevel,
@@ -100,6 +191,311 @@ func TestZap_Error(t *testing.T) {
 	}
 }
 
+// TestZap_Fatal tests the Fatal method with each OnFatal behavior.
+func TestZap_Fatal(t *testing.T) {
+	t.Run("exit", func(t *testing.T) {
+		buffer := new(bytes.Buffer)
+		exitCode := -1
+		zapLogger := NewZap(Config{
+			Level:    InfoLevel,
+			Output:   buffer,
+			ExitFunc: func(code int) { exitCode = code },
+		})
+
+		zapLogger.Fatal("Fatal message", Fields{"key": "value"})
+		if exitCode != 1 {
+			t.Errorf("Expected ExitFunc to be called with 1, got %d", exitCode)
+		}
+		if !bytes.Contains(buffer.Bytes(), []byte("Fatal message")) {
+			t.Errorf("Expected %s to contain %s", buffer.String(), "Fatal message")
+		}
+	})
+
+	t.Run("panic", func(t *testing.T) {
+		zapLogger := NewZap(Config{
+			Level:    InfoLevel,
+			Output:   new(bytes.Buffer),
+			ExitFunc: func(int) { t.Errorf("Expected ExitFunc not to be called") },
+			OnFatal:  FatalPanic,
+		})
+
+		defer func() {
+			if r := recover(); r != "Fatal message" {
+				t.Errorf("Expected panic with %q, got %v", "Fatal message", r)
+			}
+		}()
+		zapLogger.Fatal("Fatal message", nil)
+	})
+
+	t.Run("none", func(t *testing.T) {
+		buffer := new(bytes.Buffer)
+		zapLogger := NewZap(Config{
+			Level:    InfoLevel,
+			Output:   buffer,
+			ExitFunc: func(int) { t.Errorf("Expected ExitFunc not to be called") },
+			OnFatal:  FatalNone,
+		})
+
+		zapLogger.Fatal("Fatal message", nil)
+		if !bytes.Contains(buffer.Bytes(), []byte("Fatal message")) {
+			t.Errorf("Expected %s to contain %s", buffer.String(), "Fatal message")
+		}
+	})
+}
+
+// TestZap_FatalExitCode tests that the exit code can be configured.
+func TestZap_FatalExitCode(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   Config
+		fields   Fields
+		expected int
+	}{
+		{"default", Config{}, nil, 1},
+		{"fixed", Config{ExitCode: 3}, nil, 3},
+		{"derived", Config{ExitCode: 3, ExitCodeFunc: func(f Fields) int { return f["code"].(int) }}, Fields{"code": 78}, 78},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			exitCode := -1
+			test.config.Output = new(bytes.Buffer)
+			test.config.ExitFunc = func(code int) { exitCode = code }
+			zapLogger := NewZap(test.config)
+
+			zapLogger.Fatal("Fatal message", test.fields)
+			if exitCode != test.expected {
+				t.Errorf("Expected exit code %d, got %d", test.expected, exitCode)
+			}
+		})
+	}
+}
+
+// TestZap_Caller tests the caller reporting options.
+func TestZap_Caller(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	NewZap(Config{Output: buffer}).Info("Info message", nil)
+	if !bytes.Contains(buffer.Bytes(), []byte("zap_test.go")) {
+		t.Errorf("Expected %s to contain the caller zap_test.go", buffer.String())
+	}
+
+	buffer.Reset()
+	NewZap(Config{Output: buffer, DisableCaller: true}).Info("Info message", nil)
+	if bytes.Contains(buffer.Bytes(), []byte(`"caller"`)) {
+		t.Errorf("Expected %s not to contain a caller", buffer.String())
+	}
+
+	buffer.Reset()
+	_, _, line, _ := runtime.Caller(0)
+	logHelper(NewZap(Config{Output: buffer, CallerSkip: 1}))
+	expected := fmt.Sprintf("zap_test.go:%d", line+1)
+	if !bytes.Contains(buffer.Bytes(), []byte(expected)) {
+		t.Errorf("Expected %s to contain %s", buffer.String(), expected)
+	}
+}
+
+// TestZap_CallerEncoding tests the caller path encodings.
+func TestZap_CallerEncoding(t *testing.T) {
+	_, file, _, _ := runtime.Caller(0)
+	dir := file[:strings.LastIndex(file, "/")]
+	parent := dir[:strings.LastIndex(dir, "/")]
+	pkg := dir[len(parent)+1:]
+
+	tests := []struct {
+		name     string
+		config   Config
+		expected string
+	}{
+		{"full", Config{CallerEncoding: CallerFull}, `"caller":"` + file + ":"},
+		{"short", Config{CallerEncoding: CallerShort}, `"caller":"` + pkg + "/zap_test.go:"},
+		{"trimmed", Config{CallerEncoding: CallerTrimmed, CallerRoot: dir}, `"caller":"zap_test.go:`},
+		{"trimmed outside root", Config{CallerEncoding: CallerTrimmed, CallerRoot: "/elsewhere"}, `"caller":"` + pkg + "/zap_test.go:"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			buffer := new(bytes.Buffer)
+			test.config.Output = buffer
+			NewZap(test.config).Info("Info message", nil)
+			if !bytes.Contains(buffer.Bytes(), []byte(test.expected)) {
+				t.Errorf("Expected %s to contain %s", buffer.String(), test.expected)
+			}
+		})
+	}
+}
+
+// TestZap_CallerFunction tests that the calling function can be recorded.
+func TestZap_CallerFunction(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	NewZap(Config{Output: buffer}).Info("Info message", nil)
+	if bytes.Contains(buffer.Bytes(), []byte(`"function"`)) {
+		t.Errorf("Expected %s not to contain a function", buffer.String())
+	}
+
+	buffer.Reset()
+	NewZap(Config{Output: buffer, CallerFunction: true}).Info("Info message", nil)
+	expected := `"function":"github.com/ralonr/logger.TestZap_CallerFunction"`
+	if !bytes.Contains(buffer.Bytes(), []byte(expected)) {
+		t.Errorf("Expected %s to contain %s", buffer.String(), expected)
+	}
+}
+
+// TestTimeEncoder tests the timestamp formats and time zone conversion.
+func TestTimeEncoder(t *testing.T) {
+	ts := time.Date(2024, 3, 1, 12, 30, 45, 123456789, time.UTC)
+	tokyo := time.FixedZone("JST", 9*60*60)
+
+	tests := []struct {
+		name     string
+		config   Config
+		expected string
+	}{
+		{"default", Config{TimeZone: time.UTC}, `"2024-03-01T12:30:45Z"`},
+		{"nano", Config{TimeFormat: TimeFormatRFC3339Nano, TimeZone: time.UTC}, `"2024-03-01T12:30:45.123456789Z"`},
+		{"millis", Config{TimeFormat: TimeFormatUnixMillis}, "1709296245123"},
+		{"layout", Config{TimeFormat: "2006-01-02 15:04:05", TimeZone: tokyo}, `"2024-03-01 21:30:45"`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			enc := zapcore.NewJSONEncoder(zapcore.EncoderConfig{TimeKey: "ts", EncodeTime: timeEncoder(test.config)})
+			buf, err := enc.EncodeEntry(zapcore.Entry{Time: ts}, nil)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			expected := `"ts":` + test.expected
+			if !strings.Contains(buf.String(), expected) {
+				t.Errorf("Expected %s to contain %s", buf.String(), expected)
+			}
+		})
+	}
+}
+
+// TestZap_LevelLabels tests that level strings can be overridden globally and per destination.
+func TestZap_LevelLabels(t *testing.T) {
+	main := new(bytes.Buffer)
+	dest := new(bytes.Buffer)
+	zapLogger := NewZap(Config{
+		Level:           InfoLevel,
+		Output:          main,
+		UppercaseLevels: true,
+		LevelLabels:     map[Level]string{WarnLevel: "WARNING"},
+		Destinations: []Destination{
+			{Output: dest, LevelLabels: map[Level]string{InfoLevel: "notice"}},
+		},
+	})
+
+	zapLogger.Info("Info message", nil)
+	zapLogger.Warn("Warn message", nil)
+
+	for _, expected := range []string{`"level":"INFO"`, `"level":"WARNING"`} {
+		if !bytes.Contains(main.Bytes(), []byte(expected)) {
+			t.Errorf("Expected %s to contain %s", main.String(), expected)
+		}
+	}
+	for _, expected := range []string{`"level":"notice"`, `"level":"WARNING"`} {
+		if !bytes.Contains(dest.Bytes(), []byte(expected)) {
+			t.Errorf("Expected %s to contain %s", dest.String(), expected)
+		}
+	}
+}
+
+// TestZap_DestinationSeverities tests that a destination can write numeric severities.
+func TestZap_DestinationSeverities(t *testing.T) {
+	main := new(bytes.Buffer)
+	dest := new(bytes.Buffer)
+	zapLogger := NewZap(Config{
+		Level:  InfoLevel,
+		Output: main,
+		Destinations: []Destination{
+			{Output: dest, Severities: map[Level]int{WarnLevel: 30}, LevelLabels: map[Level]string{InfoLevel: "INFO"}},
+		},
+	})
+
+	zapLogger.Info("Info message", nil)
+	zapLogger.Warn("Warn message", nil)
+
+	for _, expected := range []string{`"level":"INFO"`, `"level":30`} {
+		if !bytes.Contains(dest.Bytes(), []byte(expected)) {
+			t.Errorf("Expected %s to contain %s", dest.String(), expected)
+		}
+	}
+	if !bytes.Contains(main.Bytes(), []byte(`"level":"warn"`)) {
+		t.Errorf("Expected the main output to be unchanged, got %s", main.String())
+	}
+}
+
+// TestParseLevel tests parsing level names.
+func TestParseLevel(t *testing.T) {
+	for name, expected := range map[string]Level{"debug": DebugLevel, "INFO": InfoLevel, "warning": WarnLevel, "Error": ErrorLevel, "fatal": FatalLevel} {
+		if level, err := ParseLevel(name); err != nil || level != expected {
+			t.Errorf("Expected %q to parse as %d, got %d (%v)", name, expected, level, err)
+		}
+	}
+	if _, err := ParseLevel("verbose"); err == nil {
+		t.Errorf("Expected an error for an unknown level")
+	}
+}
+
+// TestZap_SequenceAndEntryID tests that entries can be stamped with a sequence number and ID.
+func TestZap_SequenceAndEntryID(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	zapLogger := NewZap(Config{Level: InfoLevel, Output: buffer, Sequence: true, EntryID: true})
+
+	zapLogger.Info("first", nil)
+	zapLogger.Debug("skipped", nil)
+	zapLogger.Info("second", nil)
+
+	lines := bytes.Split(bytes.TrimSpace(buffer.Bytes()), []byte("\n"))
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(lines))
+	}
+	for i, line := range lines {
+		var entry map[string]interface{}
+		if err := json.Unmarshal(line, &entry); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if seq := entry["seq"]; seq != float64(i+1) {
+			t.Errorf("Expected seq %d, got %v", i+1, seq)
+		}
+		if id, _ := entry["id"].(string); len(id) != 26 {
+			t.Errorf("Expected a 26 character id, got %q", id)
+		}
+	}
+}
+
+// TestZap_Clock tests that entry timestamps come from the configured clock.
+func TestZap_Clock(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	clock := fixedClock(time.Date(2024, 3, 1, 12, 30, 45, 0, time.UTC))
+	zapLogger := NewZap(Config{Output: buffer, Clock: clock, TimeZone: time.UTC})
+
+	zapLogger.Info("Info message", nil)
+	expected := `"ts":"2024-03-01T12:30:45Z"`
+	if !bytes.Contains(buffer.Bytes(), []byte(expected)) {
+		t.Errorf("Expected %s to contain %s", buffer.String(), expected)
+	}
+}
+
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time {
+	return time.Time(c)
+}
+
+// logHelper wraps the logger the way application helpers do.
+// requireDebug skips t when the logger_nodebug tag compiles debug logging out.
+func requireDebug(t *testing.T) {
+	t.Helper()
+	if !debugCompiled {
+		t.Skip("debug logging is compiled out")
+	}
+}
+
+func logHelper(l Logger) {
+	l.Info("Info message", nil)
+}
+
 // TestShouldLog tests the shouldLog function.
 func TestShouldLog(t *testing.T) {
 	tests := []struct {
@@ -133,6 +529,85 @@ func TestShouldLog(t *testing.T) {
 	}
 }
 
+// TestZap_SetLevel tests that the level can be changed at runtime, concurrently with logging.
+func TestZap_SetLevel(t *testing.T) {
+	zapLogger := NewZap(Config{Level: InfoLevel, Output: io.Discard})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			zapLogger.Debug("Debug message", nil)
+		}
+	}()
+	zapLogger.SetLevel(DebugLevel)
+	<-done
+
+	if level := zapLogger.Level(); level != DebugLevel {
+		t.Errorf("Expected level %v, got %v", DebugLevel, level)
+	}
+	if !zapLogger.shouldLog(DebugLevel) {
+		t.Errorf("Expected debug entries to be logged after SetLevel")
+	}
+}
+
+// TestZap_SetLevelFor tests that a temporary level is restored across child loggers.
+func TestZap_SetLevelFor(t *testing.T) {
+	requireDebug(t)
+	zapLogger := NewZap(Config{Level: InfoLevel, Output: io.Discard})
+	child := zapLogger.With(Fields{"component": "db"})
+
+	zapLogger.SetLevelFor(DebugLevel, time.Hour)
+	zapLogger.SetLevelFor(DebugLevel, 10*time.Millisecond)
+	if !child.DebugEnabled() {
+		t.Errorf("Expected debug entries to be logged by the child within the window")
+	}
+	for deadline := time.Now().Add(time.Second); zapLogger.Level() != InfoLevel && time.Now().Before(deadline); {
+		time.Sleep(time.Millisecond)
+	}
+	if child.DebugEnabled() {
+		t.Errorf("Expected the child to return to info")
+	}
+
+	zapLogger.SetLevelFor(DebugLevel, 10*time.Millisecond)
+	zapLogger.SetLevel(WarnLevel)
+	time.Sleep(30 * time.Millisecond)
+	if level := zapLogger.Level(); level != WarnLevel {
+		t.Errorf("Expected SetLevel to end the window, got %v", level)
+	}
+}
+
+// TestEnabled tests the level-enabled checks.
+func TestEnabled(t *testing.T) {
+	zapLogger := NewZap(Config{Level: WarnLevel, Output: io.Discard})
+	if zapLogger.DebugEnabled() || zapLogger.Enabled(InfoLevel) {
+		t.Errorf("Expected debug and info to be disabled")
+	}
+	if !Enabled(zapLogger, WarnLevel) || !Enabled(zapLogger, ErrorLevel) {
+		t.Errorf("Expected warn and error to be enabled")
+	}
+
+	observed, _ := Observe(Config{Level: ErrorLevel})
+	if Enabled(observed, WarnLevel) {
+		t.Errorf("Expected warn to be disabled on the observer")
+	}
+}
+
+// TestZap_DisabledLevelAllocs tests that calls below the level do not allocate.
+func TestZap_DisabledLevelAllocs(t *testing.T) {
+	zapLogger := NewZap(Config{Level: ErrorLevel, Output: io.Discard, Sequence: true})
+	fields := Fields{"key": "value", "n": 1}
+
+	allocs := testing.AllocsPerRun(100, func() {
+		zapLogger.Debug("Debug message", fields)
+		zapLogger.Info("Info message", fields)
+		zapLogger.Warn("Warn message", fields)
+	})
+	if allocs != 0 {
+		t.Errorf("Expected 0 allocations for disabled levels, got %v", allocs)
+	}
+}
+
 // TestMapToZapFields tests the mapToZapFields function.
 func TestMapToZapFields(t *testing.T) {
 	fields := Fields{
@@ -0,0 +1,115 @@
+package logger
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// ZapOptions holds configuration specific to the zap backend.
+type ZapOptions struct {
+	// Sampling, if set, limits the entries logged per second for repeated
+	// messages.
+	Sampling *SamplingConfig
+	// LevelSampling keeps the given fraction, between 0 and 1, of the entries
+	// at each listed level, chosen at random, e.g. DebugLevel: 0.01 and
+	// InfoLevel: 0.1; levels that are not listed are never dropped by it.
+	// It applies after the level check and hooks, before Sampling.
+	LevelSampling map[Level]float64
+	// Options are applied to the underlying *zap.Logger after the logger's own,
+	// e.g. zap.Development() or zap.Hooks(...).
+	Options []zap.Option
+}
+
+// SamplingConfig caps the number of entries with the same level and message
+// logged per Tick: the first First entries are logged, then every
+// Thereafter-th entry. Sampling applies after the level check and hooks.
+type SamplingConfig struct {
+	// Tick is the sampling interval; defaults to one second.
+	Tick time.Duration
+	// First is the number of entries logged per tick before sampling starts.
+	First int
+	// Thereafter logs every Thereafter-th entry after First; zero drops them all.
+	Thereafter int
+}
+
+// validate checks that the options are consistent.
+func (o ZapOptions) validate() error {
+	if s := o.Sampling; s != nil {
+		if s.Tick < 0 {
+			return fmt.Errorf("invalid sampling tick %s", s.Tick)
+		}
+		if s.First < 0 || s.Thereafter < 0 {
+			return fmt.Errorf("invalid sampling first %d, thereafter %d", s.First, s.Thereafter)
+		}
+	}
+	for level, rate := range o.LevelSampling {
+		if level < DebugLevel || level > FatalLevel {
+			return fmt.Errorf("invalid sampling level %d", level)
+		}
+		if rate < 0 || rate > 1 {
+			return fmt.Errorf("invalid sampling rate %g for level %d", rate, level)
+		}
+	}
+	for i, option := range o.Options {
+		if option == nil {
+			return fmt.Errorf("zap option %d is nil", i)
+		}
+	}
+	return nil
+}
+
+// wrapCore applies the options that wrap the core, such as sampling.
+func (o ZapOptions) wrapCore(core zapcore.Core) zapcore.Core {
+	if len(o.LevelSampling) > 0 {
+		core = newLevelSampler(core, o.LevelSampling, rand.Float64)
+	}
+	if s := o.Sampling; s != nil {
+		tick := s.Tick
+		if tick <= 0 {
+			tick = time.Second
+		}
+		core = zapcore.NewSamplerWithOptions(core, tick, s.First, s.Thereafter)
+	}
+	return core
+}
+
+// levelSampler drops a random share of the entries at each level.
+type levelSampler struct {
+	zapcore.Core
+	// rates holds the fraction kept per zap level, offset by DebugLevel.
+	rates  [zapcore.FatalLevel - zapcore.DebugLevel + 1]float64
+	random func() float64
+}
+
+// newLevelSampler returns a core keeping the fraction rates[l] of the entries
+// at each level l of core, drawing from random.
+func newLevelSampler(core zapcore.Core, rates map[Level]float64, random func() float64) zapcore.Core {
+	s := &levelSampler{Core: core, random: random}
+	for i := range s.rates {
+		s.rates[i] = 1
+	}
+	for level, rate := range rates {
+		s.rates[level.zapLevel()-zapcore.DebugLevel] = rate
+	}
+	return s
+}
+
+// With returns a child sampler with the same rates.
+func (s *levelSampler) With(fields []zapcore.Field) zapcore.Core {
+	return &levelSampler{Core: s.Core.With(fields), rates: s.rates, random: s.random}
+}
+
+// Check passes the entry on to the wrapped core if it is sampled.
+func (s *levelSampler) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	i := entry.Level - zapcore.DebugLevel
+	if i >= 0 && int(i) < len(s.rates) {
+		if rate := s.rates[i]; rate < 1 && s.random() >= rate {
+			return checked
+		}
+	}
+	return s.Core.Check(entry, checked)
+}
@@ -0,0 +1,104 @@
+package logger
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// TestZapOptions_Sampling tests that repeated entries are sampled.
+func TestZapOptions_Sampling(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	zapLogger := NewZap(Config{
+		Level:  InfoLevel,
+		Output: buffer,
+		Zap:    ZapOptions{Sampling: &SamplingConfig{First: 2, Thereafter: 3}},
+	})
+
+	for i := 0; i < 8; i++ {
+		zapLogger.Info("Info message", nil)
+	}
+	zapLogger.Info("Other message", nil)
+
+	// The first 2, then the 5th and 8th repeat, plus the other message.
+	if lines := strings.Count(buffer.String(), "\n"); lines != 5 {
+		t.Errorf("Expected 5 entries, got %d: %s", lines, buffer.String())
+	}
+}
+
+// TestZapOptions_Options tests that extra zap options are applied.
+func TestZapOptions_Options(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	hooked := 0
+	zapLogger := NewZap(Config{
+		Level:  InfoLevel,
+		Output: buffer,
+		Zap: ZapOptions{Options: []zap.Option{zap.Hooks(func(zapcore.Entry) error {
+			hooked++
+			return nil
+		})}},
+	})
+
+	zapLogger.Info("Info message", nil)
+	if hooked != 1 {
+		t.Errorf("Expected the zap hook to run once, got %d", hooked)
+	}
+}
+
+// TestZapOptions_Validate tests that invalid options are rejected.
+func TestZapOptions_Validate(t *testing.T) {
+	for _, options := range []ZapOptions{
+		{Sampling: &SamplingConfig{First: -1}},
+		{Sampling: &SamplingConfig{Tick: -1}},
+		{Options: []zap.Option{nil}},
+	} {
+		if _, err := NewZapE(Config{Output: new(bytes.Buffer), Zap: options}); err == nil {
+			t.Errorf("Expected an error for %+v", options)
+		}
+	}
+}
+
+// TestZapOptions_LevelSampling tests that each level is sampled at its own rate.
+func TestZapOptions_LevelSampling(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	zapLogger := NewZap(Config{
+		Level:  DebugLevel,
+		Output: buffer,
+		Zap:    ZapOptions{LevelSampling: map[Level]float64{DebugLevel: 0, InfoLevel: 1}},
+	})
+
+	for i := 0; i < 10; i++ {
+		zapLogger.Debug("Debug message", nil)
+		zapLogger.Info("Info message", nil)
+		zapLogger.Warn("Warn message", nil)
+	}
+	if n := strings.Count(buffer.String(), "Debug message"); n != 0 {
+		t.Errorf("Expected debug entries to be dropped, got %d", n)
+	}
+	if n := strings.Count(buffer.String(), "Info message") + strings.Count(buffer.String(), "Warn message"); n != 20 {
+		t.Errorf("Expected all info and warn entries, got %d", n)
+	}
+
+	draws := []float64{0.05, 0.5, 0.09, 0.95}
+	core := newLevelSampler(zapcore.NewCore(zapcore.NewJSONEncoder(zapcore.EncoderConfig{}), zapcore.AddSync(new(bytes.Buffer)), zapcore.DebugLevel), map[Level]float64{InfoLevel: 0.1}, func() float64 {
+		draw := draws[0]
+		draws = draws[1:]
+		return draw
+	})
+	kept := 0
+	for i := 0; i < 4; i++ {
+		if core.Check(zapcore.Entry{Level: zapcore.InfoLevel}, nil) != nil {
+			kept++
+		}
+	}
+	if kept != 2 {
+		t.Errorf("Expected draws below the rate to be kept, got %d", kept)
+	}
+
+	if _, err := NewZapE(Config{Output: buffer, Zap: ZapOptions{LevelSampling: map[Level]float64{InfoLevel: 1.5}}}); err == nil {
+		t.Errorf("Expected an error for an invalid rate")
+	}
+}